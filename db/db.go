@@ -3,14 +3,31 @@ package db
 import (
 	"database/sql"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/mattn/go-sqlite3"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
 var DB *sql.DB
 
 func InitDB() {
+	// Writes read the row they audit before changing it, take the write lock up front
+	// so concurrent transactions wait for each other instead of failing with SQLITE_BUSY
+	OpenDB("golang-event.db?_txlock=immediate")
+}
+
+// OpenDB connects to the given SQLite data source and creates the tables,
+// tests use it with an in-memory database
+func OpenDB(dataSourceName string) {
 	var err error
-	DB, err = sql.Open("sqlite3", "golang-event.db")
+	// otelsql wraps the driver so every statement run with a context gets its own span
+	DB, err = otelsql.Open("sqlite3", dataSourceName,
+		otelsql.WithAttributes(semconv.DBSystemNameSQLite),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+		}),
+	)
 
 	if err != nil {
 		panic("Failed to connect to database")
@@ -21,6 +38,13 @@ func InitDB() {
 
 	// Create tables
 	createTables()
+
+	// Add columns introduced after the tables were first created
+	migrateColumns()
+	migrateIndexes()
+
+	// Full-text index of events, when SQLite supports it
+	createSearchIndex()
 }
 
 func createTables() {
@@ -71,4 +95,259 @@ func createTables() {
 	if err != nil {
 		panic("Failed to create registration table")
 	}
+
+	// Outbox of messages for users, a sender marks rows with sent_at once delivered
+	createNotificationTable := `
+	CREATE TABLE IF NOT EXISTS notifications (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		event_id INTEGER,
+		kind TEXT NOT NULL,
+		message TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		sent_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id),
+		FOREIGN KEY(event_id) REFERENCES events(id)
+	)
+	`
+
+	_, err = DB.Exec(createNotificationTable)
+
+	if err != nil {
+		panic("Failed to create notification table")
+	}
+
+	// Changes to single occurrences of recurring events, keyed by the start the rule gives them (UTC)
+	createOccurrenceTable := `
+	CREATE TABLE IF NOT EXISTS event_occurrences (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		occurrence_start DATETIME NOT NULL,
+		name TEXT,
+		description TEXT,
+		location TEXT,
+		datetime DATETIME,
+		cancelled INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(event_id, occurrence_start),
+		FOREIGN KEY(event_id) REFERENCES events(id)
+	)
+	`
+
+	_, err = DB.Exec(createOccurrenceTable)
+
+	if err != nil {
+		panic("Failed to create event occurrence table")
+	}
+
+	// Secret of each user's calendar feed, only a SHA-256 of the token is kept
+	createCalendarFeedTable := `
+	CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+		user_id INTEGER PRIMARY KEY,
+		token_hash TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	)
+	`
+
+	_, err = DB.Exec(createCalendarFeedTable)
+
+	if err != nil {
+		panic("Failed to create calendar feed table")
+	}
+
+	// Places shared by events, events reference them with venue_id
+	createVenueTable := `
+	CREATE TABLE IF NOT EXISTS venues (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		address_street TEXT NOT NULL DEFAULT '',
+		address_city TEXT NOT NULL DEFAULT '',
+		address_region TEXT NOT NULL DEFAULT '',
+		address_postal_code TEXT NOT NULL DEFAULT '',
+		address_country TEXT NOT NULL DEFAULT '',
+		latitude REAL,
+		longitude REAL,
+		capacity INTEGER,
+		accessibility_notes TEXT NOT NULL DEFAULT '',
+		user_id INTEGER NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	)
+	`
+
+	_, err = DB.Exec(createVenueTable)
+
+	if err != nil {
+		panic("Failed to create venue table")
+	}
+
+	// Topics events are browsed by, managed by admins
+	createCategoryTable := `
+	CREATE TABLE IF NOT EXISTS categories (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		slug TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	)
+	`
+
+	_, err = DB.Exec(createCategoryTable)
+
+	if err != nil {
+		panic("Failed to create category table")
+	}
+
+	// Free-form tags, shared by every event using the same (lowercase) name
+	createTagTables := `
+	CREATE TABLE IF NOT EXISTS tags (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE IF NOT EXISTS event_tags (
+		event_id INTEGER NOT NULL,
+		tag_id INTEGER NOT NULL,
+		PRIMARY KEY(event_id, tag_id),
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(tag_id) REFERENCES tags(id)
+	);
+	CREATE INDEX IF NOT EXISTS event_tags_tag_id ON event_tags (tag_id);
+	`
+
+	_, err = DB.Exec(createTagTables)
+
+	if err != nil {
+		panic("Failed to create tag tables")
+	}
+
+	// Communities hosting events, each a tenant of its own with members and settings
+	createOrganizationTables := `
+	CREATE TABLE IF NOT EXISTS organizations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		slug TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		logo_url TEXT NOT NULL DEFAULT '',
+		primary_color TEXT NOT NULL DEFAULT '',
+		default_time_zone TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS organization_members (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		organization_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		role TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		UNIQUE(organization_id, user_id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	CREATE INDEX IF NOT EXISTS organization_members_user_id ON organization_members (user_id);
+	`
+
+	_, err = DB.Exec(createOrganizationTables)
+
+	if err != nil {
+		panic("Failed to create organization tables")
+	}
+
+	// Co-organizers and check-in staff of an event, its owner is events.user_id
+	createEventMemberTable := `
+	CREATE TABLE IF NOT EXISTS event_members (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		role TEXT NOT NULL,
+		invited_by INTEGER,
+		created_at DATETIME NOT NULL,
+		UNIQUE(event_id, user_id),
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	CREATE INDEX IF NOT EXISTS event_members_user_id ON event_members (user_id);
+	`
+
+	_, err = DB.Exec(createEventMemberTable)
+
+	if err != nil {
+		panic("Failed to create event member table")
+	}
+
+	// Users who may see and register to a private event
+	createEventInvitationTable := `
+	CREATE TABLE IF NOT EXISTS event_invitations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		invited_by INTEGER NOT NULL,
+		created_at DATETIME NOT NULL,
+		UNIQUE(event_id, user_id),
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	CREATE INDEX IF NOT EXISTS event_invitations_user_id ON event_invitations (user_id);
+	`
+
+	_, err = DB.Exec(createEventInvitationTable)
+
+	if err != nil {
+		panic("Failed to create event invitation table")
+	}
+
+	// Every RSVP status change, kept after the registration is removed. An empty status is no registration.
+	createRSVPChangeTable := `
+	CREATE TABLE IF NOT EXISTS rsvp_changes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		occurrence_start DATETIME,
+		from_status TEXT NOT NULL,
+		to_status TEXT NOT NULL,
+		changed_by INTEGER,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	CREATE INDEX IF NOT EXISTS rsvp_changes_event_id ON rsvp_changes (event_id, created_at);
+	`
+
+	_, err = DB.Exec(createRSVPChangeTable)
+
+	if err != nil {
+		panic("Failed to create RSVP change table")
+	}
+
+	// Who changed what, rows are only ever inserted (enforced by the triggers below)
+	createAuditLogTable := `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		actor_id INTEGER,
+		action TEXT NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_id INTEGER NOT NULL,
+		before TEXT,
+		after TEXT,
+		request_id TEXT NOT NULL DEFAULT '',
+		ip TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS audit_log_entity ON audit_log (entity_type, entity_id);
+	CREATE INDEX IF NOT EXISTS audit_log_actor ON audit_log (actor_id);
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit_log is append-only');
+	END;
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit_log is append-only');
+	END;
+	`
+
+	_, err = DB.Exec(createAuditLogTable)
+
+	if err != nil {
+		panic("Failed to create audit log table")
+	}
 }
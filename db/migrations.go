@@ -0,0 +1,229 @@
+package db
+
+import "fmt"
+
+// column added to a table created by an older version of the app
+type columnMigration struct {
+	table      string
+	column     string
+	definition string
+
+	// optional statement filling the new column for existing rows
+	backfill string
+}
+
+// Columns added after the initial schema, applied in order on every start.
+// CREATE TABLE IF NOT EXISTS never changes an existing table, so new columns go here.
+var columnMigrations = []columnMigration{
+	{
+		table:      "events",
+		column:     "version",
+		definition: "INTEGER NOT NULL DEFAULT 1",
+	},
+	{
+		table:      "events",
+		column:     "updated_at",
+		definition: "DATETIME",
+		backfill:   "UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL",
+	},
+	{
+		// events created before statuses existed were public, so they start out published
+		table:      "events",
+		column:     "status",
+		definition: "TEXT NOT NULL DEFAULT 'published'",
+	},
+	{
+		table:      "events",
+		column:     "cancellation_reason",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		// NULL unless the event is soft deleted
+		table:      "events",
+		column:     "deleted_at",
+		definition: "DATETIME",
+	},
+	{
+		table:      "users",
+		column:     "role",
+		definition: "TEXT NOT NULL DEFAULT 'user'",
+	},
+	{
+		// RRULE value, empty for one-off events
+		table:      "events",
+		column:     "recurrence_rule",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		// JSON array of excluded occurrence starts
+		table:      "events",
+		column:     "recurrence_exdates",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		// NULL registers to every occurrence (or to a one-off event)
+		table:      "registrations",
+		column:     "occurrence_start",
+		definition: "DATETIME",
+	},
+	{
+		// Start times used to keep the offset they were sent with, they are compared as text from now on
+		// so existing ones are converted to UTC (in the format the driver writes) once, when zones arrive
+		table:      "events",
+		column:     "time_zone",
+		definition: "TEXT NOT NULL DEFAULT 'UTC'",
+		backfill:   "UPDATE events SET datetime = strftime('%Y-%m-%d %H:%M:%S', datetime) || '+00:00'",
+	},
+	{
+		// Events created before ends existed last an hour
+		table:      "events",
+		column:     "end_datetime",
+		definition: "DATETIME",
+		backfill:   "UPDATE events SET end_datetime = strftime('%Y-%m-%d %H:%M:%S', datetime, '+1 hour') || '+00:00' WHERE end_datetime IS NULL",
+	},
+	{
+		// UID of the event in the calendar or file it was imported from, empty for events created here
+		table:      "events",
+		column:     "external_uid",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		// WGS 84 degrees, NULL for events that are not placed on a map
+		table:      "events",
+		column:     "latitude",
+		definition: "REAL",
+	},
+	{
+		table:      "events",
+		column:     "longitude",
+		definition: "REAL",
+	},
+	{
+		table:      "events",
+		column:     "address_street",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		table:      "events",
+		column:     "address_city",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		table:      "events",
+		column:     "address_region",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		table:      "events",
+		column:     "address_postal_code",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		// ISO 3166-1 alpha-2 code
+		table:      "events",
+		column:     "address_country",
+		definition: "TEXT NOT NULL DEFAULT ''",
+	},
+	{
+		// NULL for events that only have a location
+		table:      "events",
+		column:     "venue_id",
+		definition: "INTEGER REFERENCES venues(id)",
+	},
+	{
+		// NULL is unlimited
+		table:      "events",
+		column:     "capacity",
+		definition: "INTEGER",
+	},
+	{
+		// NULL for uncategorized events
+		table:      "events",
+		column:     "category_id",
+		definition: "INTEGER REFERENCES categories(id)",
+	},
+	{
+		// NULL for events outside any organization
+		table:      "events",
+		column:     "organization_id",
+		definition: "INTEGER REFERENCES organizations(id)",
+	},
+	{
+		// Events created before visibility existed were listed for everyone
+		table:      "events",
+		column:     "visibility",
+		definition: "TEXT NOT NULL DEFAULT 'public'",
+	},
+	{
+		// Registrations made before RSVPs existed are attendees who said they were going
+		table:      "registrations",
+		column:     "status",
+		definition: "TEXT NOT NULL DEFAULT 'going'",
+	},
+}
+
+// Indexes on columns added by columnMigrations, created once the columns exist
+var indexMigrations = []string{
+	// Re-importing a UID updates the organizer's event in the same organization instead of adding another
+	`DROP INDEX IF EXISTS events_external_uid`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS events_organization_external_uid ON events (user_id, COALESCE(organization_id, 0), external_uid)
+	WHERE external_uid != '' AND deleted_at IS NULL`,
+
+	// Bounding-box prefilter of events near a point
+	`CREATE INDEX IF NOT EXISTS events_lat_lng ON events (latitude, longitude) WHERE latitude IS NOT NULL`,
+
+	// Events at a venue
+	`CREATE INDEX IF NOT EXISTS events_venue_id ON events (venue_id) WHERE venue_id IS NOT NULL`,
+
+	// Events in a category
+	`CREATE INDEX IF NOT EXISTS events_category_id ON events (category_id) WHERE category_id IS NOT NULL`,
+
+	// Events of an organization
+	`CREATE INDEX IF NOT EXISTS events_organization_id ON events (organization_id, datetime)`,
+}
+
+func migrateColumns() {
+	for _, m := range columnMigrations {
+		added, err := addColumnIfMissing(m.table, m.column, m.definition)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to add column %s.%s: %v", m.table, m.column, err))
+		}
+
+		if added && m.backfill != "" {
+			_, err = DB.Exec(m.backfill)
+			if err != nil {
+				panic(fmt.Sprintf("Failed to backfill column %s.%s: %v", m.table, m.column, err))
+			}
+		}
+	}
+}
+
+func migrateIndexes() {
+	for _, statement := range indexMigrations {
+		_, err := DB.Exec(statement)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to create index: %v", err))
+		}
+	}
+}
+
+// addColumnIfMissing runs ALTER TABLE ADD COLUMN unless the column already exists
+func addColumnIfMissing(table, column, definition string) (bool, error) {
+	var exists bool
+	err := DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM pragma_table_info(?) WHERE name = ?)`, table, column).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		return false, nil
+	}
+
+	// table/column names come from columnMigrations only
+	_, err = DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenDB_AddsColumnsToExistingTables(t *testing.T) {
+	dsn := "file:migrations_test?mode=memory&cache=shared"
+
+	// database created by an older version of the app
+	old, err := sql.Open("sqlite3", dsn)
+	assert.NoError(t, err)
+	defer old.Close()
+
+	_, err = old.Exec(`
+	CREATE TABLE events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL,
+		location TEXT NOT NULL,
+		datetime DATETIME NOT NULL,
+		user_id INTEGER
+	)
+	`)
+	assert.NoError(t, err)
+
+	_, err = old.Exec(`INSERT INTO events (name, description, location, datetime, user_id) VALUES ('a', 'b', 'c', '2030-01-01 09:00:00', 1)`)
+	assert.NoError(t, err)
+
+	// written with the offset the request was sent with
+	_, err = old.Exec(`INSERT INTO events (name, description, location, datetime, user_id) VALUES ('d', 'e', 'f', '2030-01-01 09:00:00+07:00', 1)`)
+	assert.NoError(t, err)
+
+	OpenDB(dsn)
+
+	var version int64
+	var updatedAt sql.NullTime
+	err = DB.QueryRow(`SELECT version, updated_at FROM events`).Scan(&version, &updatedAt)
+	assert.NoError(t, err)
+
+	// existing rows are backfilled
+	assert.Equal(t, int64(1), version)
+	assert.True(t, updatedAt.Valid)
+
+	// start times are converted to UTC in the format the driver writes, ends default to an hour later
+	var start, end, zone string
+	err = DB.QueryRow(`SELECT datetime || '', end_datetime || '', time_zone FROM events WHERE name = 'd'`).Scan(&start, &end, &zone)
+	assert.NoError(t, err)
+	assert.Equal(t, "2030-01-01 02:00:00+00:00", start)
+	assert.Equal(t, "2030-01-01 03:00:00+00:00", end)
+	assert.Equal(t, "UTC", zone)
+
+	// running again is a no-op
+	assert.NotPanics(t, migrateColumns)
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// FullTextSearch is true when SQLite was built with FTS5, events are then indexed in events_fts.
+// Builds need -tags sqlite_fts5 for it, the Makefile and CI pass it. Without it event search falls back to LIKE.
+// Search is SQLite only, a PostgreSQL (tsvector) variant is out of scope until the app can run on PostgreSQL.
+var FullTextSearch bool
+
+// events_fts indexes the text columns of events without copying them (external content)
+const createSearchTableStatement = `CREATE VIRTUAL TABLE events_fts USING fts5(
+	name, description, location,
+	content='events', content_rowid='id',
+	tokenize='unicode61 remove_diacritics 2'
+)`
+
+// The triggers keep events_fts in sync with every insert, update and delete of events
+var searchTriggers = map[string]string{
+	"events_fts_insert": `CREATE TRIGGER IF NOT EXISTS events_fts_insert AFTER INSERT ON events
+	BEGIN
+		INSERT INTO events_fts (rowid, name, description, location) VALUES (new.id, new.name, new.description, new.location);
+	END`,
+	"events_fts_delete": `CREATE TRIGGER IF NOT EXISTS events_fts_delete AFTER DELETE ON events
+	BEGIN
+		INSERT INTO events_fts (events_fts, rowid, name, description, location) VALUES ('delete', old.id, old.name, old.description, old.location);
+	END`,
+	"events_fts_update": `CREATE TRIGGER IF NOT EXISTS events_fts_update AFTER UPDATE OF name, description, location ON events
+	BEGIN
+		INSERT INTO events_fts (events_fts, rowid, name, description, location) VALUES ('delete', old.id, old.name, old.description, old.location);
+		INSERT INTO events_fts (rowid, name, description, location) VALUES (new.id, new.name, new.description, new.location);
+	END`,
+}
+
+// Indexes the events that existed before the table, or were written while the triggers were gone
+const rebuildSearchIndexStatement = `INSERT INTO events_fts (events_fts) VALUES ('rebuild')`
+
+// createSearchIndex creates events_fts and its triggers when SQLite has FTS5. A database last opened by a
+// build without FTS5 gets its triggers back and is reindexed. A build without FTS5 drops the triggers a
+// FTS5 build left, they would fail every write to events with "no such module: fts5".
+func createSearchIndex() {
+	err := DB.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&FullTextSearch)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to check for FTS5: %v", err))
+	}
+
+	triggers, err := existingSearchTriggers()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to check for the search index: %v", err))
+	}
+
+	if !FullTextSearch {
+		slog.Warn("SQLite was built without FTS5, event search falls back to LIKE (build with -tags sqlite_fts5)")
+		dropSearchTriggers(triggers)
+		return
+	}
+
+	var exists bool
+	err = DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE name = 'events_fts')`).Scan(&exists)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to check for the search index: %v", err))
+	}
+
+	if exists && len(triggers) == len(searchTriggers) {
+		return
+	}
+
+	if exists {
+		slog.Warn("search index was left out of sync by a build without FTS5, rebuilding it")
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create search index: %v", err))
+	}
+
+	// No-op once committed
+	defer tx.Rollback()
+
+	statements := []string{}
+	if !exists {
+		statements = append(statements, createSearchTableStatement)
+	}
+	for _, statement := range searchTriggers {
+		statements = append(statements, statement)
+	}
+	statements = append(statements, rebuildSearchIndexStatement)
+
+	for _, statement := range statements {
+		_, err = tx.Exec(statement)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to create search index: %v", err))
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create search index: %v", err))
+	}
+}
+
+// existingSearchTriggers lists the triggers of events_fts found in the database
+func existingSearchTriggers() ([]string, error) {
+	rows, err := DB.Query(`SELECT name FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'events_fts_%'`)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// dropSearchTriggers drops the triggers a FTS5 build left, events_fts itself cannot be dropped without FTS5
+// and is rebuilt by the next FTS5 build
+func dropSearchTriggers(triggers []string) {
+	if len(triggers) == 0 {
+		return
+	}
+
+	slog.Warn("dropping the search index triggers of a build with FTS5, the index is rebuilt once FTS5 is back")
+
+	for _, name := range triggers {
+		_, err := DB.Exec(`DROP TRIGGER IF EXISTS ` + name)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to drop search index trigger %s: %v", name, err))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenDB_DropsSearchTriggersWithoutFTS5(t *testing.T) {
+	dsn := "file:search_test_no_fts?mode=memory&cache=shared"
+
+	// database last opened by a build with FTS5
+	old, err := sql.Open("sqlite3", dsn)
+	assert.NoError(t, err)
+	defer old.Close()
+
+	_, err = old.Exec(`
+	CREATE TABLE events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL,
+		location TEXT NOT NULL,
+		datetime DATETIME NOT NULL,
+		user_id INTEGER
+	)
+	`)
+	assert.NoError(t, err)
+
+	_, err = old.Exec(searchTriggers["events_fts_insert"])
+	assert.NoError(t, err)
+
+	OpenDB(dsn)
+	if FullTextSearch {
+		t.Skip("SQLite was built with FTS5")
+	}
+
+	_, err = DB.Exec(`INSERT INTO events (name, description, location, datetime, user_id) VALUES ('a', 'b', 'c', '2030-01-01 09:00:00', 1)`)
+	assert.NoError(t, err)
+
+	triggers, err := existingSearchTriggers()
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+}
+
+func TestOpenDB_RestoresSearchTriggers(t *testing.T) {
+	OpenDB("file:search_test_fts?mode=memory&cache=shared")
+	if !FullTextSearch {
+		t.Skip("SQLite was built without FTS5")
+	}
+
+	// triggers dropped by a build without FTS5, which then wrote an event
+	dropSearchTriggers([]string{"events_fts_insert", "events_fts_delete", "events_fts_update"})
+
+	_, err := DB.Exec(`INSERT INTO events (name, description, location, datetime, user_id) VALUES ('Jazz night', 'b', 'c', '2030-01-01 09:00:00', 1)`)
+	assert.NoError(t, err)
+
+	createSearchIndex()
+
+	triggers, err := existingSearchTriggers()
+	assert.NoError(t, err)
+	assert.Len(t, triggers, len(searchTriggers))
+
+	var found int
+	err = DB.QueryRow(`SELECT COUNT(*) FROM events_fts WHERE events_fts MATCH 'jazz'`).Scan(&found)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, found)
+}
@@ -0,0 +1,22 @@
+package docs
+
+import (
+	"embed"
+)
+
+// OpenAPI is the OpenAPI 3.1 document describing every route in routes.RegisterRoutes,
+// keep it in sync when adding routes (routes tests fail otherwise)
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// SwaggerUI is a page rendering OpenAPI with Swagger UI
+//
+//go:embed swagger.html
+var SwaggerUI []byte
+
+// SwaggerUIAssets is swagger-ui-dist 5.18.2 (swagger-ui.css and swagger-ui-bundle.js, Apache-2.0),
+// vendored so /docs neither depends on nor changes with a CDN. Upgrade by replacing both files.
+//
+//go:embed swagger-ui
+var SwaggerUIAssets embed.FS
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"context"
+
+	"example.com/event/models"
+)
+
+var GetAuditLog = func(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetAuditLog")
+	entries, err := models.GetAuditLog(ctx, filter)
+	endSpan(span, err)
+
+	return entries, err
+}
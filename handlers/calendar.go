@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"example.com/event/models"
+)
+
+var GetRegisteredEvents = func(ctx context.Context, userId int64, from, to time.Time) ([]models.Event, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetRegisteredEvents")
+	events, err := models.GetRegisteredEvents(ctx, userId, from, to)
+	endSpan(span, err)
+
+	return events, err
+}
+
+var RotateCalendarToken = func(ctx context.Context, userId int64) (string, error) {
+	ctx, span := tracer.Start(ctx, "handlers.RotateCalendarToken")
+	token, err := models.RotateCalendarToken(ctx, userId)
+	endSpan(span, err)
+
+	return token, err
+}
+
+var RevokeCalendarToken = func(ctx context.Context, userId int64) error {
+	ctx, span := tracer.Start(ctx, "handlers.RevokeCalendarToken")
+	err := models.RevokeCalendarToken(ctx, userId)
+	endSpan(span, err)
+
+	return err
+}
+
+var GetUserIDByCalendarToken = func(ctx context.Context, token string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetUserIDByCalendarToken")
+	userId, err := models.GetUserIDByCalendarToken(ctx, token)
+	endSpan(span, err)
+
+	return userId, err
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+
+	"example.com/event/models"
+)
+
+var GetAllCategories = func(ctx context.Context) ([]models.Category, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetAllCategories")
+	categories, err := models.GetAllCategories(ctx)
+	endSpan(span, err)
+
+	return categories, err
+}
+
+var GetCategoryByID = func(ctx context.Context, categoryId int64) (*models.Category, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetCategoryByID")
+	category, err := models.GetCategoryByID(ctx, categoryId)
+	endSpan(span, err)
+
+	return category, err
+}
+
+var GetCategoryBySlug = func(ctx context.Context, slug string) (*models.Category, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetCategoryBySlug")
+	category, err := models.GetCategoryBySlug(ctx, slug)
+	endSpan(span, err)
+
+	return category, err
+}
+
+var CreateCategory = func(ctx context.Context, category *models.Category) error {
+	ctx, span := tracer.Start(ctx, "handlers.CreateCategory")
+	err := category.Save(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var UpdateCategory = func(ctx context.Context, category *models.Category) error {
+	ctx, span := tracer.Start(ctx, "handlers.UpdateCategory")
+	err := category.Update(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var DeleteCategory = func(ctx context.Context, category *models.Category) error {
+	ctx, span := tracer.Start(ctx, "handlers.DeleteCategory")
+	err := category.Delete(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var GetTagCounts = func(ctx context.Context, filter models.TagFilter) ([]models.TagCount, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetTagCounts")
+	counts, err := models.GetTagCounts(ctx, filter)
+	endSpan(span, err)
+
+	return counts, err
+}
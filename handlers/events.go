@@ -1,23 +1,128 @@
 package handlers
 
-import "example.com/event/models"
+import (
+	"context"
+	"time"
 
-var GetAllEvents = func() ([]models.Event, error) {
-	return models.GetAllEvents()
+	"example.com/event/models"
+)
+
+var GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetAllEvents")
+	events, err := models.GetAllEvents(ctx, filter)
+	endSpan(span, err)
+
+	return events, err
+}
+
+var GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetEventByID")
+	event, err := models.GetEventByID(ctx, eventId)
+	endSpan(span, err)
+
+	return event, err
+}
+
+var CreateEvent = func(ctx context.Context, event *models.Event) error {
+	ctx, span := tracer.Start(ctx, "handlers.CreateEvent")
+	err := event.Save(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var UpdateEvent = func(ctx context.Context, event *models.Event) error {
+	ctx, span := tracer.Start(ctx, "handlers.UpdateEvent")
+	err := event.Update(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var PatchEvent = func(ctx context.Context, event *models.Event, columns []string) error {
+	ctx, span := tracer.Start(ctx, "handlers.PatchEvent")
+	err := event.UpdateColumns(ctx, columns)
+	endSpan(span, err)
+
+	return err
+}
+
+var DeleteEvent = func(ctx context.Context, event *models.Event) error {
+	ctx, span := tracer.Start(ctx, "handlers.DeleteEvent")
+	err := event.Delete(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var ChangeEventStatus = func(ctx context.Context, event *models.Event, status models.EventStatus, reason string) error {
+	ctx, span := tracer.Start(ctx, "handlers.ChangeEventStatus")
+	err := event.ChangeStatus(ctx, status, reason)
+	endSpan(span, err)
+
+	return err
+}
+
+var GetDeletedEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetDeletedEventByID")
+	event, err := models.GetDeletedEventByID(ctx, eventId)
+	endSpan(span, err)
+
+	return event, err
+}
+
+var RestoreEvent = func(ctx context.Context, event *models.Event) error {
+	ctx, span := tracer.Start(ctx, "handlers.RestoreEvent")
+	err := event.Restore(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var PurgeDeletedEvents = func(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, span := tracer.Start(ctx, "handlers.PurgeDeletedEvents")
+	purged, err := models.PurgeDeletedEvents(ctx, cutoff)
+	endSpan(span, err)
+
+	return purged, err
 }
 
-var GetEventByID = func(eventId int64) (*models.Event, error) {
-	return models.GetEventByID(eventId)
+var GetOccurrences = func(ctx context.Context, event *models.Event, from, to time.Time) ([]models.Event, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetOccurrences")
+	occurrences, err := event.GetOccurrences(ctx, from, to)
+	endSpan(span, err)
+
+	return occurrences, err
 }
 
-var CreateEvent = func(event *models.Event) error {
-	return event.Save()
+var SaveOccurrenceOverride = func(ctx context.Context, event *models.Event, override *models.OccurrenceOverride) error {
+	ctx, span := tracer.Start(ctx, "handlers.SaveOccurrenceOverride")
+	err := event.SaveOccurrenceOverride(ctx, override)
+	endSpan(span, err)
+
+	return err
 }
 
-var UpdateEvent = func(event *models.Event) error {
-	return event.Update()
+var DeleteOccurrenceOverride = func(ctx context.Context, event *models.Event, start time.Time) error {
+	ctx, span := tracer.Start(ctx, "handlers.DeleteOccurrenceOverride")
+	err := event.DeleteOccurrenceOverride(ctx, start)
+	endSpan(span, err)
+
+	return err
 }
 
-var DeleteEvent = func(event *models.Event) error {
-	return event.Delete()
+var ImportEvents = func(ctx context.Context, userId int64, events []models.Event, dryRun bool) ([]models.ImportResult, error) {
+	ctx, span := tracer.Start(ctx, "handlers.ImportEvents")
+	results, err := models.ImportEvents(ctx, userId, events, dryRun)
+	endSpan(span, err)
+
+	return results, err
+}
+
+var SearchEvents = func(ctx context.Context, filter models.SearchFilter) ([]models.SearchResult, int, error) {
+	ctx, span := tracer.Start(ctx, "handlers.SearchEvents")
+	results, total, err := models.SearchEvents(ctx, filter)
+	endSpan(span, err)
+
+	return results, total, err
 }
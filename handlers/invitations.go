@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	"example.com/event/models"
+)
+
+var IsEventInvited = func(ctx context.Context, eventId, userId int64) (bool, error) {
+	ctx, span := tracer.Start(ctx, "handlers.IsEventInvited")
+	invited, err := models.IsInvited(ctx, eventId, userId)
+	endSpan(span, err)
+
+	return invited, err
+}
+
+var GetEventInvitations = func(ctx context.Context, eventId int64) ([]models.EventInvitation, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetEventInvitations")
+	invitations, err := models.GetEventInvitations(ctx, eventId)
+	endSpan(span, err)
+
+	return invitations, err
+}
+
+var GetEventInvitation = func(ctx context.Context, eventId, userId int64) (*models.EventInvitation, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetEventInvitation")
+	invitation, err := models.GetEventInvitation(ctx, eventId, userId)
+	endSpan(span, err)
+
+	return invitation, err
+}
+
+var InviteToEvent = func(ctx context.Context, event *models.Event, email string, invitedBy int64) (*models.EventInvitation, error) {
+	ctx, span := tracer.Start(ctx, "handlers.InviteToEvent")
+	invitation, err := event.Invite(ctx, email, invitedBy)
+	endSpan(span, err)
+
+	return invitation, err
+}
+
+var AcceptEventInvitation = func(ctx context.Context, event *models.Event, userId, invitedBy int64) (*models.EventInvitation, error) {
+	ctx, span := tracer.Start(ctx, "handlers.AcceptEventInvitation")
+	invitation, err := event.AcceptInvitation(ctx, userId, invitedBy)
+	endSpan(span, err)
+
+	return invitation, err
+}
+
+var RevokeEventInvitation = func(ctx context.Context, invitation *models.EventInvitation) error {
+	ctx, span := tracer.Start(ctx, "handlers.RevokeEventInvitation")
+	err := invitation.Delete(ctx)
+	endSpan(span, err)
+
+	return err
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"example.com/event/models"
+)
+
+var GetEventRole = func(ctx context.Context, eventId, userId int64) (models.EventRole, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetEventRole")
+	role, err := models.GetEventRole(ctx, eventId, userId)
+	endSpan(span, err)
+
+	return role, err
+}
+
+var GetEventMembers = func(ctx context.Context, eventId int64) ([]models.EventMember, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetEventMembers")
+	members, err := models.GetEventMembers(ctx, eventId)
+	endSpan(span, err)
+
+	return members, err
+}
+
+var GetEventMember = func(ctx context.Context, eventId, userId int64) (*models.EventMember, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetEventMember")
+	member, err := models.GetEventMember(ctx, eventId, userId)
+	endSpan(span, err)
+
+	return member, err
+}
+
+var AddEventMember = func(ctx context.Context, event *models.Event, email string, role models.EventRole, invitedBy int64) (*models.EventMember, error) {
+	ctx, span := tracer.Start(ctx, "handlers.AddEventMember")
+	member, err := event.AddMember(ctx, email, role, invitedBy)
+	endSpan(span, err)
+
+	return member, err
+}
+
+var RemoveEventMember = func(ctx context.Context, member *models.EventMember) error {
+	ctx, span := tracer.Start(ctx, "handlers.RemoveEventMember")
+	err := member.Delete(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var TransferEventOwnership = func(ctx context.Context, event *models.Event, userId int64) error {
+	ctx, span := tracer.Start(ctx, "handlers.TransferEventOwnership")
+	err := event.TransferOwnership(ctx, userId)
+	endSpan(span, err)
+
+	return err
+}
+
+var GetEventRegistrations = func(ctx context.Context, event *models.Event, occurrence *time.Time) ([]models.Registration, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetEventRegistrations")
+	registrations, err := event.GetRegistrations(ctx, occurrence)
+	endSpan(span, err)
+
+	return registrations, err
+}
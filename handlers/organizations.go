@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+
+	"example.com/event/models"
+)
+
+var GetAllOrganizations = func(ctx context.Context) ([]models.Organization, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetAllOrganizations")
+	organizations, err := models.GetAllOrganizations(ctx)
+	endSpan(span, err)
+
+	return organizations, err
+}
+
+var GetOrganizationBySlug = func(ctx context.Context, slug string) (*models.Organization, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetOrganizationBySlug")
+	organization, err := models.GetOrganizationBySlug(ctx, slug)
+	endSpan(span, err)
+
+	return organization, err
+}
+
+var CreateOrganization = func(ctx context.Context, organization *models.Organization, ownerId int64) error {
+	ctx, span := tracer.Start(ctx, "handlers.CreateOrganization")
+	err := organization.Save(ctx, ownerId)
+	endSpan(span, err)
+
+	return err
+}
+
+var UpdateOrganization = func(ctx context.Context, organization *models.Organization) error {
+	ctx, span := tracer.Start(ctx, "handlers.UpdateOrganization")
+	err := organization.Update(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var GetOrganizationRole = func(ctx context.Context, organizationId, userId int64) (models.OrganizationRole, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetOrganizationRole")
+	role, err := models.GetOrganizationRole(ctx, organizationId, userId)
+	endSpan(span, err)
+
+	return role, err
+}
+
+var GetOrganizationMembers = func(ctx context.Context, organizationId int64) ([]models.OrganizationMember, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetOrganizationMembers")
+	members, err := models.GetOrganizationMembers(ctx, organizationId)
+	endSpan(span, err)
+
+	return members, err
+}
+
+var GetOrganizationMember = func(ctx context.Context, organizationId, userId int64) (*models.OrganizationMember, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetOrganizationMember")
+	member, err := models.GetOrganizationMember(ctx, organizationId, userId)
+	endSpan(span, err)
+
+	return member, err
+}
+
+var AddOrganizationMember = func(ctx context.Context, organization *models.Organization, email string, role models.OrganizationRole) (*models.OrganizationMember, error) {
+	ctx, span := tracer.Start(ctx, "handlers.AddOrganizationMember")
+	member, err := organization.AddMember(ctx, email, role)
+	endSpan(span, err)
+
+	return member, err
+}
+
+var RemoveOrganizationMember = func(ctx context.Context, member *models.OrganizationMember) error {
+	ctx, span := tracer.Start(ctx, "handlers.RemoveOrganizationMember")
+	err := member.Delete(ctx)
+	endSpan(span, err)
+
+	return err
+}
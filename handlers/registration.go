@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"example.com/event/models"
+)
+
+var RegisterEvent = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time) error {
+	ctx, span := tracer.Start(ctx, "handlers.RegisterEvent")
+	err := event.RegisterEvent(ctx, userId, occurrence)
+	endSpan(span, err)
+
+	return err
+}
+
+var UnregisterEvent = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time) error {
+	ctx, span := tracer.Start(ctx, "handlers.UnregisterEvent")
+	err := event.UnregisterEvent(ctx, userId, occurrence)
+	endSpan(span, err)
+
+	return err
+}
+
+var SetEventRSVP = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time, status models.RSVPStatus) (*models.Registration, error) {
+	ctx, span := tracer.Start(ctx, "handlers.SetEventRSVP")
+	registration, err := event.SetRSVP(ctx, userId, occurrence, status)
+	endSpan(span, err)
+
+	return registration, err
+}
+
+var CheckInAttendee = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time, status models.RSVPStatus) (*models.Registration, error) {
+	ctx, span := tracer.Start(ctx, "handlers.CheckInAttendee")
+	registration, err := event.CheckIn(ctx, userId, occurrence, status)
+	endSpan(span, err)
+
+	return registration, err
+}
+
+var GetRSVPCounts = func(ctx context.Context, event *models.Event) (map[models.RSVPStatus]int, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetRSVPCounts")
+	counts, err := event.GetRSVPCounts(ctx)
+	endSpan(span, err)
+
+	return counts, err
+}
+
+var GetRSVPHistory = func(ctx context.Context, event *models.Event, userId int64) ([]models.RSVPChange, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetRSVPHistory")
+	changes, err := event.GetRSVPHistory(ctx, userId)
+	endSpan(span, err)
+
+	return changes, err
+}
@@ -1,18 +1,40 @@
 package handlers
 
 import (
+	"context"
+
 	"example.com/event/models"
 	"example.com/event/utils"
 )
 
-var SaveUser = func(user *models.User) error {
-	return user.Save()
+var SaveUser = func(ctx context.Context, user *models.User) error {
+	ctx, span := tracer.Start(ctx, "handlers.SaveUser")
+	err := user.Save(ctx)
+	endSpan(span, err)
+
+	return err
 }
 
-var ValidateCredentials = func(user *models.User) error {
-	return user.ValidateCredentials()
+var ValidateCredentials = func(ctx context.Context, user *models.User) error {
+	ctx, span := tracer.Start(ctx, "handlers.ValidateCredentials")
+	err := user.ValidateCredentials(ctx)
+	endSpan(span, err)
+
+	return err
 }
 
-var GenerateToken = func(email string, userId int64) (string, error) {
-	return utils.GenerateToken(email, userId)
+var GenerateToken = func(ctx context.Context, email string, userId int64) (string, error) {
+	_, span := tracer.Start(ctx, "handlers.GenerateToken")
+	token, err := utils.GenerateToken(email, userId)
+	endSpan(span, err)
+
+	return token, err
+}
+
+var IsAdmin = func(ctx context.Context, userId int64) (bool, error) {
+	ctx, span := tracer.Start(ctx, "handlers.IsAdmin")
+	admin, err := models.IsAdmin(ctx, userId)
+	endSpan(span, err)
+
+	return admin, err
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"example.com/event/models"
+)
+
+var GetAllVenues = func(ctx context.Context) ([]models.Venue, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetAllVenues")
+	venues, err := models.GetAllVenues(ctx)
+	endSpan(span, err)
+
+	return venues, err
+}
+
+var GetVenueByID = func(ctx context.Context, venueId int64) (*models.Venue, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetVenueByID")
+	venue, err := models.GetVenueByID(ctx, venueId)
+	endSpan(span, err)
+
+	return venue, err
+}
+
+var CreateVenue = func(ctx context.Context, venue *models.Venue) error {
+	ctx, span := tracer.Start(ctx, "handlers.CreateVenue")
+	err := venue.Save(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var UpdateVenue = func(ctx context.Context, venue *models.Venue) error {
+	ctx, span := tracer.Start(ctx, "handlers.UpdateVenue")
+	err := venue.Update(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var DeleteVenue = func(ctx context.Context, venue *models.Venue) error {
+	ctx, span := tracer.Start(ctx, "handlers.DeleteVenue")
+	err := venue.Delete(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+var GetVenueBookings = func(ctx context.Context, venueId int64, from, to time.Time) ([]models.Event, error) {
+	ctx, span := tracer.Start(ctx, "handlers.GetVenueBookings")
+	bookings, err := models.GetVenueBookings(ctx, venueId, from, to, 0)
+	endSpan(span, err)
+
+	return bookings, err
+}
@@ -0,0 +1,160 @@
+// Package ics writes and reads iCalendar (RFC 5545) documents.
+// Only what event exports and imports need is supported: a VCALENDAR of VEVENTs,
+// written with UTC times, read with go-ical.
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MediaType is the Content-Type of iCalendar documents
+const MediaType = "text/calendar; charset=utf-8"
+
+// Event statuses (STATUS property of a VEVENT)
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+type Calendar struct {
+	// ProdID identifies the product that created the calendar
+	ProdID string
+
+	// Name is shown by clients subscribing to the calendar (X-WR-CALNAME), optional
+	Name string
+
+	Events []Event
+}
+
+type Event struct {
+	// UID must be globally unique and stable, clients use it to update the event on refresh
+	UID string
+
+	// Stamp is when the event was last revised (DTSTAMP)
+	Stamp    time.Time
+	Sequence int64
+
+	Start time.Time
+	End   time.Time
+
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+}
+
+// lines are folded at 75 octets, not counting the CRLF
+const maxLineOctets = 75
+
+// Write encodes the calendar with CRLF line endings and long lines folded
+func (c Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", c.ProdID)
+	lw.line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		e.write(lw)
+	}
+
+	lw.line("END", "VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+
+	return bw.Flush()
+}
+
+func (e Event) write(lw *lineWriter) {
+	lw.line("BEGIN", "VEVENT")
+	lw.line("UID", e.UID)
+	lw.line("DTSTAMP", formatTime(e.Stamp))
+	lw.line("DTSTART", formatTime(e.Start))
+	lw.line("DTEND", formatTime(e.End))
+	lw.line("SEQUENCE", strconv.FormatInt(e.Sequence, 10))
+	lw.line("SUMMARY", escapeText(e.Summary))
+
+	if e.Description != "" {
+		lw.line("DESCRIPTION", escapeText(e.Description))
+	}
+	if e.Location != "" {
+		lw.line("LOCATION", escapeText(e.Location))
+	}
+	if e.Status != "" {
+		lw.line("STATUS", e.Status)
+	}
+
+	lw.line("END", "VEVENT")
+}
+
+// formatTime renders a DATE-TIME in UTC form, e.g. 20301216T020000Z
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Escapes of TEXT values (RFC 5545 section 3.3.11), newlines of any kind become \n
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// lineWriter writes content lines, keeping the first error
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes NAME:value folded into lines of at most 75 octets, never splitting a UTF-8 sequence
+func (lw *lineWriter) line(name, value string) {
+	if lw.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+
+		lw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+
+		// continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+
+	lw.write(s + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err != nil {
+		return
+	}
+
+	_, lw.err = lw.w.WriteString(s)
+}
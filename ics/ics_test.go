@@ -0,0 +1,107 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	goical "github.com/emersion/go-ical"
+	"github.com/stretchr/testify/assert"
+)
+
+// validate checks the document against RFC 5545: CRLF content lines of at most 75 octets,
+// then parses it and re-encodes it with go-ical, whose encoder enforces the required
+// and at-most-once properties of VCALENDAR and VEVENT
+func validate(t *testing.T, document []byte) *goical.Calendar {
+	t.Helper()
+
+	assert.True(t, bytes.HasSuffix(document, []byte("\r\n")), "document must end with CRLF")
+	for _, line := range strings.Split(strings.TrimSuffix(string(document), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+		assert.NotContains(t, line, "\n", "bare LF")
+	}
+
+	calendar, err := goical.NewDecoder(bytes.NewReader(document)).Decode()
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	err = goical.NewEncoder(&bytes.Buffer{}).Encode(calendar)
+	assert.NoError(t, err)
+
+	return calendar
+}
+
+func TestCalendarWrite_Valid(t *testing.T) {
+	description := "Bring a laptop; Go 1.25, an editor, and\nplenty of coffee ☕. " + strings.Repeat("Ünïcödé ", 20)
+
+	calendar := Calendar{
+		ProdID: "-//Example//Events//EN",
+		Name:   "Go Workshops",
+		Events: []Event{
+			{
+				UID:         "event-1@example.com",
+				Stamp:       time.Date(2030, 11, 1, 8, 0, 0, 0, time.UTC),
+				Sequence:    2,
+				Start:       time.Date(2030, 12, 16, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+				End:         time.Date(2030, 12, 16, 11, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+				Summary:     "Go Workshop Jakarta",
+				Description: description,
+				Location:    "Jakarta, Indonesia",
+				Status:      StatusConfirmed,
+			},
+			{
+				UID:     "event-2@example.com",
+				Stamp:   time.Date(2030, 11, 1, 8, 0, 0, 0, time.UTC),
+				Start:   time.Date(2030, 12, 20, 2, 0, 0, 0, time.UTC),
+				End:     time.Date(2030, 12, 20, 3, 0, 0, 0, time.UTC),
+				Summary: "Cancelled meetup",
+				Status:  StatusCancelled,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := calendar.Write(&buf)
+	assert.NoError(t, err)
+
+	parsed := validate(t, buf.Bytes())
+	if parsed == nil {
+		return
+	}
+
+	events := parsed.Events()
+	if !assert.Len(t, events, 2) {
+		return
+	}
+
+	// text survives escaping and folding
+	text, err := events[0].Props.Text(goical.PropDescription)
+	assert.NoError(t, err)
+	assert.Equal(t, description, text)
+
+	location, err := events[0].Props.Text(goical.PropLocation)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jakarta, Indonesia", location)
+
+	// times are written in UTC
+	assert.Contains(t, buf.String(), "DTSTART:20301216T020000Z\r\n")
+
+	start, err := events[0].DateTimeStart(nil)
+	assert.NoError(t, err)
+	assert.True(t, start.Equal(calendar.Events[0].Start))
+
+	status, err := events[1].Status()
+	assert.NoError(t, err)
+	assert.Equal(t, goical.EventCancelled, status)
+}
+
+func TestCalendarWrite_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	err := Calendar{ProdID: "-//Example//Events//EN"}.Write(&buf)
+	assert.NoError(t, err)
+
+	// a feed with nothing in it yet is still a calendar clients can subscribe to
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Example//Events//EN\r\nCALSCALE:GREGORIAN\r\nEND:VCALENDAR\r\n", buf.String())
+}
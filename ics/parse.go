@@ -0,0 +1,137 @@
+package ics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	goical "github.com/emersion/go-ical"
+)
+
+// ParsedEvent is a VEVENT read by Parse, Stamp and Sequence are left unset
+type ParsedEvent struct {
+	Event
+
+	// TimeZone is the TZID the start was given in, empty for UTC and floating times
+	TimeZone string
+
+	// RecurrenceRule is the RRULE value, ExDates the occurrences it leaves out
+	RecurrenceRule string
+	ExDates        []time.Time
+
+	// RecurrenceID is set on a VEVENT that changes one occurrence of another VEVENT with the same UID
+	RecurrenceID bool
+
+	// Errors lists the properties that could not be read, by property name (e.g. DTSTART)
+	Errors map[string]string
+}
+
+// Parse reads the VEVENTs of an iCalendar document. A document that cannot be decoded fails as a whole,
+// properties that cannot be read are reported on the event they belong to.
+// Floating times are taken in the calendar's X-WR-TIMEZONE, or in UTC.
+func Parse(r io.Reader) ([]ParsedEvent, error) {
+	calendar, err := goical.NewDecoder(r).Decode()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("document is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	floating := time.UTC
+	if name, _ := calendar.Props.Text("X-WR-TIMEZONE"); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err == nil {
+			floating = loc
+		}
+	}
+
+	events := calendar.Events()
+	parsed := make([]ParsedEvent, 0, len(events))
+	for _, e := range events {
+		parsed = append(parsed, parseEvent(e, floating))
+	}
+
+	return parsed, nil
+}
+
+func parseEvent(e goical.Event, floating *time.Location) ParsedEvent {
+	p := ParsedEvent{Errors: map[string]string{}}
+
+	text := func(name string) string {
+		value, err := e.Props.Text(name)
+		if err != nil {
+			p.Errors[name] = err.Error()
+		}
+		return value
+	}
+
+	p.UID = text(goical.PropUID)
+	p.Summary = text(goical.PropSummary)
+	p.Description = text(goical.PropDescription)
+	p.Location = text(goical.PropLocation)
+	p.Status = strings.ToUpper(text(goical.PropStatus))
+	p.RecurrenceID = e.Props.Get(goical.PropRecurrenceID) != nil
+
+	start := e.Props.Get(goical.PropDateTimeStart)
+	if start == nil {
+		p.Errors[goical.PropDateTimeStart] = "is required"
+		return p
+	}
+
+	var err error
+	p.Start, err = start.DateTime(floating)
+	if err != nil {
+		p.Errors[goical.PropDateTimeStart] = err.Error()
+		return p
+	}
+
+	p.TimeZone = start.Params.Get(goical.PropTimezoneID)
+
+	switch {
+	case e.Props.Get(goical.PropDateTimeEnd) != nil:
+		p.End, err = e.Props.DateTime(goical.PropDateTimeEnd, floating)
+		if err != nil {
+			p.Errors[goical.PropDateTimeEnd] = err.Error()
+		}
+	case e.Props.Get(goical.PropDuration) != nil:
+		var duration time.Duration
+		duration, err = e.Props.Get(goical.PropDuration).Duration()
+		if err != nil {
+			p.Errors[goical.PropDuration] = err.Error()
+		}
+		p.End = p.Start.Add(duration)
+	case isDate(start):
+		// an all-day event without an end lasts the day
+		p.End = p.Start.AddDate(0, 0, 1)
+	}
+
+	if rule := e.Props.Get(goical.PropRecurrenceRule); rule != nil {
+		p.RecurrenceRule = rule.Value
+	}
+
+	for _, exdate := range e.Props.Values(goical.PropExceptionDates) {
+		// EXDATE holds a comma-separated list sharing the property's TZID
+		for value := range strings.SplitSeq(exdate.Value, ",") {
+			single := exdate
+			single.Value = value
+
+			t, err := single.DateTime(floating)
+			if err != nil {
+				p.Errors[goical.PropExceptionDates] = fmt.Sprintf("%q: %v", value, err)
+				continue
+			}
+
+			p.ExDates = append(p.ExDates, t)
+		}
+	}
+
+	return p
+}
+
+// isDate tells a DATE value (all-day) from a DATE-TIME
+func isDate(prop *goical.Prop) bool {
+	return prop.ValueType() == goical.ValueDate || (prop.ValueType() == goical.ValueDefault && len(prop.Value) == len("20060102"))
+}
@@ -0,0 +1,122 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	calendar := Calendar{
+		ProdID: "-//Example//Events//EN",
+		Events: []Event{{
+			UID:         "event-1@example.com",
+			Stamp:       time.Date(2030, 11, 1, 8, 0, 0, 0, time.UTC),
+			Start:       time.Date(2030, 12, 16, 2, 0, 0, 0, time.UTC),
+			End:         time.Date(2030, 12, 16, 3, 30, 0, 0, time.UTC),
+			Summary:     "Go Workshop; Jakarta",
+			Description: "Line one\nline two, " + strings.Repeat("long ", 30),
+			Location:    "Jakarta",
+			Status:      StatusConfirmed,
+		}},
+	}
+
+	var buf bytes.Buffer
+	err := calendar.Write(&buf)
+	assert.NoError(t, err)
+
+	events, err := Parse(&buf)
+	assert.NoError(t, err)
+	if !assert.Len(t, events, 1) {
+		return
+	}
+
+	e := events[0]
+	assert.Empty(t, e.Errors)
+	assert.Equal(t, calendar.Events[0].UID, e.UID)
+	assert.Equal(t, calendar.Events[0].Summary, e.Summary)
+	assert.Equal(t, calendar.Events[0].Description, e.Description)
+	assert.True(t, calendar.Events[0].Start.Equal(e.Start))
+	assert.True(t, calendar.Events[0].End.Equal(e.End))
+	assert.Equal(t, StatusConfirmed, e.Status)
+}
+
+func TestParse_ZonesRecurrenceAndErrors(t *testing.T) {
+	document := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Other//Tool//EN",
+		"X-WR-TIMEZONE:Asia/Jakarta",
+		"BEGIN:VEVENT",
+		"UID:weekly@other",
+		"DTSTART;TZID=America/New_York:20310107T090000",
+		"DURATION:PT90M",
+		"RRULE:FREQ=WEEKLY;COUNT=10",
+		"EXDATE;TZID=America/New_York:20310114T090000,20310121T090000",
+		"SUMMARY:Weekly meetup",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:weekly@other",
+		"RECURRENCE-ID;TZID=America/New_York:20310128T090000",
+		"DTSTART;TZID=America/New_York:20310128T100000",
+		"SUMMARY:Weekly meetup (moved)",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating@other",
+		"DTSTART:20310201T090000",
+		"SUMMARY:Floating",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:allday@other",
+		"DTSTART;VALUE=DATE:20310301",
+		"SUMMARY:All day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@other",
+		"DTSTART;TZID=Mars/Olympus_Mons:20310301T090000",
+		"SUMMARY:Broken",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	events, err := Parse(strings.NewReader(document))
+	assert.NoError(t, err)
+	if !assert.Len(t, events, 5) {
+		return
+	}
+
+	newYork, _ := time.LoadLocation("America/New_York")
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+
+	weekly := events[0]
+	assert.Empty(t, weekly.Errors)
+	assert.Equal(t, "America/New_York", weekly.TimeZone)
+	assert.True(t, time.Date(2031, 1, 7, 9, 0, 0, 0, newYork).Equal(weekly.Start))
+	assert.Equal(t, 90*time.Minute, weekly.End.Sub(weekly.Start))
+	assert.Equal(t, "FREQ=WEEKLY;COUNT=10", weekly.RecurrenceRule)
+	if assert.Len(t, weekly.ExDates, 2) {
+		assert.True(t, time.Date(2031, 1, 21, 9, 0, 0, 0, newYork).Equal(weekly.ExDates[1]))
+	}
+	assert.False(t, weekly.RecurrenceID)
+
+	assert.True(t, events[1].RecurrenceID)
+
+	// floating times are taken in X-WR-TIMEZONE
+	assert.True(t, time.Date(2031, 2, 1, 9, 0, 0, 0, jakarta).Equal(events[2].Start))
+	assert.True(t, events[2].End.IsZero())
+
+	assert.Equal(t, 24*time.Hour, events[3].End.Sub(events[3].Start))
+
+	assert.Contains(t, events[4].Errors, "DTSTART")
+}
+
+func TestParse_InvalidDocument(t *testing.T) {
+	for _, document := range []string{"", "not a calendar", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"} {
+		_, err := Parse(strings.NewReader(document))
+		assert.Error(t, err, document)
+	}
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+)
+
+// PurgeDeletedEvents removes events soft deleted longer than models.DeletedEventRetention ago,
+// once at start and then every interval until ctx is done
+func PurgeDeletedEvents(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeDeletedEvents(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeDeletedEvents(ctx context.Context) {
+	cutoff := time.Now().Add(-models.DeletedEventRetention)
+
+	purged, err := handlers.PurgeDeletedEvents(ctx, cutoff)
+	if err != nil {
+		// Shutting down is not a failure, the next start purges again
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "could not purge deleted events", "error", err)
+		}
+		return
+	}
+
+	if purged > 0 {
+		slog.InfoContext(ctx, "purged deleted events", "count", purged, "cutoff", cutoff)
+	}
+}
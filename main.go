@@ -1,19 +1,64 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"example.com/event/db"
+	"example.com/event/jobs"
+	"example.com/event/middlewares"
+	"example.com/event/models"
 	"example.com/event/routes"
+	"example.com/event/utils"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
+	// Initialize JSON logger (level from LOG_LEVEL)
+	utils.InitLogger()
+
+	// Initialize tracing (exporter from OTEL_TRACES_EXPORTER)
+	shutdownTracer, err := utils.InitTracer(context.Background())
+	if err != nil {
+		panic("Failed to initialize tracer: " + err.Error())
+	}
+
+	// Flush remaining spans on exit
+	defer func() {
+		if err := shutdownTracer(context.Background()); err != nil {
+			slog.Error("could not shutdown tracer", "error", err)
+		}
+	}()
+
 	// Initialize database
 	db.InitDB()
 
+	// How long deleted events stay restorable before they are purged
+	models.DeletedEventRetention = utils.GetEnvDuration("DELETED_EVENT_RETENTION", models.DeletedEventRetention)
+
 	// Setup engine (configure HTTP server)
-	server := gin.Default()
+	server := gin.New()
+
+	// Deadline for a single request, slow queries are cancelled once it passes
+	requestTimeout := utils.GetEnvDuration("REQUEST_TIMEOUT", 10*time.Second)
+
+	// Recover from panics, start a span per request, tag every request with an id, remember who made it
+	// for the audit log, write access logs and bound how long a request may take
+	server.Use(
+		gin.Recovery(),
+		otelgin.Middleware(utils.ServiceName),
+		middlewares.RequestID,
+		middlewares.AuditActor,
+		middlewares.Logger,
+		middlewares.Timeout(requestTimeout),
+	)
 
 	// GET "/"
 	server.GET("/", func(context *gin.Context) {
@@ -24,6 +69,41 @@ func main() {
 
 	routes.RegisterRoutes(server)
 
+	// Stop on Ctrl+C / SIGTERM so deferred cleanup (e.g. flushing spans) still runs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Remove expired soft deleted events in the background until shutdown
+	go jobs.PurgeDeletedEvents(ctx, utils.GetEnvDuration("PURGE_INTERVAL", time.Hour))
+
+	httpServer := &http.Server{
+		Addr:              ":8080",
+		Handler:           server,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       requestTimeout,
+		// leave room to write the timeout response after the request deadline
+		WriteTimeout: requestTimeout + 5*time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
 	// Start server on localhost:8080
-	server.Run(":8080")
+	go func() {
+		slog.Info("server started", "addr", httpServer.Addr)
+
+		err := httpServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("server stopped unexpectedly", "error", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+
+	// Give in-flight requests a few seconds to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("could not shutdown server", "error", err)
+	}
 }
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin only lets admins through, it must run after Authenticate
+func RequireAdmin(context *gin.Context) {
+	admin, err := handlers.IsAdmin(context.Request.Context(), context.GetInt64("userId"))
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if !admin {
+		problems.Abort(context, models.ErrAdminRequired)
+		return
+	}
+
+	context.Next()
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"example.com/event/models"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditActor attaches the request id and client IP to the request context so models record them
+// with every change in the audit log, Authenticate adds the user id.
+// It must be registered after RequestID.
+func AuditActor(context *gin.Context) {
+	actor := models.AuditActor{
+		RequestID: context.GetString("requestId"),
+		IP:        context.ClientIP(),
+	}
+
+	context.Request = context.Request.WithContext(models.WithAuditActor(context.Request.Context(), actor))
+
+	context.Next()
+}
@@ -1,8 +1,11 @@
 package middlewares
 
 import (
-	"net/http"
+	"errors"
+	"fmt"
 
+	"example.com/event/models"
+	"example.com/event/problems"
 	"example.com/event/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -12,25 +15,47 @@ func Authenticate(context *gin.Context) {
 
 	// Check if token not in headers
 	if token == "" {
-		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "Not authorized",
-		})
+		problems.Abort(context, utils.ErrMissingToken)
 		return
 	}
 
+	authenticate(context, token)
+}
+
+// OptionalAuthenticate lets anonymous requests through, a token that is sent must still be valid.
+// Public routes use it to show more (e.g. the caller's drafts) to signed-in users.
+func OptionalAuthenticate(context *gin.Context) {
+	token := context.Request.Header.Get("Authorization")
+
+	if token == "" {
+		context.Next()
+		return
+	}
+
+	authenticate(context, token)
+}
+
+func authenticate(context *gin.Context, token string) {
 	// Verify token
 	userId, err := utils.VerifyToken(token)
 	if err != nil {
-		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "Could not verify token",
-			"error":   err.Error(),
-		})
+		// Any verification failure is reported as an invalid token
+		if !errors.Is(err, utils.ErrInvalidToken) {
+			err = fmt.Errorf("%w: %v", utils.ErrInvalidToken, err)
+		}
+
+		problems.Abort(context, err)
 		return
 	}
 
 	// Store userId in request context so it can be accessed by next handlers
 	context.Set("userId", userId)
 
+	// Changes made by this request are audited as made by this user
+	actor := models.AuditActorFrom(context.Request.Context())
+	actor.UserID = userId
+	context.Request = context.Request.WithContext(models.WithAuditActor(context.Request.Context(), actor))
+
 	// Continue to next handlers
 	context.Next()
 }
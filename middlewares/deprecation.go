@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks a legacy route with Deprecation (RFC 9745) and Sunset (RFC 8594) headers,
+// and links to the route replacing it. successor may contain the same :params as the legacy route.
+func Deprecated(deprecatedAt, sunsetAt time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+
+	return func(context *gin.Context) {
+		// Fill path params of the successor with the values of this request
+		link := successor
+		for _, param := range context.Params {
+			link = strings.ReplaceAll(link, ":"+param.Key, param.Value)
+		}
+
+		context.Header("Deprecation", deprecation)
+		context.Header("Sunset", sunset)
+		context.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", link))
+
+		context.Next()
+	}
+}
@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireIfMatch rejects writes without an If-Match header (428),
+// so clients cannot overwrite changes they have not seen
+func RequireIfMatch(context *gin.Context) {
+	if context.GetHeader("If-Match") == "" {
+		problems.Abort(context, problems.ErrPreconditionRequired)
+		return
+	}
+
+	context.Next()
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Logger writes one structured access log entry per request.
+// It must be registered after RequestID so the request id is available.
+func Logger(context *gin.Context) {
+	start := time.Now()
+
+	// Continue to next handlers
+	context.Next()
+
+	status := context.Writer.Status()
+
+	attrs := []any{
+		slog.String("request_id", context.GetString("requestId")),
+		slog.String("method", context.Request.Method),
+		slog.String("path", context.Request.URL.Path),
+		slog.String("route", context.FullPath()),
+		slog.Int("status", status),
+		slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+		slog.String("client_ip", context.ClientIP()),
+	}
+
+	// Correlate logs with traces when the request is part of one
+	spanContext := trace.SpanContextFromContext(context.Request.Context())
+	if spanContext.HasTraceID() {
+		attrs = append(attrs, slog.String("trace_id", spanContext.TraceID().String()))
+	}
+
+	// userId only exists for authenticated requests
+	if userId, exists := context.Get("userId"); exists {
+		attrs = append(attrs, slog.Any("user_id", userId))
+	}
+
+	// Errors attached by handlers via context.Error
+	if len(context.Errors) > 0 {
+		attrs = append(attrs, slog.String("errors", context.Errors.String()))
+	}
+
+	switch {
+	case status >= http.StatusInternalServerError:
+		slog.Error("request completed", attrs...)
+	case status >= http.StatusBadRequest:
+		slog.Warn("request completed", attrs...)
+	default:
+		slog.Info("request completed", attrs...)
+	}
+}
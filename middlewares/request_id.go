@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"example.com/event/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Longest client supplied request id we accept, anything longer is replaced
+const maxRequestIDLength = 128
+
+func RequestID(context *gin.Context) {
+	requestId := context.GetHeader(RequestIDHeader)
+
+	// Generate a new id if client did not send one (or sent something unreasonable)
+	if requestId == "" || len(requestId) > maxRequestIDLength {
+		requestId = uuid.NewString()
+	}
+
+	// Store requestId in gin and request context so it can be accessed by logger, next handlers and models
+	context.Set("requestId", requestId)
+	context.Request = context.Request.WithContext(utils.WithRequestID(context.Request.Context(), requestId))
+
+	// Echo requestId back so clients can correlate responses with logs
+	context.Header(RequestIDHeader, requestId)
+
+	context.Next()
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHeader selects the organization (by slug) a request is about
+const OrganizationHeader = "X-Organization"
+
+// Tenant scopes the request to the organization named by the :orgSlug path parameter, or else the
+// X-Organization header, so models only read and write that organization's events. The organization
+// is stored as "organization". Requests naming neither only see events outside any organization.
+func Tenant(context *gin.Context) {
+	slug := context.Param("orgSlug")
+	if slug == "" {
+		slug = context.GetHeader(OrganizationHeader)
+	}
+
+	if slug == "" {
+		context.Next()
+		return
+	}
+
+	organization, err := handlers.GetOrganizationBySlug(context.Request.Context(), slug)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.Set("organization", organization)
+	context.Request = context.Request.WithContext(models.WithTenant(context.Request.Context(), organization.ID))
+
+	context.Next()
+}
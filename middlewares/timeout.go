@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	stdcontext "context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout attaches a deadline to the request context, so handlers and SQL queries
+// using context.Request.Context() are cancelled once the deadline passes
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		ctx, cancel := stdcontext.WithTimeout(context.Request.Context(), timeout)
+		defer cancel()
+
+		context.Request = context.Request.WithContext(ctx)
+
+		context.Next()
+	}
+}
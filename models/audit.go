@@ -0,0 +1,246 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"example.com/event/db"
+)
+
+// Audited actions
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// Audited entity types
+const (
+	AuditEntityEvent        = "event"
+	AuditEntityUser         = "user"
+	AuditEntityRegistration = "registration"
+	AuditEntityOccurrence   = "occurrence"
+	AuditEntityVenue        = "venue"
+	AuditEntityCategory     = "category"
+	AuditEntityMember       = "member"
+
+	AuditEntityOrganization       = "organization"
+	AuditEntityOrganizationMember = "organization_member"
+
+	AuditEntityInvitation = "invitation"
+)
+
+// AuditActor is who caused a change, taken from the request
+type AuditActor struct {
+	// UserID is 0 for anonymous requests (e.g. sign up) and background jobs
+	UserID    int64
+	RequestID string
+	IP        string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor attaches the actor recorded for every change made with ctx
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFrom returns the actor attached to ctx, the zero value when there is none
+func AuditActorFrom(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
+
+// AuditEntry is one row of the append-only audit log.
+// Before and After only hold the fields that changed.
+type AuditEntry struct {
+	ID         int64
+	ActorID    *int64
+	Action     string
+	EntityType string
+	EntityID   int64
+	Before     json.RawMessage
+	After      json.RawMessage
+	RequestID  string
+	IP         string
+	CreatedAt  time.Time
+}
+
+// writeAudit appends an entry for a change, q is the transaction making the change.
+// before is nil for creations and after is nil for removals.
+func writeAudit(ctx context.Context, q querier, action, entityType string, entityId int64, before, after map[string]any) error {
+	before, after = auditDiff(before, after)
+
+	beforeJSON, err := marshalAuditFields(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := marshalAuditFields(after)
+	if err != nil {
+		return err
+	}
+
+	actor := AuditActorFrom(ctx)
+
+	var actorId sql.NullInt64
+	if actor.UserID != 0 {
+		actorId = sql.NullInt64{Int64: actor.UserID, Valid: true}
+	}
+
+	query := `
+	INSERT INTO audit_log (actor_id, action, entity_type, entity_id, before, after, request_id, ip, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = q.ExecContext(ctx, query, actorId, action, entityType, entityId, beforeJSON, afterJSON, actor.RequestID, actor.IP, time.Now().UTC())
+
+	return err
+}
+
+// auditDiff drops the fields that are equal on both sides
+func auditDiff(before, after map[string]any) (map[string]any, map[string]any) {
+	if before == nil || after == nil {
+		return before, after
+	}
+
+	changedBefore := map[string]any{}
+	changedAfter := map[string]any{}
+	for field, old := range before {
+		if !sameAuditValue(old, after[field]) {
+			changedBefore[field] = old
+			changedAfter[field] = after[field]
+		}
+	}
+
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changedAfter[field] = value
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+// sameAuditValue compares values by their JSON form, e.g. equal instants in different zones
+func sameAuditValue(a, b any) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// marshalAuditFields stores nil as NULL
+func marshalAuditFields(fields map[string]any) (sql.NullString, error) {
+	if fields == nil {
+		return sql.NullString{}, nil
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// AuditFilter narrows GetAuditLog, zero fields match everything
+type AuditFilter struct {
+	EntityType string
+	EntityID   int64
+	ActorID    int64
+	Action     string
+
+	// BeforeID pages backwards: only entries older than this id
+	BeforeID int64
+	Limit    int
+}
+
+// GetAuditLog returns matching entries, newest first
+func GetAuditLog(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	conditions := []string{"1 = 1"}
+	args := []any{}
+
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.ActorID != 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.BeforeID != 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	args = append(args, filter.Limit)
+
+	query := `
+	SELECT id, actor_id, action, entity_type, entity_id, before, after, request_id, ip, created_at
+	FROM audit_log WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY id DESC LIMIT ?
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		var before, after sql.NullString
+
+		err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.EntityType, &e.EntityID, &before, &after, &e.RequestID, &e.IP, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// withTx runs fn in a transaction, committing when it returns nil
+func withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// No-op once committed
+	defer tx.Rollback()
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"example.com/event/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit_RecordsEventChanges(t *testing.T) {
+	ctx := WithAuditActor(context.Background(), AuditActor{UserID: 8001, RequestID: "req-audit", IP: "203.0.113.7"})
+
+	event := newTestEvent()
+	event.UserID = 8001
+	err := event.Save(ctx)
+	assert.NoError(t, err)
+
+	event.Location = "Bandung"
+	err = event.UpdateColumns(ctx, []string{"location"})
+	assert.NoError(t, err)
+
+	err = event.Delete(ctx)
+	assert.NoError(t, err)
+
+	entries, err := GetAuditLog(context.Background(), AuditFilter{EntityType: AuditEntityEvent, EntityID: event.ID, Limit: 10})
+	assert.NoError(t, err)
+	if !assert.Len(t, entries, 3) {
+		return
+	}
+
+	// newest first
+	assert.Equal(t, AuditDelete, entries[0].Action)
+	assert.Equal(t, AuditUpdate, entries[1].Action)
+	assert.Equal(t, AuditCreate, entries[2].Action)
+
+	update := entries[1]
+	assert.Equal(t, int64(8001), *update.ActorID)
+	assert.Equal(t, "req-audit", update.RequestID)
+	assert.Equal(t, "203.0.113.7", update.IP)
+
+	// only the changed field is kept
+	var before, after map[string]any
+	assert.NoError(t, json.Unmarshal(update.Before, &before))
+	assert.NoError(t, json.Unmarshal(update.After, &after))
+	assert.Equal(t, map[string]any{"location": "Jakarta"}, before)
+	assert.Equal(t, map[string]any{"location": "Bandung"}, after)
+
+	assert.Nil(t, entries[2].Before)
+	assert.Contains(t, string(entries[2].After), `"name":"Go Workshop Jakarta"`)
+
+	// filter by actor
+	byActor, err := GetAuditLog(context.Background(), AuditFilter{ActorID: 8001, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, byActor, 3)
+}
+
+func TestAudit_RecordsRegistrationsAndUsers(t *testing.T) {
+	event := newPublishedTestEvent(t)
+
+	const attendee = int64(8101)
+	err := event.RegisterEvent(context.Background(), attendee, nil)
+	assert.NoError(t, err)
+
+	err = event.UnregisterEvent(context.Background(), attendee, nil)
+	assert.NoError(t, err)
+
+	entries, err := GetAuditLog(context.Background(), AuditFilter{EntityType: AuditEntityRegistration, Limit: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, AuditDelete, entries[0].Action)
+		assert.Equal(t, AuditCreate, entries[1].Action)
+		assert.Equal(t, entries[0].EntityID, entries[1].EntityID)
+		assert.Nil(t, entries[0].ActorID)
+	}
+
+	user := User{Email: "audited@example.com", Password: "secret123"}
+	err = user.Save(context.Background())
+	assert.NoError(t, err)
+
+	entries, err = GetAuditLog(context.Background(), AuditFilter{EntityType: AuditEntityUser, EntityID: user.ID, Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.NotContains(t, string(entries[0].After), "password")
+	}
+}
+
+func TestAudit_NotWrittenWhenChangeFails(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	stale := event
+	err = event.UpdateColumns(context.Background(), []string{"location"})
+	assert.NoError(t, err)
+
+	stale.Location = "Surabaya"
+	err = stale.UpdateColumns(context.Background(), []string{"location"})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	entries, err := GetAuditLog(context.Background(), AuditFilter{EntityType: AuditEntityEvent, EntityID: event.ID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestAudit_AppendOnly(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	_, err = db.DB.Exec(`UPDATE audit_log SET action = 'tampered' WHERE entity_id = ?`, event.ID)
+	assert.ErrorContains(t, err, "append-only")
+
+	_, err = db.DB.Exec(`DELETE FROM audit_log WHERE entity_id = ?`, event.ID)
+	assert.ErrorContains(t, err, "append-only")
+}
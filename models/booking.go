@@ -0,0 +1,264 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"example.com/event/db"
+)
+
+// Statuses of events holding their venue, cancelled events and occurrences free it
+var bookingStatuses = []EventStatus{EventStatusDraft, EventStatusPublished, EventStatusCompleted}
+
+// TimeSlot is a span of time, End excluded
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// VenueConflict is a booking of the venue overlapping the event being saved
+type VenueConflict struct {
+	EventID int64 `json:"eventId"`
+
+	// Name is left out for events the caller cannot see (other organizers' drafts)
+	Name string `json:"name,omitempty"`
+
+	Start time.Time `json:"dateTime"`
+	End   time.Time `json:"endDateTime"`
+
+	// OccurrenceStart identifies the occurrence of a recurring event
+	OccurrenceStart *time.Time `json:"occurrenceStart,omitempty"`
+}
+
+// VenueConflictError lists the bookings an event overlaps, errors.Is matches it with ErrVenueConflict
+type VenueConflictError struct {
+	Conflicts []VenueConflict
+}
+
+func (e *VenueConflictError) Error() string {
+	return fmt.Sprintf("%v: %d overlapping bookings", ErrVenueConflict, len(e.Conflicts))
+}
+
+func (e *VenueConflictError) Is(target error) bool {
+	return target == ErrVenueConflict
+}
+
+// GetVenueBookings returns the events and occurrences at the venue overlapping from to to, by start.
+// Recurring events are expanded, excludeEventId (0 for none) is left out.
+// A venue is booked whichever organization books it, so every tenant's events are read.
+func GetVenueBookings(ctx context.Context, venueId int64, from, to time.Time, excludeEventId int64) ([]Event, error) {
+	return getVenueBookings(ctx, db.DB, venueId, from, to, excludeEventId)
+}
+
+// getVenueBookings is GetVenueBookings read with q
+func getVenueBookings(ctx context.Context, q querier, venueId int64, from, to time.Time, excludeEventId int64) ([]Event, error) {
+	query := `
+	SELECT ` + eventSelectColumns + ` FROM events
+	WHERE venue_id = ? AND id != ? AND deleted_at IS NULL AND status != 'cancelled'
+	AND datetime < ? AND (recurrence_rule != '' OR end_datetime > ?)
+	`
+
+	rows, err := q.QueryContext(ctx, query, venueId, excludeEventId, to.UTC(), from.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	events := []Event{}
+	var longest time.Duration
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+		longest = max(longest, e.Duration())
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// Occurrences starting up to the longest duration earlier may still be running at from
+	expanded, err := expandOccurrences(ctx, q, events, from.Add(-longest), to, bookingStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(expanded, func(e Event) bool {
+		return !e.End().After(from)
+	}), nil
+}
+
+// FindVenueConflicts returns the bookings of the event's venue overlapping the event, or any of its
+// occurrences over the next MaxOccurrenceWindow. Events without a venue and cancelled events never conflict.
+func FindVenueConflicts(ctx context.Context, e Event) ([]Event, error) {
+	return findVenueConflicts(ctx, db.DB, e)
+}
+
+// findVenueConflicts is FindVenueConflicts read with q
+func findVenueConflicts(ctx context.Context, q querier, e Event) ([]Event, error) {
+	if e.VenueID == nil || e.Status == EventStatusCancelled {
+		return []Event{}, nil
+	}
+
+	e.normalizeSchedule()
+
+	from, to := e.DateTime, e.End()
+	if e.Recurrence != nil {
+		// Past occurrences cannot be double-booked anymore
+		if now := time.Now().UTC(); now.After(from) {
+			from = now
+		}
+		to = from.Add(MaxOccurrenceWindow)
+	}
+
+	var overrides []OccurrenceOverride
+	if e.ID != 0 && e.Recurrence != nil {
+		byEvent, err := getOccurrenceOverrides(ctx, q, []int64{e.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		overrides = byEvent[e.ID]
+	}
+
+	occurrences, err := e.Occurrences(from, to, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences = slices.DeleteFunc(occurrences, func(o Event) bool {
+		return o.Status == EventStatusCancelled
+	})
+
+	if len(occurrences) == 0 {
+		return []Event{}, nil
+	}
+
+	bookings, err := getVenueBookings(ctx, q, *e.VenueID, occurrences[0].DateTime, occurrences[len(occurrences)-1].End(), e.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(bookings, func(booking Event) bool {
+		return !slices.ContainsFunc(occurrences, func(o Event) bool {
+			return o.DateTime.Before(booking.End()) && booking.DateTime.Before(o.End())
+		})
+	}), nil
+}
+
+type venueConflictsAllowedKey struct{}
+
+// WithVenueConflictsAllowed lets the events written with ctx overlap other bookings of their venue,
+// for organizers booking anyway
+func WithVenueConflictsAllowed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, venueConflictsAllowedKey{}, true)
+}
+
+// VenueConflictsAllowed reports whether ctx was made with WithVenueConflictsAllowed
+func VenueConflictsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(venueConflictsAllowedKey{}).(bool)
+	return allowed
+}
+
+// requireNoVenueConflicts fails with a *VenueConflictError when e overlaps other bookings of its venue.
+// Writes check within their transaction, so two of them cannot both take the same slot.
+func requireNoVenueConflicts(ctx context.Context, q querier, e Event) error {
+	if VenueConflictsAllowed(ctx) {
+		return nil
+	}
+
+	bookings, err := findVenueConflicts(ctx, q, e)
+	if err != nil {
+		return err
+	}
+
+	if len(bookings) == 0 {
+		return nil
+	}
+
+	return newVenueConflictError(ctx, bookings)
+}
+
+// newVenueConflictError reports bookings to the actor of ctx. Drafts and private events stay private
+// to their owner and events to their organization, only the time they hold is given away.
+func newVenueConflictError(ctx context.Context, bookings []Event) *VenueConflictError {
+	userId := AuditActorFrom(ctx).UserID
+	tenant := TenantFrom(ctx)
+
+	conflicts := make([]VenueConflict, 0, len(bookings))
+	for _, b := range bookings {
+		conflict := VenueConflict{
+			EventID:         b.ID,
+			Start:           b.DateTime,
+			End:             b.End(),
+			OccurrenceStart: b.OccurrenceStart,
+		}
+
+		sameTenant := b.OrganizationID == nil && tenant == 0 || b.OrganizationID != nil && *b.OrganizationID == tenant
+		hidden := b.Status == EventStatusDraft || b.VisibilityName() == VisibilityPrivate
+		if sameTenant && (!hidden || b.UserID == userId) {
+			conflict.Name = b.Name
+		}
+
+		conflicts = append(conflicts, conflict)
+	}
+
+	return &VenueConflictError{Conflicts: conflicts}
+}
+
+// rebooks reports whether an event written as after, stored as before, can run into other bookings of
+// its venue: it moved, changed venue, or holds its venue again once restored
+func rebooks(before, after Event) bool {
+	if after.VenueID == nil || after.DeletedAt != nil || after.Status == EventStatusCancelled {
+		return false
+	}
+
+	return before.DeletedAt != nil ||
+		before.Status == EventStatusCancelled ||
+		before.VenueID == nil || *before.VenueID != *after.VenueID ||
+		!before.DateTime.Equal(after.DateTime) ||
+		!before.End().Equal(after.End()) ||
+		!before.Recurrence.Equal(after.Recurrence)
+}
+
+// FreeSlots returns the gaps between bookings from from to to
+func FreeSlots(bookings []Event, from, to time.Time) []TimeSlot {
+	busy := make([]TimeSlot, 0, len(bookings))
+	for _, b := range bookings {
+		busy = append(busy, TimeSlot{Start: b.DateTime, End: b.End()})
+	}
+
+	slices.SortFunc(busy, func(a, b TimeSlot) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	free := []TimeSlot{}
+	cursor := from
+	for _, slot := range busy {
+		if slot.Start.After(cursor) {
+			end := slot.Start
+			if end.After(to) {
+				end = to
+			}
+			free = append(free, TimeSlot{Start: cursor, End: end})
+		}
+
+		if slot.End.After(cursor) {
+			cursor = slot.End
+		}
+
+		if !cursor.Before(to) {
+			return free
+		}
+	}
+
+	return append(free, TimeSlot{Start: cursor, End: to})
+}
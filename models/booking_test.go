@@ -0,0 +1,193 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindVenueConflicts(t *testing.T) {
+	venue := newTestVenue(t, 120)
+
+	// weekly from 2031-01-07, 09:00 to 11:00
+	series := newTestEvent()
+	series.Status = EventStatusPublished
+	series.DateTime = time.Date(2031, 1, 7, 9, 0, 0, 0, time.UTC)
+	series.EndDateTime = time.Date(2031, 1, 7, 11, 0, 0, 0, time.UTC)
+	series.Recurrence = &Recurrence{Rule: "FREQ=WEEKLY;COUNT=4"}
+	venue.ApplyTo(&series)
+	err := series.Save(context.Background())
+	assert.NoError(t, err)
+
+	event := newTestEvent()
+	event.DateTime = time.Date(2031, 1, 14, 10, 0, 0, 0, time.UTC)
+	event.EndDateTime = time.Date(2031, 1, 14, 12, 0, 0, 0, time.UTC)
+	venue.ApplyTo(&event)
+
+	conflicts, err := FindVenueConflicts(context.Background(), event)
+	assert.NoError(t, err)
+	if assert.Len(t, conflicts, 1) {
+		assert.Equal(t, series.ID, conflicts[0].ID)
+		assert.Equal(t, time.Date(2031, 1, 14, 9, 0, 0, 0, time.UTC), *conflicts[0].OccurrenceStart)
+	}
+
+	// starting as the occurrence ends is fine
+	event.DateTime = time.Date(2031, 1, 14, 11, 0, 0, 0, time.UTC)
+	event.EndDateTime = time.Date(2031, 1, 14, 13, 0, 0, 0, time.UTC)
+
+	conflicts, err = FindVenueConflicts(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// a cancelled occurrence frees the venue
+	event.DateTime = time.Date(2031, 1, 21, 10, 0, 0, 0, time.UTC)
+	event.EndDateTime = time.Date(2031, 1, 21, 12, 0, 0, 0, time.UTC)
+
+	override := OccurrenceOverride{OccurrenceStart: time.Date(2031, 1, 21, 9, 0, 0, 0, time.UTC), Cancelled: true}
+	err = series.SaveOccurrenceOverride(context.Background(), &override)
+	assert.NoError(t, err)
+
+	conflicts, err = FindVenueConflicts(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// the series itself is not a conflict of its own
+	conflicts, err = FindVenueConflicts(context.Background(), series)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
+
+func TestFreeSlots(t *testing.T) {
+	day := time.Date(2031, 1, 14, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time {
+		return day.Add(time.Duration(hour) * time.Hour)
+	}
+
+	bookings := []Event{
+		{DateTime: at(13), EndDateTime: at(15)},
+		{DateTime: at(9), EndDateTime: at(11)},
+		{DateTime: at(10), EndDateTime: at(12)},
+		{DateTime: at(22), EndDateTime: at(26)},
+	}
+
+	assert.Equal(t, []TimeSlot{
+		{Start: at(0), End: at(9)},
+		{Start: at(12), End: at(13)},
+		{Start: at(15), End: at(22)},
+	}, FreeSlots(bookings, at(0), at(24)))
+
+	assert.Equal(t, []TimeSlot{{Start: at(0), End: at(24)}}, FreeSlots(nil, at(0), at(24)))
+}
+
+func TestEventWrites_ErrorVenueConflict(t *testing.T) {
+	ctx := context.Background()
+	venue := newTestVenue(t, 120)
+
+	booked := newTestEvent()
+	booked.Status = EventStatusPublished
+	booked.DateTime = time.Date(2031, 2, 3, 10, 0, 0, 0, time.UTC)
+	booked.EndDateTime = time.Date(2031, 2, 3, 11, 0, 0, 0, time.UTC)
+	venue.ApplyTo(&booked)
+	assert.NoError(t, booked.Save(ctx))
+
+	overlapping := newTestEvent()
+	overlapping.DateTime = time.Date(2031, 2, 3, 10, 30, 0, 0, time.UTC)
+	overlapping.EndDateTime = time.Date(2031, 2, 3, 11, 30, 0, 0, time.UTC)
+	venue.ApplyTo(&overlapping)
+
+	err := overlapping.Save(ctx)
+	assert.ErrorIs(t, err, ErrVenueConflict)
+	assert.Zero(t, overlapping.ID)
+
+	// moving onto the booking fails, other changes go through
+	later := overlapping
+	later.DateTime = time.Date(2031, 2, 3, 12, 0, 0, 0, time.UTC)
+	later.EndDateTime = time.Date(2031, 2, 3, 13, 0, 0, 0, time.UTC)
+	assert.NoError(t, later.Save(ctx))
+
+	later.Name = "Go Workshop Jakarta, afternoon"
+	assert.NoError(t, later.UpdateColumns(ctx, []string{"name"}))
+
+	moved := later
+	moved.DateTime = overlapping.DateTime
+	moved.EndDateTime = overlapping.EndDateTime
+	assert.ErrorIs(t, moved.UpdateColumns(ctx, []string{"datetime", "end_datetime"}), ErrVenueConflict)
+
+	// the organizer books anyway
+	assert.NoError(t, moved.UpdateColumns(WithVenueConflictsAllowed(ctx), []string{"datetime", "end_datetime"}))
+	assert.NoError(t, moved.Delete(ctx))
+
+	// restoring brings the event back onto its venue, which was booked meanwhile
+	deleted, err := GetDeletedEventByID(ctx, moved.ID)
+	if assert.NoError(t, err) {
+		assert.ErrorIs(t, deleted.Restore(ctx), ErrVenueConflict)
+	}
+}
+
+func TestImportEvents_ErrorVenueConflict(t *testing.T) {
+	ctx := context.Background()
+	const userId = int64(9301)
+	venue := newTestVenue(t, 120)
+
+	booked := newTestEvent()
+	booked.Status = EventStatusPublished
+	booked.DateTime = time.Date(2031, 2, 10, 10, 0, 0, 0, time.UTC)
+	booked.EndDateTime = time.Date(2031, 2, 10, 11, 0, 0, 0, time.UTC)
+	venue.ApplyTo(&booked)
+	assert.NoError(t, booked.Save(ctx))
+
+	imported := newTestEvent(importedAs("venue@import"))
+	imported.DateTime = time.Date(2031, 2, 10, 12, 0, 0, 0, time.UTC)
+	imported.EndDateTime = time.Date(2031, 2, 10, 13, 0, 0, 0, time.UTC)
+
+	results, err := ImportEvents(ctx, userId, []Event{imported}, false)
+	assert.NoError(t, err)
+
+	event, err := GetEventByID(ctx, results[0].EventID)
+	assert.NoError(t, err)
+	event.VenueID = &venue.ID
+	assert.NoError(t, event.UpdateColumns(ctx, []string{"venue_id"}))
+
+	// importing it again onto the booking keeps the venue, so the row conflicts and nothing is written
+	imported.DateTime = time.Date(2031, 2, 10, 10, 30, 0, 0, time.UTC)
+	imported.EndDateTime = time.Date(2031, 2, 10, 11, 30, 0, 0, time.UTC)
+	imported.Name = "Go Workshop Jakarta, moved"
+
+	results, err = ImportEvents(ctx, userId, []Event{newTestEvent(importedAs("other@import")), imported}, false)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Nil(t, results[0].Conflict)
+		assert.Zero(t, results[0].EventID)
+
+		if assert.NotNil(t, results[1].Conflict) {
+			assert.Equal(t, booked.ID, results[1].Conflict.Conflicts[0].EventID)
+		}
+	}
+
+	stored, err := GetEventByID(ctx, event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, event.Name, stored.Name)
+	assert.True(t, stored.DateTime.Equal(time.Date(2031, 2, 10, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestNewVenueConflictError_HidesOtherOrganizersEvents(t *testing.T) {
+	start := time.Date(2031, 2, 17, 10, 0, 0, 0, time.UTC)
+	other := int64(7)
+
+	err := newVenueConflictError(WithAuditActor(context.Background(), AuditActor{UserID: 1}), []Event{
+		{ID: 8, Name: "Go Meetup", Status: EventStatusPublished, DateTime: start, UserID: 2},
+		{ID: 9, Name: "Secret Launch", Status: EventStatusDraft, DateTime: start, UserID: 2},
+		{ID: 10, Name: "Own Draft", Status: EventStatusDraft, DateTime: start, UserID: 1},
+		{ID: 11, Name: "Acme Meetup", Status: EventStatusPublished, DateTime: start, UserID: 2, OrganizationID: &other},
+	})
+
+	names := []string{}
+	for _, c := range err.Conflicts {
+		names = append(names, c.Name)
+	}
+
+	// another organizer's draft and another organization's event only give their time away
+	assert.Equal(t, []string{"Go Meetup", "", "Own Draft", ""}, names)
+}
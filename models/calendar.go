@@ -0,0 +1,212 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"example.com/event/db"
+	"example.com/event/utils"
+)
+
+// Calendar feeds list occurrences from CalendarFeedPast ago up to CalendarFeedAhead from now
+const (
+	CalendarFeedPast  = 30 * 24 * time.Hour
+	CalendarFeedAhead = MaxOccurrenceWindow
+)
+
+// RotateCalendarToken issues a new calendar feed token for the user, the previous one stops working.
+// Only its hash is stored, the token itself is returned this once.
+func RotateCalendarToken(ctx context.Context, userId int64) (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	createdAt := time.Now().UTC()
+
+	query := `
+	INSERT INTO calendar_feed_tokens (user_id, token_hash, created_at) VALUES (?, ?, ?)
+	ON CONFLICT (user_id) DO UPDATE SET token_hash = excluded.token_hash, created_at = excluded.created_at
+	`
+
+	err = withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getCalendarFeedCreatedAt(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, userId, hashCalendarToken(token), createdAt)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, AuditUpdate, AuditEntityUser, userId, calendarFeedAuditFields(before), calendarFeedAuditFields(&createdAt))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RevokeCalendarToken disables the user's calendar feed
+func RevokeCalendarToken(ctx context.Context, userId int64) error {
+	return withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getCalendarFeedCreatedAt(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		if before == nil {
+			return ErrCalendarFeedNotFound
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM calendar_feed_tokens WHERE user_id = ?`, userId)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, AuditUpdate, AuditEntityUser, userId, calendarFeedAuditFields(before), calendarFeedAuditFields(nil))
+	})
+}
+
+// GetUserIDByCalendarToken returns the user a feed token was issued to
+func GetUserIDByCalendarToken(ctx context.Context, token string) (int64, error) {
+	var userId int64
+	err := db.DB.QueryRowContext(ctx, `SELECT user_id FROM calendar_feed_tokens WHERE token_hash = ?`, hashCalendarToken(token)).Scan(&userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: unknown or revoked calendar feed token", utils.ErrInvalidToken)
+	}
+
+	return userId, err
+}
+
+// Tokens are random, so a plain SHA-256 is enough to keep a database leak from exposing feeds
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// getCalendarFeedCreatedAt returns when the current feed token was issued, nil when there is none
+func getCalendarFeedCreatedAt(ctx context.Context, q querier, userId int64) (*time.Time, error) {
+	var createdAt time.Time
+	err := q.QueryRowContext(ctx, `SELECT created_at FROM calendar_feed_tokens WHERE user_id = ?`, userId).Scan(&createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdAt, nil
+}
+
+// calendarFeedAuditFields records when the feed token was issued, never the token
+func calendarFeedAuditFields(createdAt *time.Time) map[string]any {
+	return map[string]any{"calendarFeedIssuedAt": createdAt}
+}
+
+// GetRegisteredEvents returns what the user registered to between from and to, drafts and deleted events excluded.
+// Recurring events are expanded into occurrences: all of them for a series registration,
+// otherwise only those the user registered to. Cancelled events and occurrences are kept.
+// The feed is the user's own, so it spans every organization.
+func GetRegisteredEvents(ctx context.Context, userId int64, from, to time.Time) ([]Event, error) {
+	registered, err := getRegisteredOccurrences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(registered) == 0 {
+		return []Event{}, nil
+	}
+
+	placeholders := make([]string, 0, len(registered))
+	args := make([]any, 0, len(registered))
+	for eventId := range registered {
+		placeholders = append(placeholders, "?")
+		args = append(args, eventId)
+	}
+
+	query := `
+	SELECT ` + eventSelectColumns + ` FROM events
+	WHERE id IN (` + strings.Join(placeholders, ", ") + `) AND deleted_at IS NULL AND status != 'draft'
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := expandOccurrences(ctx, db.DB, events, from, to, EventStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep whole events and series, and the single occurrences registered to
+	return slices.DeleteFunc(expanded, func(e Event) bool {
+		starts := registered[e.ID]
+		if e.OccurrenceStart == nil || slices.Contains(starts, nil) {
+			return false
+		}
+
+		return !slices.ContainsFunc(starts, func(start *time.Time) bool {
+			return start.Equal(*e.OccurrenceStart)
+		})
+	}), nil
+}
+
+// getRegisteredOccurrences maps the events the user registered to onto the registered occurrence starts,
+// nil standing for the whole event or series. Declined registrations are left out.
+func getRegisteredOccurrences(ctx context.Context, userId int64) (map[int64][]*time.Time, error) {
+	rows, err := db.DB.QueryContext(ctx, `SELECT event_id, occurrence_start FROM registrations WHERE user_id = ? AND status != 'declined'`, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	registered := map[int64][]*time.Time{}
+	for rows.Next() {
+		var eventId int64
+		var start *time.Time
+
+		err := rows.Scan(&eventId, &start)
+		if err != nil {
+			return nil, err
+		}
+
+		registered[eventId] = append(registered[eventId], start)
+	}
+
+	return registered, rows.Err()
+}
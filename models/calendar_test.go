@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/event/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarToken_RotateAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	const userId = int64(9101)
+
+	first, err := RotateCalendarToken(ctx, userId)
+	assert.NoError(t, err)
+	assert.Len(t, first, 43)
+
+	found, err := GetUserIDByCalendarToken(ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, userId, found)
+
+	// rotating replaces the previous token
+	second, err := RotateCalendarToken(ctx, userId)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	_, err = GetUserIDByCalendarToken(ctx, first)
+	assert.ErrorIs(t, err, utils.ErrInvalidToken)
+
+	err = RevokeCalendarToken(ctx, userId)
+	assert.NoError(t, err)
+
+	_, err = GetUserIDByCalendarToken(ctx, second)
+	assert.ErrorIs(t, err, utils.ErrInvalidToken)
+
+	err = RevokeCalendarToken(ctx, userId)
+	assert.ErrorIs(t, err, ErrCalendarFeedNotFound)
+
+	// only the issue time is audited, never the token
+	entries, err := GetAuditLog(ctx, AuditFilter{EntityType: AuditEntityUser, EntityID: userId, Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		for _, entry := range entries {
+			assert.NotContains(t, string(entry.After), first)
+			assert.NotContains(t, string(entry.After), second)
+		}
+	}
+}
+
+func TestGetRegisteredEvents(t *testing.T) {
+	ctx := context.Background()
+	const userId = int64(9102)
+
+	single := newPublishedTestEvent(t)
+	err := single.RegisterEvent(ctx, userId, nil)
+	assert.NoError(t, err)
+
+	series := newPublishedTestEvent(t, recurring("FREQ=WEEKLY;COUNT=4"))
+	err = series.RegisterEvent(ctx, userId, nil)
+	assert.NoError(t, err)
+
+	picked := newPublishedTestEvent(t, recurring("FREQ=WEEKLY;COUNT=4"))
+	second := time.Date(2031, 1, 14, 9, 0, 0, 0, time.UTC)
+	err = picked.RegisterEvent(ctx, userId, &second)
+	assert.NoError(t, err)
+
+	deleted := newPublishedTestEvent(t)
+	err = deleted.RegisterEvent(ctx, userId, nil)
+	assert.NoError(t, err)
+	err = deleted.Delete(ctx)
+	assert.NoError(t, err)
+
+	events, err := GetRegisteredEvents(ctx, userId, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2031, 12, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+
+	counts := map[int64]int{}
+	for _, e := range events {
+		counts[e.ID]++
+		if e.ID == picked.ID {
+			assert.True(t, second.Equal(*e.OccurrenceStart))
+		}
+	}
+
+	// the whole series, a single occurrence of the other, nothing deleted
+	assert.Equal(t, map[int64]int{single.ID: 1, series.ID: 4, picked.ID: 1}, counts)
+
+	events, err = GetRegisteredEvents(ctx, int64(9103), time.Now(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
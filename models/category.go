@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"time"
+
+	"example.com/event/db"
+)
+
+// Category slugs are lowercase letters, digits and inner hyphens, e.g. "workshop"
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Category is a topic events are browsed by, managed by admins
+type Category struct {
+	ID int64
+
+	// Slug identifies the category in URLs and filters (?category=workshop)
+	Slug        string
+	Name        string
+	Description string
+
+	// EventCount is only set by GetAllCategories, the events listed by default in the category
+	EventCount int
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Columns selected for a Category, in the order scanCategory expects them
+const categorySelectColumns = `id, slug, name, description, created_at, updated_at`
+
+func scanCategory(row scanner) (Category, error) {
+	var c Category
+
+	err := row.Scan(&c.ID, &c.Slug, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+
+	return c, err
+}
+
+// ValidCategorySlug reports whether slug can identify a category
+func ValidCategorySlug(slug string) bool {
+	return categorySlugPattern.MatchString(slug)
+}
+
+// Save inserts the category, ErrCategorySlugTaken when another category has the slug
+func (c *Category) Save(ctx context.Context) error {
+	query := `
+	INSERT INTO categories (slug, name, description, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?)
+	`
+
+	now := time.Now().UTC()
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, c.Slug, c.Name, c.Description, now, now)
+		if isUniqueViolation(err) {
+			return ErrCategorySlugTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		c.ID, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		c.CreatedAt = now
+		c.UpdatedAt = now
+
+		return writeAudit(ctx, tx, AuditCreate, AuditEntityCategory, c.ID, nil, c.auditFields())
+	})
+}
+
+// GetAllCategories lists every category by name, with the number of public events of the tenant listed by default in each
+func GetAllCategories(ctx context.Context) ([]Category, error) {
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+
+	query := `
+	SELECT ` + categorySelectColumns + `,
+	(SELECT COUNT(*) FROM events WHERE events.category_id = categories.id AND deleted_at IS NULL AND status IN (?, ?) AND visibility = 'public' AND ` + tenant + `)
+	FROM categories ORDER BY name, id
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, append([]any{EventStatusPublished, EventStatusCompleted}, tenantArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	categories := []Category{}
+	for rows.Next() {
+		var count int
+		c, err := scanCategory(scannerWith(rows, &count))
+		if err != nil {
+			return nil, err
+		}
+
+		c.EventCount = count
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+// GetCategoryByID returns the category or ErrCategoryNotFound
+func GetCategoryByID(ctx context.Context, categoryId int64) (*Category, error) {
+	return getCategory(ctx, db.DB, `id = ?`, categoryId)
+}
+
+// GetCategoryBySlug returns the category or ErrCategoryNotFound
+func GetCategoryBySlug(ctx context.Context, slug string) (*Category, error) {
+	return getCategory(ctx, db.DB, `slug = ?`, slug)
+}
+
+func getCategory(ctx context.Context, q querier, condition string, arg any) (*Category, error) {
+	c, err := scanCategory(q.QueryRowContext(ctx, `SELECT `+categorySelectColumns+` FROM categories WHERE `+condition, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Update writes the slug, name and description of the category
+func (c *Category) Update(ctx context.Context) error {
+	query := `
+	UPDATE categories SET slug = ?, name = ?, description = ?, updated_at = ?
+	WHERE id = ?
+	`
+
+	updatedAt := time.Now().UTC()
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getCategory(ctx, tx, `id = ?`, c.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, c.Slug, c.Name, c.Description, updatedAt, c.ID)
+		if isUniqueViolation(err) {
+			return ErrCategorySlugTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		c.CreatedAt = before.CreatedAt
+		c.UpdatedAt = updatedAt
+
+		return writeAudit(ctx, tx, AuditUpdate, AuditEntityCategory, c.ID, before.auditFields(), c.auditFields())
+	})
+}
+
+// Delete removes the category unless an event is still listed under it (ErrCategoryInUse).
+// Soft deleted events lose their reference to it.
+func (c Category) Delete(ctx context.Context) error {
+	return withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getCategory(ctx, tx, `id = ?`, c.ID)
+		if err != nil {
+			return err
+		}
+
+		var inUse bool
+		err = tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE category_id = ? AND deleted_at IS NULL)`, c.ID).Scan(&inUse)
+		if err != nil {
+			return err
+		}
+
+		if inUse {
+			return ErrCategoryInUse
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE events SET category_id = NULL WHERE category_id = ?`, c.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM categories WHERE id = ?`, c.ID)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, AuditDelete, AuditEntityCategory, c.ID, before.auditFields(), nil)
+	})
+}
+
+// auditFields are the category fields recorded in the audit log
+func (c Category) auditFields() map[string]any {
+	return map[string]any{
+		"slug":        c.Slug,
+		"name":        c.Name,
+		"description": c.Description,
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"example.com/event/db"
+)
+
+// DeletedEventRetention is how long a soft deleted event can be restored before it is purged
+var DeletedEventRetention = 30 * 24 * time.Hour
+
+// GetDeletedEventByID returns a soft deleted event of the tenant, ErrEventNotFound when there is no deleted event with that id
+func GetDeletedEventByID(ctx context.Context, eventId int64) (*Event, error) {
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+
+	query := `
+	SELECT ` + eventSelectColumns + ` FROM events WHERE id = ? AND deleted_at IS NOT NULL AND ` + tenant + `
+	`
+
+	row := db.DB.QueryRowContext(ctx, query, append([]any{eventId}, tenantArgs...)...)
+
+	e, err := scanEvent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// Restore undoes a soft delete within DeletedEventRetention, event.Version must be the version the caller read
+func (event *Event) Restore(ctx context.Context) error {
+	if event.DeletedAt == nil {
+		return ErrEventNotFound
+	}
+
+	if time.Since(*event.DeletedAt) > DeletedEventRetention {
+		return ErrRestoreExpired
+	}
+
+	query := `
+	UPDATE events SET deleted_at = NULL, version = version + 1, updated_at = ?
+	WHERE id = ? AND version = ? AND deleted_at IS NOT NULL
+	`
+
+	updatedAt := time.Now().UTC()
+
+	err := event.writeVersioned(ctx, AuditRestore, query, []any{updatedAt}, nil)
+	if err != nil {
+		return err
+	}
+
+	event.DeletedAt = nil
+	event.UpdatedAt = updatedAt
+
+	return nil
+}
+
+// PurgeDeletedEvents permanently removes events soft deleted before cutoff together with their
+// registrations, notifications and occurrence overrides, and registrations left behind by events that no longer exist.
+// It returns the number of events removed.
+func PurgeDeletedEvents(ctx context.Context, cutoff time.Time) (int64, error) {
+	// deleted_at is stored in UTC, compare like with like
+	cutoff = cutoff.UTC()
+
+	expired := `SELECT id FROM events WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	var purged int64
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		actor := AuditActorFrom(ctx)
+
+		// Record what is about to go, the purge itself has no before/after worth keeping
+		_, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_log (actor_id, action, entity_type, entity_id, request_id, ip, created_at)
+		SELECT NULL, ?, ?, id, ?, ?, ? FROM events WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		`, AuditPurge, AuditEntityEvent, actor.RequestID, actor.IP, time.Now().UTC(), cutoff)
+		if err != nil {
+			return err
+		}
+
+		// Dependent rows first, events rows are still there to select from
+		for _, query := range []string{
+			`DELETE FROM registrations WHERE event_id IN (` + expired + `)`,
+			`DELETE FROM notifications WHERE event_id IN (` + expired + `)`,
+			`DELETE FROM event_occurrences WHERE event_id IN (` + expired + `)`,
+			`DELETE FROM event_tags WHERE event_id IN (` + expired + `)`,
+			`DELETE FROM event_members WHERE event_id IN (` + expired + `)`,
+			`DELETE FROM event_invitations WHERE event_id IN (` + expired + `)`,
+			`DELETE FROM rsvp_changes WHERE event_id IN (` + expired + `)`,
+		} {
+			_, err = tx.ExecContext(ctx, query, cutoff)
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM events WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+		if err != nil {
+			return err
+		}
+
+		purged, err = result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		// Left behind by hard deletes before soft deletion existed
+		_, err = tx.ExecContext(ctx, `DELETE FROM registrations WHERE event_id NOT IN (SELECT id FROM events)`)
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
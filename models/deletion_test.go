@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/event/db"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPublishedTestEvent saves a published event, changes adjust it before it is saved
+func newPublishedTestEvent(t *testing.T, changes ...func(*Event)) Event {
+	event := newTestEvent(changes...)
+	event.Status = EventStatusPublished
+
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	return event
+}
+
+func TestEventDelete_SoftDeletesAndRestores(t *testing.T) {
+	event := newPublishedTestEvent(t)
+
+	const attendee = int64(5151)
+	err := event.RegisterEvent(context.Background(), attendee, nil)
+	assert.NoError(t, err)
+
+	err = event.Delete(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, event.DeletedAt)
+
+	// hidden from reads and listings
+	_, err = GetEventByID(context.Background(), event.ID)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	events, err := GetAllEvents(context.Background(), EventFilter{})
+	assert.NoError(t, err)
+	for _, e := range events {
+		assert.NotEqual(t, event.ID, e.ID)
+	}
+
+	// deleting twice finds nothing to delete
+	again := event
+	err = again.Delete(context.Background())
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	deleted, err := GetDeletedEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+
+	err = deleted.Restore(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, deleted.DeletedAt)
+
+	// registrations come back with the event
+	restored, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+
+	registered, err := restored.IsRegistered(context.Background(), attendee)
+	assert.NoError(t, err)
+	assert.True(t, registered)
+}
+
+func TestEventRestore_ErrorExpired(t *testing.T) {
+	event := newPublishedTestEvent(t)
+
+	err := event.Delete(context.Background())
+	assert.NoError(t, err)
+
+	deletedAt := time.Now().Add(-DeletedEventRetention - time.Hour)
+	event.DeletedAt = &deletedAt
+
+	err = event.Restore(context.Background())
+	assert.ErrorIs(t, err, ErrRestoreExpired)
+}
+
+func TestPurgeDeletedEvents(t *testing.T) {
+	expired := newPublishedTestEvent(t)
+	recent := newPublishedTestEvent(t)
+
+	const attendee = int64(6161)
+	for _, e := range []*Event{&expired, &recent} {
+		err := e.RegisterEvent(context.Background(), attendee, nil)
+		assert.NoError(t, err)
+
+		err = e.Delete(context.Background())
+		assert.NoError(t, err)
+	}
+
+	// registration of an event hard deleted before soft deletion existed
+	_, err := db.DB.Exec(`INSERT INTO registrations (event_id, user_id) VALUES (?, ?)`, 999999, attendee)
+	assert.NoError(t, err)
+
+	_, err = db.DB.Exec(`UPDATE events SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Add(-48*time.Hour), expired.ID)
+	assert.NoError(t, err)
+
+	purged, err := PurgeDeletedEvents(context.Background(), time.Now().Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, purged, int64(1))
+
+	_, err = GetDeletedEventByID(context.Background(), expired.ID)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	_, err = GetDeletedEventByID(context.Background(), recent.ID)
+	assert.NoError(t, err)
+
+	var registrations int
+	err = db.DB.QueryRow(`SELECT COUNT(*) FROM registrations WHERE event_id IN (?, ?)`, expired.ID, 999999).Scan(&registrations)
+	assert.NoError(t, err)
+	assert.Zero(t, registrations)
+
+	registered, err := recent.IsRegistered(context.Background(), attendee)
+	assert.NoError(t, err)
+	assert.True(t, registered)
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Domain errors returned by models and handlers, routes map them to HTTP problems
+var (
+	ErrEventNotFound      = errors.New("event not found")
+	ErrNotOwner           = errors.New("only the event owner can perform this action")
+	ErrAdminRequired      = errors.New("only admins can perform this action")
+	ErrVersionMismatch    = errors.New("event was modified since it was read")
+	ErrAlreadyRegistered  = errors.New("user is already registered to this event")
+	ErrNotRegistered      = errors.New("user is not registered to this event")
+	ErrNotCheckedIn       = errors.New("user is not checked in to this event")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailTaken         = errors.New("email is already registered")
+
+	ErrInvalidStatusTransition = errors.New("event status cannot change this way")
+	ErrEventNotOpen            = errors.New("event is not open for registration")
+
+	ErrRestoreExpired = errors.New("event was deleted too long ago to be restored")
+
+	ErrOccurrenceNotFound = errors.New("event has no such occurrence")
+
+	ErrCalendarFeedNotFound = errors.New("user has no calendar feed")
+
+	ErrVenueNotFound = errors.New("venue not found")
+	ErrNotVenueOwner = errors.New("only the venue owner or an admin can perform this action")
+	ErrVenueInUse    = errors.New("events still take place at this venue")
+	ErrEventFull     = errors.New("event has reached its capacity")
+	ErrVenueConflict = errors.New("venue is already booked at that time")
+
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrCategorySlugTaken = errors.New("another category has this slug")
+	ErrCategoryInUse     = errors.New("events are still listed under this category")
+
+	ErrUserNotFound     = errors.New("no user has this email")
+	ErrMemberNotFound   = errors.New("user is not a member")
+	ErrAlreadyMember    = errors.New("user is already a member")
+	ErrRoleNotPermitted = errors.New("your role does not allow this action")
+
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationSlugTaken = errors.New("another organization has this slug")
+	ErrNotOrganizationMember = errors.New("only members of the organization can perform this action")
+	ErrLastOwner             = errors.New("an organization needs at least one owner")
+
+	ErrInvitationNotFound = errors.New("user is not invited")
+	ErrAlreadyInvited     = errors.New("user is already invited")
+	ErrInvalidInvitation  = errors.New("invitation link is invalid or has expired")
+	ErrNotInvited         = errors.New("only invited users can register to this private event")
+)
+
+// FieldError describes why a single request field is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a request
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{
+		Fields: []FieldError{{Field: field, Message: message}},
+	}
+}
+
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Field+": "+f.Message)
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// isUniqueViolation reports whether err is SQLite rejecting a duplicate in a UNIQUE column
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
@@ -1,7 +1,14 @@
 package models
 
 import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"example.com/event/db"
@@ -9,54 +16,280 @@ import (
 
 type Event struct {
 	ID          int64
-	Name        string    `binding:"required"`
-	Description string    `binding:"required"`
-	Location    string    `binding:"required"`
-	DateTime    time.Time `binding:"required"`
+	Name        string
+	Description string
+	Location    string
+	DateTime    time.Time
 	UserID      int64
+
+	// EndDateTime is when the event finishes, DateTime and EndDateTime are stored in UTC
+	EndDateTime time.Time
+
+	// TimeZone is the IANA name (e.g. Asia/Jakarta) the event is scheduled in,
+	// used to render local times and to expand recurrences across DST changes
+	TimeZone string
+
+	// Version is incremented on every write, writes only succeed
+	// when it still matches the version stored in the database
+	Version   int64
+	UpdatedAt time.Time
+
+	Status EventStatus
+
+	// Visibility is who can find and see the event, public when empty
+	Visibility EventVisibility
+
+	// CancellationReason is set when the event is cancelled
+	CancellationReason string
+
+	// DeletedAt is set while the event is soft deleted and can still be restored
+	DeletedAt *time.Time
+
+	// Recurrence is nil for one-off events
+	Recurrence *Recurrence
+
+	// ExternalUID is the UID of the event in the calendar or file it was imported from
+	ExternalUID string
+
+	// Latitude and Longitude place the event on a map, both set or both nil
+	Latitude  *float64
+	Longitude *float64
+
+	Address Address
+
+	// VenueID references the venue the event takes place at, nil when it only has a location
+	VenueID *int64
+
+	// Capacity caps the registrations, per occurrence of a recurring event. Nil is unlimited.
+	Capacity *int
+
+	// CategoryID references the category the event is listed under, nil when uncategorized.
+	// Category is its slug, read along with the event.
+	CategoryID *int64
+	Category   string
+
+	// Tags are lowercase names (see NormalizeTags), kept in event_tags
+	Tags []string
+
+	// OrganizationID is the organization hosting the event, nil outside any organization.
+	// It is taken from the tenant of the context the event is created with.
+	OrganizationID *int64
+
+	// DistanceKm is only set on events listed near a point, the great-circle distance to it
+	DistanceKm *float64
+
+	// OccurrenceStart is only set on an occurrence expanded from a recurring event,
+	// it identifies the occurrence even when an override moved it
+	OccurrenceStart *time.Time
+}
+
+// Columns selected for an Event, in the order scanEvent expects them
+const eventSelectColumns = `id, name, description, location, datetime, user_id, version, updated_at, status, cancellation_reason, deleted_at, recurrence_rule, recurrence_exdates, end_datetime, time_zone, external_uid, latitude, longitude, address_street, address_city, address_region, address_postal_code, address_country, venue_id, capacity, category_id, organization_id, visibility, ` + eventCategoryColumn + `, ` + eventTagsColumn
+
+// Subqueries reading the category slug and the comma-separated tags of the events row
+const (
+	eventCategoryColumn = `(SELECT slug FROM categories WHERE categories.id = events.category_id)`
+	eventTagsColumn     = `(SELECT group_concat(tags.name) FROM event_tags JOIN tags ON tags.id = event_tags.tag_id WHERE event_tags.event_id = events.id)`
+)
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
-func (e *Event) Save() error {
+func scanEvent(row scanner) (Event, error) {
+	var e Event
+	var rule, exdates string
+	var end sql.NullTime
+	var category, tags sql.NullString
+
+	err := row.Scan(&e.ID, &e.Name, &e.Description, &e.Location, &e.DateTime, &e.UserID, &e.Version, &e.UpdatedAt, &e.Status, &e.CancellationReason, &e.DeletedAt, &rule, &exdates, &end, &e.TimeZone, &e.ExternalUID, &e.Latitude, &e.Longitude, &e.Address.Street, &e.Address.City, &e.Address.Region, &e.Address.PostalCode, &e.Address.Country, &e.VenueID, &e.Capacity, &e.CategoryID, &e.OrganizationID, &e.Visibility, &category, &tags)
+	if err != nil {
+		return e, err
+	}
+
+	e.Category = category.String
+	e.Tags = scanTags(tags.String)
+	e.EndDateTime = end.Time
+	e.normalizeSchedule()
+
+	e.Recurrence, err = scanRecurrence(rule, exdates)
+
+	return e, err
+}
+
+// Save inserts the event, new events are drafts unless e.Status says otherwise
+func (e *Event) Save(ctx context.Context) error {
+	var created Event
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		created, err = e.insert(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	*e = created
+
+	return nil
+}
+
+// insert writes the event in tx and audits it, returning the stored event
+func (e Event) insert(ctx context.Context, tx *sql.Tx) (Event, error) {
 	query := `
-	INSERT INTO events (name, description, location, datetime, user_id) 
-	VALUES (?, ?, ?, ?, ?)
+	INSERT INTO events (name, description, location, datetime, user_id, version, updated_at, status, recurrence_rule, recurrence_exdates, end_datetime, time_zone, external_uid, latitude, longitude, address_street, address_city, address_region, address_postal_code, address_country, venue_id, capacity, category_id, organization_id, visibility) 
+	VALUES (?, ?, ?, ?, ?, 1, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	// DB.Prepare is used to create a prepared statement for execution
-	stmt, err := db.DB.Prepare(query)
+	updatedAt := time.Now().UTC()
+
+	if e.Status == "" {
+		e.Status = EventStatusDraft
+	}
+	if e.Visibility == "" {
+		e.Visibility = VisibilityPublic
+	}
+
+	e.normalizeSchedule()
+	rule, exdates := e.recurrenceColumns()
+	e.OrganizationID = tenantID(ctx)
+
+	err := requireNoVenueConflicts(ctx, tx, e)
 	if err != nil {
-		return err
+		return e, err
+	}
+
+	// tx.PrepareContext is used to create a prepared statement for execution
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return e, err
 	}
 
 	// Ensure the statement is closed after execution
 	defer stmt.Close()
 
-	// stmt.Exec is used to execute a prepared statement with the given arguments
-	result, err := stmt.Exec(e.Name, e.Description, e.Location, e.DateTime, e.UserID)
+	// stmt.ExecContext is used to execute a prepared statement with the given arguments
+	result, err := stmt.ExecContext(ctx, e.Name, e.Description, e.Location, e.DateTime, e.UserID, updatedAt, e.Status, rule, exdates, e.EndDateTime, e.TimeZone, e.ExternalUID, e.Latitude, e.Longitude, e.Address.Street, e.Address.City, e.Address.Region, e.Address.PostalCode, e.Address.Country, e.VenueID, e.Capacity, e.CategoryID, e.OrganizationID, e.Visibility)
 	if err != nil {
-		return err
+		return e, err
 	}
 
-	// Get the last inserted ID and assign it to the event
-	id, err := result.LastInsertId()
+	// Get the last inserted ID to assign it to the event
+	e.ID, err = result.LastInsertId()
 	if err != nil {
-		return err
+		return e, err
 	}
 
-	e.ID = id
+	e.Version = 1
+	e.UpdatedAt = updatedAt
 
-	return nil
+	e.Tags = NormalizeTags(e.Tags)
+	err = writeTags(ctx, tx, e.ID, e.Tags)
+	if err != nil {
+		return e, err
+	}
+
+	return e, writeAudit(ctx, tx, AuditCreate, AuditEntityEvent, e.ID, nil, e.auditFields())
+}
+
+// EventFilter narrows GetAllEvents
+type EventFilter struct {
+	// Statuses to list, DefaultEventStatuses when empty
+	Statuses []EventStatus
+
+	// ViewerID is the user listing events (0 when anonymous), drafts are only listed for their owner and members,
+	// private events for the users invited to them, unlisted events are not listed
+	ViewerID int64
+
+	// From and To (exclusive) select events starting within a window, recurring events are expanded
+	// into their occurrences. Both zero lists every event once.
+	From time.Time
+	To   time.Time
+
+	// VenueID (optional) only lists events at the venue
+	VenueID int64
+
+	// Category (optional slug) only lists events in the category, Tags events carrying every tag
+	Category string
+	Tags     []string
+
+	// Near (optional) only lists events within RadiusKm of it, closest first
+	Near     *GeoPoint
+	RadiusKm float64
 }
 
-func GetAllEvents() ([]Event, error) {
+// windowed reports whether the filter selects a window
+func (f EventFilter) windowed() bool {
+	return !f.From.IsZero() || !f.To.IsZero()
+}
+
+func GetAllEvents(ctx context.Context, filter EventFilter) ([]Event, error) {
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = DefaultEventStatuses
+	}
+
+	placeholders := make([]string, 0, len(statuses))
+	args := make([]any, 0, len(statuses)+4)
+	for _, status := range statuses {
+		placeholders = append(placeholders, "?")
+		args = append(args, status)
+	}
+
+	args = append(args, filter.ViewerID, filter.ViewerID, filter.ViewerID, filter.ViewerID)
+
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+	args = append(args, tenantArgs...)
+
+	// Start times are stored in UTC, so they compare as text. A series starting before the window
+	// may still have occurrences in it and is expanded below.
+	window := ""
+	if filter.windowed() {
+		window = "AND datetime < ? AND (recurrence_rule != '' OR datetime >= ?)"
+		args = append(args, filter.To.UTC(), filter.From.UTC())
+	}
+
+	venue := ""
+	if filter.VenueID != 0 {
+		venue = "AND venue_id = ?"
+		args = append(args, filter.VenueID)
+	}
+
+	tagged, tagArgs := tagConditions(filter.Category, NormalizeTags(filter.Tags))
+	args = append(args, tagArgs...)
+
+	// The box around the point is narrowed down to the circle once the events are read
+	near := ""
+	if filter.Near != nil {
+		condition, nearArgs := nearCondition(*filter.Near, filter.RadiusKm)
+		near = "AND " + condition
+		args = append(args, nearArgs...)
+	}
+
 	query := `
-	SELECT * FROM events
+	SELECT ` + eventSelectColumns + ` FROM events
+	WHERE deleted_at IS NULL
+	AND status IN (` + strings.Join(placeholders, ", ") + `) AND ` + visibleEventCondition("") + `
+	AND ` + tenant + `
+	` + window + `
+	` + venue + `
+	` + tagged + `
+	` + near + `
+	ORDER BY datetime, id
 	`
 
-	// DB.Query is used to execute a query that returns rows
-	rows, err := db.DB.Query(query)
+	// DB.QueryContext is used to execute a query that returns rows
+	rows, err := db.DB.QueryContext(ctx, query, args...)
 	if err != nil {
-		fmt.Println("Error preparing statement:", err)
+		slog.ErrorContext(ctx, "could not query events", "error", err)
 		return nil, err
 	}
 
@@ -66,9 +299,7 @@ func GetAllEvents() ([]Event, error) {
 	// Slice to hold the retrieved events
 	events := []Event{}
 	for rows.Next() {
-		var e Event
-
-		err := rows.Scan(&e.ID, &e.Name, &e.Description, &e.Location, &e.DateTime, &e.UserID)
+		e, err := scanEvent(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -76,66 +307,373 @@ func GetAllEvents() ([]Event, error) {
 		events = append(events, e)
 	}
 
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Near != nil {
+		events = withinRadius(events, *filter.Near, filter.RadiusKm)
+	}
+
+	if filter.windowed() {
+		events, err = expandOccurrences(ctx, db.DB, events, filter.From, filter.To, statuses)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Near != nil {
+		// Stable, so events at the same distance (occurrences of a series) stay in start order
+		slices.SortStableFunc(events, func(a, b Event) int {
+			return cmp.Compare(*a.DistanceKm, *b.DistanceKm)
+		})
+	}
+
 	return events, nil
 }
 
-func GetEventByID(eventId int64) (*Event, error) {
-	query := `
-	SELECT * FROM events WHERE id = ?
-	`
+// withinRadius keeps the events at most radiusKm from center, setting their DistanceKm
+func withinRadius(events []Event, center GeoPoint, radiusKm float64) []Event {
+	near := events[:0]
+	for _, e := range events {
+		point, ok := e.Point()
+		if !ok {
+			continue
+		}
 
-	// QueryRow is used to execute a query that is expected to return at most one row
-	row := db.DB.QueryRow(query, eventId)
+		distance := DistanceKm(center, point)
+		if distance > radiusKm {
+			continue
+		}
 
-	var e Event
+		e.DistanceKm = &distance
+		near = append(near, e)
+	}
 
-	err := row.Scan(&e.ID, &e.Name, &e.Description, &e.Location, &e.DateTime, &e.UserID)
+	return near
+}
+
+// expandOccurrences replaces events by their occurrences between from and to, ordered by start.
+// Occurrences cancelled by an override are dropped unless statuses asks for cancelled events.
+func expandOccurrences(ctx context.Context, q querier, events []Event, from, to time.Time, statuses []EventStatus) ([]Event, error) {
+	var recurringIds []int64
+	for _, e := range events {
+		if e.Recurrence != nil {
+			recurringIds = append(recurringIds, e.ID)
+		}
+	}
+
+	overrides, err := getOccurrenceOverrides(ctx, q, recurringIds)
 	if err != nil {
 		return nil, err
 	}
 
-	return &e, nil
+	expanded := []Event{}
+	for _, e := range events {
+		occurrences, err := e.Occurrences(from, to, overrides[e.ID])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, occurrence := range occurrences {
+			if slices.Contains(statuses, occurrence.Status) {
+				expanded = append(expanded, occurrence)
+			}
+		}
+	}
+
+	slices.SortStableFunc(expanded, func(a, b Event) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+
+	return expanded, nil
 }
 
-func (event Event) Update() error {
+// GetEventByID returns the event unless it does not exist, is soft deleted or belongs to another tenant
+func GetEventByID(ctx context.Context, eventId int64) (*Event, error) {
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+
 	query := `
-	UPDATE events 
-	SET name = ?, description = ?, location = ?, datetime = ? 
-	WHERE id = ?
+	SELECT ` + eventSelectColumns + ` FROM events WHERE id = ? AND deleted_at IS NULL AND ` + tenant + `
 	`
 
-	stmt, err := db.DB.Prepare(query)
+	// QueryRowContext is used to execute a query that is expected to return at most one row
+	row := db.DB.QueryRowContext(ctx, query, append([]any{eventId}, tenantArgs...)...)
+
+	e, err := scanEvent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// Update writes every editable column, event.Version must be the version the caller read
+func (event *Event) Update(ctx context.Context) error {
+	return event.UpdateColumns(ctx, editableEventColumns)
+}
+
+// Columns written by a full update, everything the organizer describes the event with
+var editableEventColumns = []string{
+	"name", "description", "location", "datetime", "end_datetime", "time_zone", "recurrence_rule", "recurrence_exdates",
+	"latitude", "longitude", "address_street", "address_city", "address_region", "address_postal_code", "address_country",
+	"venue_id", "capacity", "category_id", "tags", "visibility",
+}
+
+// Columns of events that can be changed after creation, with the value to write for each
+var updatableEventColumns = map[string]func(e Event) any{
+	"name":         func(e Event) any { return e.Name },
+	"description":  func(e Event) any { return e.Description },
+	"location":     func(e Event) any { return e.Location },
+	"datetime":     func(e Event) any { return e.DateTime.UTC() },
+	"end_datetime": func(e Event) any { return e.End().UTC() },
+	"time_zone":    func(e Event) any { return e.TimeZoneName() },
+	"recurrence_rule": func(e Event) any {
+		rule, _ := e.recurrenceColumns()
+		return rule
+	},
+	"recurrence_exdates": func(e Event) any {
+		_, exdates := e.recurrenceColumns()
+		return exdates
+	},
+	"latitude":            func(e Event) any { return e.Latitude },
+	"longitude":           func(e Event) any { return e.Longitude },
+	"address_street":      func(e Event) any { return e.Address.Street },
+	"address_city":        func(e Event) any { return e.Address.City },
+	"address_region":      func(e Event) any { return e.Address.Region },
+	"address_postal_code": func(e Event) any { return e.Address.PostalCode },
+	"address_country":     func(e Event) any { return e.Address.Country },
+	"venue_id":            func(e Event) any { return e.VenueID },
+	"capacity":            func(e Event) any { return e.Capacity },
+	"category_id":         func(e Event) any { return e.CategoryID },
+	"visibility":          func(e Event) any { return e.VisibilityName() },
+}
+
+// UpdateColumns writes only the given columns (e.g. "name", "datetime") of the event,
+// "tags" replaces the tags of the event. event.Version must be the version the caller read.
+func (event *Event) UpdateColumns(ctx context.Context, columns []string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	query, args, updatedAt, err := event.updateColumnsQuery(columns)
 	if err != nil {
 		return err
 	}
 
-	// Ensure the statement is closed after execution
-	defer stmt.Close()
+	var then func(tx *sql.Tx) error
+	if slices.Contains(columns, "tags") {
+		event.Tags = NormalizeTags(event.Tags)
+		then = func(tx *sql.Tx) error {
+			return writeTags(ctx, tx, event.ID, event.Tags)
+		}
+	}
 
-	_, err = stmt.Exec(event.Name, event.Description, event.Location, event.DateTime, event.ID)
+	err = event.writeVersioned(ctx, AuditUpdate, query, args, then)
 	if err != nil {
 		return err
 	}
+
+	event.UpdatedAt = updatedAt
+
 	return nil
 }
 
-func (event Event) Delete() error {
+// updateColumnsQuery builds the versioned UPDATE of the given columns, for writeVersioned.
+// Tags are not a column of events and are left to the caller.
+func (event Event) updateColumnsQuery(columns []string) (string, []any, time.Time, error) {
+	sets := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns)+1)
+	for _, column := range columns {
+		if column == "tags" {
+			continue
+		}
+
+		value, ok := updatableEventColumns[column]
+		if !ok {
+			return "", nil, time.Time{}, fmt.Errorf("column %q of events cannot be updated", column)
+		}
+
+		sets = append(sets, column+" = ?")
+		args = append(args, value(event))
+	}
+
+	updatedAt := time.Now().UTC()
+	sets = append(sets, "version = version + 1", "updated_at = ?")
+	args = append(args, updatedAt)
+
+	// Column names come from updatableEventColumns only, values are still bound as parameters
+	query := "UPDATE events SET " + strings.Join(sets, ", ") + " WHERE id = ? AND version = ?"
+
+	return query, args, updatedAt, nil
+}
+
+// Delete soft deletes the event, event.Version must be the version the caller read.
+// Registrations are kept so a restore brings them back, PurgeDeletedEvents removes both for good.
+func (event *Event) Delete(ctx context.Context) error {
 	query := `
-	DELETE FROM events WHERE id = ?
+	UPDATE events SET deleted_at = ?, version = version + 1, updated_at = ?
+	WHERE id = ? AND version = ? AND deleted_at IS NULL
 	`
 
-	stmt, err := db.DB.Prepare(query)
+	deletedAt := time.Now().UTC()
+
+	err := event.writeVersioned(ctx, AuditDelete, query, []any{deletedAt, deletedAt}, nil)
 	if err != nil {
 		return err
 	}
 
-	// Ensure the statement is closed after execution
-	defer stmt.Close()
+	event.DeletedAt = &deletedAt
+	event.UpdatedAt = deletedAt
 
-	_, err = stmt.Exec(event.ID)
+	return nil
+}
+
+// writeVersioned runs query, an UPDATE of this event taking args followed by id and version,
+// limited to the tenant of ctx, and records the change in the audit log. Moving the event onto
+// other bookings of its venue fails with a *VenueConflictError. then (optional) runs in the same transaction, before the audit.
+// event.Version is incremented once committed, other fields are left to the caller.
+func (event *Event) writeVersioned(ctx context.Context, action, query string, args []any, then func(tx *sql.Tx) error) error {
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		return event.writeVersionedTx(ctx, tx, action, query, args, then)
+	})
 	if err != nil {
 		return err
 	}
 
+	event.Version++
+
+	return nil
+}
+
+// writeVersionedTx is writeVersioned within the caller's transaction, event.Version is left unchanged
+func (event Event) writeVersionedTx(ctx context.Context, tx *sql.Tx, action, query string, args []any, then func(tx *sql.Tx) error) error {
+	before, err := getEventForAudit(ctx, tx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	// Events of other organizations are not found, like they are for reads
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+	query = strings.TrimRight(query, " \n\t") + " AND " + tenant
+
+	args = append(args, event.ID, event.Version)
+	result, err := tx.ExecContext(ctx, query, append(args, tenantArgs...)...)
+	if err != nil {
+		return err
+	}
+
+	err = event.requireVersionMatched(ctx, tx, result)
+	if err != nil {
+		return err
+	}
+
+	if then != nil {
+		err = then(tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	after, err := getEventForAudit(ctx, tx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	// Checked on the stored event, before the transaction lets anyone else book the venue
+	if rebooks(*before, *after) {
+		err = requireNoVenueConflicts(ctx, tx, *after)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeAudit(ctx, tx, action, AuditEntityEvent, event.ID, before.auditFields(), after.auditFields())
+}
+
+// getEventForAudit reads the stored event, soft deleted or not
+func getEventForAudit(ctx context.Context, q querier, eventId int64) (*Event, error) {
+	row := q.QueryRowContext(ctx, `SELECT `+eventSelectColumns+` FROM events WHERE id = ?`, eventId)
+
+	e, err := scanEvent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// auditFields are the event fields recorded in the audit log
+func (e Event) auditFields() map[string]any {
+	return map[string]any{
+		"name":               e.Name,
+		"description":        e.Description,
+		"location":           e.Location,
+		"dateTime":           e.DateTime,
+		"endDateTime":        e.EndDateTime,
+		"timeZone":           e.TimeZone,
+		"userId":             e.UserID,
+		"status":             e.Status,
+		"cancellationReason": e.CancellationReason,
+		"deletedAt":          e.DeletedAt,
+		"recurrence":         e.Recurrence,
+		"externalUid":        e.ExternalUID,
+		"latitude":           e.Latitude,
+		"longitude":          e.Longitude,
+		"address":            e.Address,
+		"venueId":            e.VenueID,
+		"capacity":           e.Capacity,
+		"categoryId":         e.CategoryID,
+		"tags":               e.Tags,
+		"organizationId":     e.OrganizationID,
+		"visibility":         e.Visibility,
+	}
+}
+
+// requireVersionMatched checks a versioned write touched a row, telling apart a (soft) deleted
+// event or one of another tenant from one changed by someone else since it was read
+func (event Event) requireVersionMatched(ctx context.Context, q querier, result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected > 0 {
+		return nil
+	}
+
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+
+	var exists bool
+	err = q.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE id = ? AND deleted_at IS NULL AND `+tenant+`)`, append([]any{event.ID}, tenantArgs...)...).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return ErrVersionMismatch
+	}
+
+	return ErrEventNotFound
+}
+
+// requireAffected returns notFound when a statement did not touch any row
+func requireAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return notFound
+	}
+
 	return nil
 }
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"example.com/event/db"
+	"example.com/event/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// run models tests against a fresh in-memory database
+func TestMain(m *testing.M) {
+	db.OpenDB("file:models_test?mode=memory&cache=shared&_txlock=immediate")
+	os.Exit(m.Run())
+}
+
+// newTestEvent builds an unsaved event, changes adjust it for a test
+func newTestEvent(changes ...func(*Event)) Event {
+	event := Event{
+		Name:        "Go Workshop Jakarta",
+		Description: "A beginner-friendly workshop covering Go fundamentals and best practices.",
+		Location:    "Jakarta",
+		DateTime:    time.Date(2025, 12, 16, 9, 0, 0, 0, time.UTC),
+		UserID:      1,
+	}
+
+	for _, change := range changes {
+		change(&event)
+	}
+
+	return event
+}
+
+func TestEventSave_Success(t *testing.T) {
+	event := newTestEvent()
+
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+	assert.NotZero(t, event.ID)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, event.Name, saved.Name)
+}
+
+func TestEventSave_CancelledContext(t *testing.T) {
+	event := newTestEvent()
+
+	// request already abandoned by client before the query starts
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := event.Save(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, event.ID)
+}
+
+func TestGetAllEvents_DeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	events, err := GetAllEvents(ctx, EventFilter{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, events)
+}
+
+func TestGetAllEvents_LogsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	originalLogger := slog.Default()
+	defer slog.SetDefault(originalLogger)
+	slog.SetDefault(utils.NewLogger(&buf, "info"))
+
+	// the failed query is logged with the id of the request it was made for
+	ctx, cancel := context.WithCancel(utils.WithRequestID(context.Background(), "req-123"))
+	cancel()
+
+	_, err := GetAllEvents(ctx, EventFilter{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var entry map[string]any
+	err = json.Unmarshal(buf.Bytes(), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "could not query events", entry["msg"])
+	assert.Equal(t, "req-123", entry["request_id"])
+}
+
+func TestGetEventByID_CancelledContext(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = GetEventByID(ctx, event.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEventUpdateColumns_OnlyWritesGivenColumns(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	// change two fields but only write location
+	patched := event
+	patched.Name = "Not written"
+	patched.Location = "Bandung"
+
+	err = patched.UpdateColumns(context.Background(), []string{"location"})
+	assert.NoError(t, err)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bandung", saved.Location)
+	assert.Equal(t, event.Name, saved.Name)
+}
+
+func TestEventUpdateColumns_RejectsUnknownColumn(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	err = event.UpdateColumns(context.Background(), []string{"user_id"})
+	assert.Error(t, err)
+}
+
+func TestEventUpdate_ErrorStaleVersion(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	// two clients read version 1
+	first := event
+	second := event
+
+	first.Location = "Bandung"
+	err = first.Update(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), first.Version)
+
+	// second write is based on a version that no longer exists
+	second.Location = "Surabaya"
+	err = second.Update(context.Background())
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bandung", saved.Location)
+}
+
+func TestEventDelete_ErrorStaleVersion(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	stale := event
+	err = event.UpdateColumns(context.Background(), []string{"location"})
+	assert.NoError(t, err)
+
+	err = stale.Delete(context.Background())
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	err = event.Delete(context.Background())
+	assert.NoError(t, err)
+
+	err = event.Delete(context.Background())
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"math"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0088
+
+// kmPerDegree is the length of a degree of latitude, and of longitude at the equator
+const kmPerDegree = earthRadiusKm * math.Pi / 180
+
+// GeoPoint is a WGS 84 position in degrees
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Address is the structured form of an event's location, every part is optional
+type Address struct {
+	Street     string
+	City       string
+	Region     string
+	PostalCode string
+
+	// Country is an ISO 3166-1 alpha-2 code, e.g. ID
+	Country string
+}
+
+// IsZero reports whether no part of the address is set
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// Point returns where the event takes place, false when it has no coordinates
+func (e Event) Point() (GeoPoint, bool) {
+	if e.Latitude == nil || e.Longitude == nil {
+		return GeoPoint{}, false
+	}
+
+	return GeoPoint{Latitude: *e.Latitude, Longitude: *e.Longitude}, true
+}
+
+// DistanceKm is the great-circle distance between two points (haversine formula)
+func DistanceKm(a, b GeoPoint) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat := lat2 - lat1
+	dLng := radians(b.Longitude - a.Longitude)
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// nearCondition is a SQL condition on the latitude and longitude columns selecting the box around center
+// that contains every point within radiusKm. It only prefilters, the box corners are further away.
+func nearCondition(center GeoPoint, radiusKm float64) (string, []any) {
+	dLat := radiusKm / kmPerDegree
+	minLat, maxLat := center.Latitude-dLat, center.Latitude+dLat
+
+	// Near a pole the circle covers every longitude
+	if minLat <= -90 || maxLat >= 90 {
+		return "latitude BETWEEN ? AND ? AND longitude IS NOT NULL", []any{max(minLat, -90), min(maxLat, 90)}
+	}
+
+	// Degrees of longitude shrink towards the poles, the box is widest at its latitude furthest from the equator
+	widest := max(math.Abs(minLat), math.Abs(maxLat))
+	dLng := radiusKm / (kmPerDegree * math.Cos(radians(widest)))
+	if dLng >= 180 {
+		return "latitude BETWEEN ? AND ? AND longitude IS NOT NULL", []any{minLat, maxLat}
+	}
+
+	minLng, maxLng := center.Longitude-dLng, center.Longitude+dLng
+
+	// Boxes crossing the antimeridian are split in two
+	switch {
+	case minLng < -180:
+		return "latitude BETWEEN ? AND ? AND (longitude >= ? OR longitude <= ?)", []any{minLat, maxLat, minLng + 360, maxLng}
+	case maxLng > 180:
+		return "latitude BETWEEN ? AND ? AND (longitude >= ? OR longitude <= ?)", []any{minLat, maxLat, minLng, maxLng - 360}
+	}
+
+	return "latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", []any{minLat, maxLat, minLng, maxLng}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// placedAt names a test event and puts it at the given coordinates
+func placedAt(name string, latitude, longitude float64) func(*Event) {
+	return func(e *Event) {
+		e.Name = name
+		e.Latitude = &latitude
+		e.Longitude = &longitude
+		e.Address = Address{City: "Suva", Country: "FJ"}
+	}
+}
+
+// eventNames lists the names of the given events that are among names, in order
+func eventNames(events []Event, names ...string) []string {
+	found := []string{}
+	for _, e := range events {
+		for _, name := range names {
+			if e.Name == name {
+				found = append(found, name)
+			}
+		}
+	}
+
+	return found
+}
+
+func TestDistanceKm(t *testing.T) {
+	london := GeoPoint{Latitude: 51.5074, Longitude: -0.1278}
+	paris := GeoPoint{Latitude: 48.8566, Longitude: 2.3522}
+
+	assert.InDelta(t, 343.6, DistanceKm(london, paris), 1)
+	assert.InDelta(t, DistanceKm(london, paris), DistanceKm(paris, london), 1e-9)
+	assert.Zero(t, DistanceKm(paris, paris))
+}
+
+func TestGetAllEvents_NearSortsByDistance(t *testing.T) {
+	// Around the antimeridian, the bounding box wraps from 180 to -180
+	closest := newPublishedTestEvent(t, placedAt("Near antimeridian east", -17.8, -179.9))
+	newPublishedTestEvent(t, placedAt("Near antimeridian west", -17.8, 179.5))
+	newPublishedTestEvent(t, placedAt("Near antimeridian far", -17.8, 178.0))
+
+	// Events without coordinates are never near anything
+	newPublishedTestEvent(t)
+
+	filter := EventFilter{Near: &GeoPoint{Latitude: -17.8, Longitude: 179.9}, RadiusKm: 50}
+
+	events, err := GetAllEvents(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Near antimeridian east", "Near antimeridian west"}, eventNames(events,
+		"Near antimeridian east", "Near antimeridian west", "Near antimeridian far"))
+
+	for _, e := range events {
+		if assert.NotNil(t, e.DistanceKm) {
+			assert.LessOrEqual(t, *e.DistanceKm, 50.0)
+		}
+
+		if e.ID == closest.ID {
+			assert.InDelta(t, 21.2, *e.DistanceKm, 0.5)
+			assert.Equal(t, Address{City: "Suva", Country: "FJ"}, e.Address)
+		}
+	}
+}
+
+func TestGetAllEvents_NearPole(t *testing.T) {
+	// Across the pole, longitudes are far apart but the events are not
+	newPublishedTestEvent(t, placedAt("Near pole", 89.9, 180))
+
+	events, err := GetAllEvents(context.Background(), EventFilter{Near: &GeoPoint{Latitude: 89.9, Longitude: 0}, RadiusKm: 30})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Near pole"}, eventNames(events, "Near pole"))
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// MaxImportEvents caps how many events a single import may contain
+const MaxImportEvents = 500
+
+// What importing an event did, or would do in a dry run
+const (
+	ImportCreate    = "create"
+	ImportUpdate    = "update"
+	ImportUnchanged = "unchanged"
+)
+
+type ImportResult struct {
+	// EventID is 0 for events a dry run would create
+	EventID int64
+	Action  string
+
+	// Conflict lists the bookings of its venue an update would overlap, nothing is imported when a result has one
+	Conflict *VenueConflictError
+}
+
+// errDryRun rolls back the transaction of a dry run, errImportConflicts one where events ran into other bookings
+var (
+	errDryRun          = errors.New("dry run")
+	errImportConflicts = errors.New("import conflicts with venue bookings")
+)
+
+// ImportEvents creates or updates the user's events in one transaction, all or nothing, and reports
+// what happened to each in the same order. An event with an ExternalUID updates the user's event with
+// that UID (deleted ones aside) instead of adding another, events that did not change are not written.
+// A dry run goes through the same steps, then rolls back. So does an import where an event would overlap
+// other bookings of its venue, the results tell which.
+func ImportEvents(ctx context.Context, userId int64, events []Event, dryRun bool) ([]ImportResult, error) {
+	results := make([]ImportResult, len(events))
+
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		conflicts := false
+		for i, e := range events {
+			e.UserID = userId
+
+			result, err := importEvent(ctx, tx, e)
+
+			var conflict *VenueConflictError
+			if errors.As(err, &conflict) {
+				result.Conflict = conflict
+				conflicts = true
+			} else if err != nil {
+				return err
+			}
+
+			results[i] = result
+		}
+
+		if conflicts {
+			return errImportConflicts
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if errors.Is(err, errDryRun) || errors.Is(err, errImportConflicts) {
+		for i := range results {
+			if results[i].Action == ImportCreate {
+				results[i].EventID = 0
+			}
+		}
+
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func importEvent(ctx context.Context, tx *sql.Tx, e Event) (ImportResult, error) {
+	existing, err := getEventByExternalUID(ctx, tx, e.UserID, e.ExternalUID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	if existing == nil {
+		created, err := e.insert(ctx, tx)
+		return ImportResult{EventID: created.ID, Action: ImportCreate}, err
+	}
+
+	e.normalizeSchedule()
+	if existing.sameContent(e) {
+		return ImportResult{EventID: existing.ID, Action: ImportUnchanged}, nil
+	}
+
+	// Status, visibility, owner, place, capacity, category, tags and registrations stay, only what the import describes is replaced
+	updated := *existing
+	updated.Name = e.Name
+	updated.Description = e.Description
+	updated.Location = e.Location
+	updated.DateTime = e.DateTime
+	updated.EndDateTime = e.EndDateTime
+	updated.TimeZone = e.TimeZone
+	updated.Recurrence = e.Recurrence
+
+	query, args, _, err := updated.updateColumnsQuery(editableEventColumns)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	err = updated.writeVersionedTx(ctx, tx, AuditUpdate, query, args, nil)
+
+	return ImportResult{EventID: existing.ID, Action: ImportUpdate}, err
+}
+
+// getEventByExternalUID finds the user's event of the tenant imported with this UID, nil when there is none
+func getEventByExternalUID(ctx context.Context, q querier, userId int64, uid string) (*Event, error) {
+	if uid == "" {
+		return nil, nil
+	}
+
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+
+	query := `SELECT ` + eventSelectColumns + ` FROM events WHERE user_id = ? AND external_uid = ? AND deleted_at IS NULL AND ` + tenant
+
+	e, err := scanEvent(q.QueryRowContext(ctx, query, append([]any{userId, uid}, tenantArgs...)...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// sameContent compares the fields an import describes, both events normalized
+func (e Event) sameContent(other Event) bool {
+	return e.Name == other.Name &&
+		e.Description == other.Description &&
+		e.Location == other.Location &&
+		e.DateTime.Equal(other.DateTime) &&
+		e.End().Equal(other.End()) &&
+		e.TimeZoneName() == other.TimeZoneName() &&
+		e.Recurrence.Equal(other.Recurrence)
+}
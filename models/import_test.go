@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/event/db"
+	"github.com/stretchr/testify/assert"
+)
+
+// importedAs gives a test event the UID of an imported calendar entry, ImportEvents takes it unsaved
+func importedAs(uid string) func(*Event) {
+	return func(e *Event) {
+		e.DateTime = time.Date(2031, 3, 1, 9, 0, 0, 0, time.UTC)
+		e.ExternalUID = uid
+	}
+}
+
+func TestImportEvents_CreatesThenUpdatesByUID(t *testing.T) {
+	ctx := context.Background()
+	const userId = int64(9201)
+
+	results, err := ImportEvents(ctx, userId, []Event{newTestEvent(importedAs("a@import")), newTestEvent(importedAs(""))}, false)
+	assert.NoError(t, err)
+	if !assert.Len(t, results, 2) {
+		return
+	}
+	assert.Equal(t, ImportCreate, results[0].Action)
+	assert.Equal(t, ImportCreate, results[1].Action)
+
+	created, err := GetEventByID(ctx, results[0].EventID)
+	assert.NoError(t, err)
+	assert.Equal(t, userId, created.UserID)
+	assert.Equal(t, "a@import", created.ExternalUID)
+	assert.Equal(t, EventStatusDraft, created.Status)
+
+	// publishing is kept when the event is imported again
+	err = created.ChangeStatus(ctx, EventStatusPublished, "")
+	assert.NoError(t, err)
+
+	results, err = ImportEvents(ctx, userId, []Event{newTestEvent(importedAs("a@import"))}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImportResult{{EventID: created.ID, Action: ImportUnchanged}}, results)
+
+	changed := newTestEvent(importedAs("a@import"))
+	changed.Location = "Bandung"
+	results, err = ImportEvents(ctx, userId, []Event{changed}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImportResult{{EventID: created.ID, Action: ImportUpdate}}, results)
+
+	updated, err := GetEventByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bandung", updated.Location)
+	assert.Equal(t, EventStatusPublished, updated.Status)
+	assert.Equal(t, created.Version+1, updated.Version)
+
+	// UIDs are per organizer
+	results, err = ImportEvents(ctx, userId+1, []Event{newTestEvent(importedAs("a@import"))}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ImportCreate, results[0].Action)
+	assert.NotEqual(t, created.ID, results[0].EventID)
+}
+
+func TestImportEvents_DryRunWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	const userId = int64(9203)
+
+	results, err := ImportEvents(ctx, userId, []Event{newTestEvent(importedAs("dry@import"))}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImportResult{{Action: ImportCreate}}, results)
+
+	existing, err := getEventByExternalUID(ctx, db.DB, userId, "dry@import")
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+}
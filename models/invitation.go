@@ -0,0 +1,186 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"example.com/event/db"
+)
+
+// EventInvitation lets a user see and register to a private event
+type EventInvitation struct {
+	ID      int64
+	EventID int64
+	UserID  int64
+	Email   string
+
+	// InvitedBy is who invited the user, or issued the invitation link the user accepted
+	InvitedBy int64
+	CreatedAt time.Time
+}
+
+const eventInvitationSelectColumns = `i.id, i.event_id, i.user_id, u.email, i.invited_by, i.created_at`
+
+func scanEventInvitation(row scanner) (EventInvitation, error) {
+	var i EventInvitation
+	err := row.Scan(&i.ID, &i.EventID, &i.UserID, &i.Email, &i.InvitedBy, &i.CreatedAt)
+	return i, err
+}
+
+// IsInvited reports whether the user is invited to the event
+func IsInvited(ctx context.Context, eventId, userId int64) (bool, error) {
+	var invited bool
+	err := db.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM event_invitations WHERE event_id = ? AND user_id = ?)`, eventId, userId).Scan(&invited)
+	return invited, err
+}
+
+// GetEventInvitations lists the users invited to the event, in the order they were invited
+func GetEventInvitations(ctx context.Context, eventId int64) ([]EventInvitation, error) {
+	query := `
+	SELECT ` + eventInvitationSelectColumns + `
+	FROM event_invitations i JOIN users u ON u.id = i.user_id
+	WHERE i.event_id = ? ORDER BY i.created_at, i.id
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	invitations := []EventInvitation{}
+	for rows.Next() {
+		i, err := scanEventInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		invitations = append(invitations, i)
+	}
+
+	return invitations, rows.Err()
+}
+
+// GetEventInvitation returns the user's invitation to the event or ErrInvitationNotFound
+func GetEventInvitation(ctx context.Context, eventId, userId int64) (*EventInvitation, error) {
+	return getEventInvitation(ctx, db.DB, eventId, userId)
+}
+
+func getEventInvitation(ctx context.Context, q querier, eventId, userId int64) (*EventInvitation, error) {
+	query := `
+	SELECT ` + eventInvitationSelectColumns + `
+	FROM event_invitations i JOIN users u ON u.id = i.user_id
+	WHERE i.event_id = ? AND i.user_id = ?
+	`
+
+	i, err := scanEventInvitation(q.QueryRowContext(ctx, query, eventId, userId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// Invite invites the user with email to the event.
+// ErrUserNotFound when nobody has the email, ErrAlreadyInvited when the user is the owner or invited already.
+func (event Event) Invite(ctx context.Context, email string, invitedBy int64) (*EventInvitation, error) {
+	invitation := EventInvitation{EventID: event.ID, Email: email, InvitedBy: invitedBy, CreatedAt: time.Now().UTC()}
+
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, email).Scan(&invitation.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if invitation.UserID == event.UserID {
+			return ErrAlreadyInvited
+		}
+
+		return invitation.insert(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// AcceptInvitation invites the user to the event with a link issued by invitedBy.
+// Accepting again returns the invitation the user already has.
+func (event Event) AcceptInvitation(ctx context.Context, userId, invitedBy int64) (*EventInvitation, error) {
+	invitation := EventInvitation{EventID: event.ID, UserID: userId, InvitedBy: invitedBy, CreatedAt: time.Now().UTC()}
+
+	var accepted *EventInvitation
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		err := invitation.insert(ctx, tx)
+		if err != nil && !errors.Is(err, ErrAlreadyInvited) {
+			return err
+		}
+
+		accepted, err = getEventInvitation(ctx, tx, event.ID, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accepted, nil
+}
+
+// insert writes the invitation and its audit entry, setting i.ID
+func (i *EventInvitation) insert(ctx context.Context, tx *sql.Tx) error {
+	query := `
+	INSERT INTO event_invitations (event_id, user_id, invited_by, created_at) VALUES (?, ?, ?, ?)
+	`
+
+	result, err := tx.ExecContext(ctx, query, i.EventID, i.UserID, i.InvitedBy, i.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrAlreadyInvited
+	}
+	if err != nil {
+		return err
+	}
+
+	i.ID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return writeAudit(ctx, tx, AuditCreate, AuditEntityInvitation, i.ID, nil, i.auditFields())
+}
+
+// Delete revokes the invitation, registrations the user already made stay
+func (i EventInvitation) Delete(ctx context.Context) error {
+	return withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM event_invitations WHERE id = ?`, i.ID)
+		if err != nil {
+			return err
+		}
+
+		err = requireAffected(result, ErrInvitationNotFound)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, AuditDelete, AuditEntityInvitation, i.ID, i.auditFields(), nil)
+	})
+}
+
+// auditFields are the invitation fields recorded in the audit log
+func (i EventInvitation) auditFields() map[string]any {
+	return map[string]any{
+		"eventId":   i.EventID,
+		"userId":    i.UserID,
+		"invitedBy": i.InvitedBy,
+	}
+}
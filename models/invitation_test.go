@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventVisibility_Listing(t *testing.T) {
+	ctx := context.Background()
+
+	owner := User{Email: "visibility-owner@example.com", Password: "secret123"}
+	assert.NoError(t, owner.Save(ctx))
+
+	guest := User{Email: "visibility-guest@example.com", Password: "secret123"}
+	assert.NoError(t, guest.Save(ctx))
+
+	save := func(visibility EventVisibility) Event {
+		event := newTestEvent()
+		event.UserID = owner.ID
+		event.Status = EventStatusPublished
+		event.Visibility = visibility
+		assert.NoError(t, event.Save(ctx))
+		return event
+	}
+
+	public := save("")
+	unlisted := save(VisibilityUnlisted)
+	private := save(VisibilityPrivate)
+
+	listed := func(viewerId int64) []int64 {
+		events, err := GetAllEvents(ctx, EventFilter{ViewerID: viewerId})
+		assert.NoError(t, err)
+
+		ids := []int64{}
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, VisibilityPublic, public.Visibility)
+	assert.Contains(t, listed(guest.ID), public.ID)
+	assert.NotContains(t, listed(guest.ID), unlisted.ID)
+	assert.NotContains(t, listed(guest.ID), private.ID)
+
+	// The owner sees every event, whatever its visibility
+	assert.Contains(t, listed(owner.ID), unlisted.ID)
+	assert.Contains(t, listed(owner.ID), private.ID)
+
+	invitation, err := private.Invite(ctx, guest.Email, owner.ID)
+	assert.NoError(t, err)
+
+	_, err = private.Invite(ctx, guest.Email, owner.ID)
+	assert.ErrorIs(t, err, ErrAlreadyInvited)
+
+	invited, err := IsInvited(ctx, private.ID, guest.ID)
+	assert.NoError(t, err)
+	assert.True(t, invited)
+	assert.Contains(t, listed(guest.ID), private.ID)
+	assert.NotContains(t, listed(0), private.ID)
+
+	// Accepting a link while invited keeps the invitation
+	accepted, err := private.AcceptInvitation(ctx, guest.ID, owner.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, invitation.ID, accepted.ID)
+
+	assert.NoError(t, invitation.Delete(ctx))
+	assert.NotContains(t, listed(guest.ID), private.ID)
+
+	_, err = GetEventInvitation(ctx, private.ID, guest.ID)
+	assert.ErrorIs(t, err, ErrInvitationNotFound)
+}
@@ -0,0 +1,285 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"example.com/event/db"
+)
+
+// EventRole is what a user may do on an event
+type EventRole string
+
+const (
+	// EventRoleOwner is the event's UserID, it is not stored among the members
+	EventRoleOwner EventRole = "owner"
+
+	// EventRoleCoOrganizer members edit the event and manage its check-in staff
+	EventRoleCoOrganizer EventRole = "co_organizer"
+
+	// EventRoleCheckInStaff members see the event and its attendees
+	EventRoleCheckInStaff EventRole = "check_in_staff"
+)
+
+// MemberRoles are the roles members can be invited with
+var MemberRoles = []EventRole{EventRoleCoOrganizer, EventRoleCheckInStaff}
+
+// EventPermission is an action on an event granted by a role
+type EventPermission string
+
+const (
+	// PermissionView covers seeing the event while it is a draft, and its members
+	PermissionView EventPermission = "view"
+
+	// PermissionEdit covers the event details, its occurrences and its status
+	PermissionEdit EventPermission = "edit"
+
+	// PermissionCheckIn covers the attendee list
+	PermissionCheckIn EventPermission = "check_in"
+
+	// PermissionDelete covers deleting the event and handing it over to another member
+	PermissionDelete EventPermission = "delete"
+)
+
+var rolePermissions = map[EventRole][]EventPermission{
+	EventRoleOwner:        {PermissionView, PermissionEdit, PermissionCheckIn, PermissionDelete},
+	EventRoleCoOrganizer:  {PermissionView, PermissionEdit, PermissionCheckIn},
+	EventRoleCheckInStaff: {PermissionView, PermissionCheckIn},
+}
+
+// Valid reports whether members can be given the role
+func (r EventRole) Valid() bool {
+	return slices.Contains(MemberRoles, r)
+}
+
+// Can reports whether the role grants permission, the empty role (not a member) grants nothing
+func (r EventRole) Can(permission EventPermission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// CanManage reports whether the role may invite and remove members with role:
+// the owner manages everyone, co-organizers their check-in staff
+func (r EventRole) CanManage(role EventRole) bool {
+	switch r {
+	case EventRoleOwner:
+		return role.Valid()
+	case EventRoleCoOrganizer:
+		return role == EventRoleCheckInStaff
+	default:
+		return false
+	}
+}
+
+// EventMember is a user helping to run an event
+type EventMember struct {
+	ID      int64
+	EventID int64
+	UserID  int64
+	Email   string
+	Role    EventRole
+
+	// InvitedBy is nil for the previous owner after a transfer
+	InvitedBy *int64
+
+	CreatedAt time.Time
+}
+
+// Columns selected for an EventMember, in the order scanEventMember expects them
+const eventMemberSelectColumns = `m.id, m.event_id, m.user_id, u.email, m.role, m.invited_by, m.created_at`
+
+func scanEventMember(row scanner) (EventMember, error) {
+	var m EventMember
+
+	err := row.Scan(&m.ID, &m.EventID, &m.UserID, &m.Email, &m.Role, &m.InvitedBy, &m.CreatedAt)
+
+	return m, err
+}
+
+// GetEventRole returns the user's role on the event, empty when the user is neither its owner nor a member.
+// Owners and admins of the organization hosting the event act as its owner.
+func GetEventRole(ctx context.Context, eventId, userId int64) (EventRole, error) {
+	query := `
+	SELECT CASE WHEN user_id = ? THEN 'owner'
+	WHEN organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ? AND role IN ('owner', 'admin')) THEN 'owner'
+	ELSE COALESCE((SELECT role FROM event_members WHERE event_id = events.id AND user_id = ?), '') END
+	FROM events WHERE id = ?
+	`
+
+	var role EventRole
+	err := db.DB.QueryRowContext(ctx, query, userId, userId, userId, eventId).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrEventNotFound
+	}
+
+	return role, err
+}
+
+// GetEventMembers lists the members of the event in the order they joined, the owner is not among them
+func GetEventMembers(ctx context.Context, eventId int64) ([]EventMember, error) {
+	query := `
+	SELECT ` + eventMemberSelectColumns + `
+	FROM event_members m JOIN users u ON u.id = m.user_id
+	WHERE m.event_id = ? ORDER BY m.created_at, m.id
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	members := []EventMember{}
+	for rows.Next() {
+		m, err := scanEventMember(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// GetEventMember returns the user's membership of the event or ErrMemberNotFound
+func GetEventMember(ctx context.Context, eventId, userId int64) (*EventMember, error) {
+	return getEventMember(ctx, db.DB, eventId, userId)
+}
+
+func getEventMember(ctx context.Context, q querier, eventId, userId int64) (*EventMember, error) {
+	query := `
+	SELECT ` + eventMemberSelectColumns + `
+	FROM event_members m JOIN users u ON u.id = m.user_id
+	WHERE m.event_id = ? AND m.user_id = ?
+	`
+
+	m, err := scanEventMember(q.QueryRowContext(ctx, query, eventId, userId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// AddMember gives the user with email the role on the event.
+// ErrUserNotFound when nobody has the email, ErrAlreadyMember when the user is the owner or a member already.
+func (event Event) AddMember(ctx context.Context, email string, role EventRole, invitedBy int64) (*EventMember, error) {
+	member := EventMember{EventID: event.ID, Email: email, Role: role, InvitedBy: &invitedBy, CreatedAt: time.Now().UTC()}
+
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, email).Scan(&member.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if member.UserID == event.UserID {
+			return ErrAlreadyMember
+		}
+
+		return member.insert(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// insert writes the membership and its audit entry, setting m.ID
+func (m *EventMember) insert(ctx context.Context, tx *sql.Tx) error {
+	query := `
+	INSERT INTO event_members (event_id, user_id, role, invited_by, created_at) VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := tx.ExecContext(ctx, query, m.EventID, m.UserID, m.Role, m.InvitedBy, m.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrAlreadyMember
+	}
+	if err != nil {
+		return err
+	}
+
+	m.ID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return writeAudit(ctx, tx, AuditCreate, AuditEntityMember, m.ID, nil, m.auditFields())
+}
+
+// Delete removes the user from the members of the event
+func (m EventMember) Delete(ctx context.Context) error {
+	return withTx(ctx, func(tx *sql.Tx) error {
+		return m.delete(ctx, tx)
+	})
+}
+
+func (m EventMember) delete(ctx context.Context, tx *sql.Tx) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM event_members WHERE id = ?`, m.ID)
+	if err != nil {
+		return err
+	}
+
+	err = requireAffected(result, ErrMemberNotFound)
+	if err != nil {
+		return err
+	}
+
+	return writeAudit(ctx, tx, AuditDelete, AuditEntityMember, m.ID, m.auditFields(), nil)
+}
+
+// TransferOwnership makes the member with userId the owner of the event, event.Version must be the version the caller read.
+// The new owner leaves the members and the previous owner stays on as a co-organizer.
+func (event *Event) TransferOwnership(ctx context.Context, userId int64) error {
+	query := `
+	UPDATE events SET user_id = ?, version = version + 1, updated_at = ?
+	WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	updatedAt := time.Now().UTC()
+
+	previous := EventMember{EventID: event.ID, UserID: event.UserID, Role: EventRoleCoOrganizer, CreatedAt: updatedAt}
+
+	err := event.writeVersioned(ctx, AuditUpdate, query, []any{userId, updatedAt}, func(tx *sql.Tx) error {
+		member, err := getEventMember(ctx, tx, event.ID, userId)
+		if err != nil {
+			return err
+		}
+
+		err = member.delete(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		return previous.insert(ctx, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	event.UserID = userId
+	event.UpdatedAt = updatedAt
+
+	return nil
+}
+
+// auditFields are the membership fields recorded in the audit log
+func (m EventMember) auditFields() map[string]any {
+	return map[string]any{
+		"eventId":   m.EventID,
+		"userId":    m.UserID,
+		"role":      m.Role,
+		"invitedBy": m.InvitedBy,
+	}
+}
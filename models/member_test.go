@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventRole_Permissions(t *testing.T) {
+	assert.True(t, EventRoleOwner.Can(PermissionDelete))
+	assert.True(t, EventRoleCoOrganizer.Can(PermissionEdit))
+	assert.False(t, EventRoleCoOrganizer.Can(PermissionDelete))
+	assert.True(t, EventRoleCheckInStaff.Can(PermissionCheckIn))
+	assert.False(t, EventRoleCheckInStaff.Can(PermissionEdit))
+	assert.False(t, EventRole("").Can(PermissionView))
+
+	assert.True(t, EventRoleOwner.CanManage(EventRoleCoOrganizer))
+	assert.False(t, EventRoleOwner.CanManage(EventRoleOwner))
+	assert.True(t, EventRoleCoOrganizer.CanManage(EventRoleCheckInStaff))
+	assert.False(t, EventRoleCoOrganizer.CanManage(EventRoleCoOrganizer))
+	assert.False(t, EventRoleCheckInStaff.CanManage(EventRoleCheckInStaff))
+}
+
+func TestEventMembers_AddAndTransfer(t *testing.T) {
+	ctx := context.Background()
+
+	owner := User{Email: "member-owner@example.com", Password: "secret123"}
+	assert.NoError(t, owner.Save(ctx))
+
+	helper := User{Email: "member-helper@example.com", Password: "secret123"}
+	assert.NoError(t, helper.Save(ctx))
+
+	event := newTestEvent()
+	event.UserID = owner.ID
+	assert.NoError(t, event.Save(ctx))
+
+	member, err := event.AddMember(ctx, helper.Email, EventRoleCoOrganizer, owner.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.ID, member.UserID)
+
+	_, err = event.AddMember(ctx, helper.Email, EventRoleCheckInStaff, owner.ID)
+	assert.ErrorIs(t, err, ErrAlreadyMember)
+
+	_, err = event.AddMember(ctx, owner.Email, EventRoleCoOrganizer, owner.ID)
+	assert.ErrorIs(t, err, ErrAlreadyMember)
+
+	_, err = event.AddMember(ctx, "nobody@example.com", EventRoleCoOrganizer, owner.ID)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	role, err := GetEventRole(ctx, event.ID, helper.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, EventRoleCoOrganizer, role)
+
+	role, err = GetEventRole(ctx, event.ID, owner.ID+1000)
+	assert.NoError(t, err)
+	assert.Empty(t, role)
+
+	// The draft is listed for its members
+	drafts, err := GetAllEvents(ctx, EventFilter{Statuses: []EventStatus{EventStatusDraft}, ViewerID: helper.ID})
+	assert.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(drafts, func(e Event) bool { return e.ID == event.ID }))
+
+	// Only members can take over
+	err = event.TransferOwnership(ctx, owner.ID+1000)
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+
+	err = event.TransferOwnership(ctx, helper.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.ID, event.UserID)
+
+	saved, err := GetEventByID(ctx, event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.ID, saved.UserID)
+	assert.Equal(t, event.Version, saved.Version)
+
+	members, err := GetEventMembers(ctx, event.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, owner.ID, members[0].UserID)
+		assert.Equal(t, EventRoleCoOrganizer, members[0].Role)
+		assert.Equal(t, owner.Email, members[0].Email)
+	}
+
+	assert.NoError(t, members[0].Delete(ctx))
+
+	_, err = GetEventMember(ctx, event.ID, owner.ID)
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+}
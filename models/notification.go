@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"example.com/event/db"
+)
+
+// Kinds of notification queued for users
+const (
+	NotificationEventCancelled      = "event_cancelled"
+	NotificationOccurrenceCancelled = "occurrence_cancelled"
+)
+
+// Notification is a message queued for a user, sent_at stays NULL until it is delivered
+type Notification struct {
+	ID        int64
+	UserID    int64
+	EventID   int64
+	Kind      string
+	Message   string
+	CreatedAt time.Time
+	SentAt    *time.Time
+}
+
+// notifyRegistrants queues one notification per user registered to the event, however many occurrences they
+// registered to, and returns how many were queued. Users who declined are not told.
+func notifyRegistrants(ctx context.Context, q querier, eventId int64, kind, message string) (int64, error) {
+	query := `
+	INSERT INTO notifications (user_id, event_id, kind, message, created_at)
+	SELECT DISTINCT user_id, event_id, ?, ?, ? FROM registrations WHERE event_id = ? AND status != 'declined'
+	`
+
+	result, err := q.ExecContext(ctx, query, kind, message, time.Now().UTC(), eventId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// notifyOccurrenceRegistrants queues a notification for users registered to one occurrence or to the whole series,
+// except those who declined
+func notifyOccurrenceRegistrants(ctx context.Context, q querier, eventId int64, start time.Time, kind, message string) (int64, error) {
+	query := `
+	INSERT INTO notifications (user_id, event_id, kind, message, created_at)
+	SELECT DISTINCT user_id, event_id, ?, ?, ? FROM registrations
+	WHERE event_id = ? AND status != 'declined' AND (occurrence_start IS NULL OR occurrence_start = ?)
+	`
+
+	result, err := q.ExecContext(ctx, query, kind, message, time.Now().UTC(), eventId, start.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetNotificationsByUser returns the notifications queued for a user, newest first
+func GetNotificationsByUser(ctx context.Context, userId int64) ([]Notification, error) {
+	query := `
+	SELECT id, user_id, event_id, kind, message, created_at, sent_at
+	FROM notifications WHERE user_id = ? ORDER BY id DESC
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		var n Notification
+		err := rows.Scan(&n.ID, &n.UserID, &n.EventID, &n.Kind, &n.Message, &n.CreatedAt, &n.SentAt)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
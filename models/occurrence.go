@@ -0,0 +1,246 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"example.com/event/db"
+)
+
+// OccurrenceOverride changes a single occurrence of a recurring event, nil fields keep the series value
+type OccurrenceOverride struct {
+	ID      int64
+	EventID int64
+
+	// OccurrenceStart is the start the recurrence rule gives the occurrence, in UTC
+	OccurrenceStart time.Time
+
+	Name        *string
+	Description *string
+	Location    *string
+	DateTime    *time.Time
+	Cancelled   bool
+}
+
+func (o OccurrenceOverride) applyTo(e *Event) {
+	if o.Name != nil {
+		e.Name = *o.Name
+	}
+	if o.Description != nil {
+		e.Description = *o.Description
+	}
+	if o.Location != nil {
+		e.Location = *o.Location
+	}
+	if o.DateTime != nil {
+		e.DateTime = *o.DateTime
+	}
+	if o.Cancelled {
+		e.Status = EventStatusCancelled
+	}
+}
+
+// auditFields are the override fields recorded in the audit log
+func (o OccurrenceOverride) auditFields() map[string]any {
+	return map[string]any{
+		"eventId":         o.EventID,
+		"occurrenceStart": o.OccurrenceStart,
+		"name":            o.Name,
+		"description":     o.Description,
+		"location":        o.Location,
+		"dateTime":        o.DateTime,
+		"cancelled":       o.Cancelled,
+	}
+}
+
+const occurrenceSelectColumns = `id, event_id, occurrence_start, name, description, location, datetime, cancelled`
+
+func scanOccurrenceOverride(row scanner) (OccurrenceOverride, error) {
+	var o OccurrenceOverride
+
+	err := row.Scan(&o.ID, &o.EventID, &o.OccurrenceStart, &o.Name, &o.Description, &o.Location, &o.DateTime, &o.Cancelled)
+
+	return o, err
+}
+
+// GetOccurrenceOverrides returns the overrides of the given events, grouped by event id
+func GetOccurrenceOverrides(ctx context.Context, eventIds []int64) (map[int64][]OccurrenceOverride, error) {
+	return getOccurrenceOverrides(ctx, db.DB, eventIds)
+}
+
+// getOccurrenceOverrides is GetOccurrenceOverrides read with q
+func getOccurrenceOverrides(ctx context.Context, q querier, eventIds []int64) (map[int64][]OccurrenceOverride, error) {
+	overrides := map[int64][]OccurrenceOverride{}
+	if len(eventIds) == 0 {
+		return overrides, nil
+	}
+
+	placeholders := make([]string, 0, len(eventIds))
+	args := make([]any, 0, len(eventIds))
+	for _, id := range eventIds {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	query := `
+	SELECT ` + occurrenceSelectColumns + ` FROM event_occurrences
+	WHERE event_id IN (` + strings.Join(placeholders, ", ") + `)
+	`
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	for rows.Next() {
+		o, err := scanOccurrenceOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides[o.EventID] = append(overrides[o.EventID], o)
+	}
+
+	return overrides, rows.Err()
+}
+
+// GetOccurrences expands a single event between from and to, see Event.Occurrences
+func (event Event) GetOccurrences(ctx context.Context, from, to time.Time) ([]Event, error) {
+	overrides, err := GetOccurrenceOverrides(ctx, []int64{event.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return event.Occurrences(from, to, overrides[event.ID])
+}
+
+// requireOccurrence fails with ErrOccurrenceNotFound unless the recurring event has an occurrence at start
+func (event Event) requireOccurrence(start time.Time) error {
+	if event.Recurrence == nil {
+		return fmt.Errorf("%w: event does not recur", ErrOccurrenceNotFound)
+	}
+
+	exists, err := event.HasOccurrence(start)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrOccurrenceNotFound
+	}
+
+	return nil
+}
+
+// SaveOccurrenceOverride creates or replaces the override of one occurrence.
+// Cancelling an occurrence notifies users registered to it or to the whole series.
+func (event Event) SaveOccurrenceOverride(ctx context.Context, o *OccurrenceOverride) error {
+	o.EventID = event.ID
+	o.OccurrenceStart = o.OccurrenceStart.UTC()
+	if o.DateTime != nil {
+		moved := o.DateTime.UTC()
+		o.DateTime = &moved
+	}
+
+	err := event.requireOccurrence(o.OccurrenceStart)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO event_occurrences (event_id, occurrence_start, name, description, location, datetime, cancelled)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (event_id, occurrence_start) DO UPDATE SET
+		name = excluded.name, description = excluded.description, location = excluded.location,
+		datetime = excluded.datetime, cancelled = excluded.cancelled
+	RETURNING id
+	`
+
+	var notified int64
+	err = withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getOccurrenceOverride(ctx, tx, event.ID, o.OccurrenceStart)
+		if err != nil && !errors.Is(err, ErrOccurrenceNotFound) {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, query, event.ID, o.OccurrenceStart, o.Name, o.Description, o.Location, o.DateTime, o.Cancelled).Scan(&o.ID)
+		if err != nil {
+			return err
+		}
+
+		action := AuditCreate
+		var beforeFields map[string]any
+		if before != nil {
+			action = AuditUpdate
+			beforeFields = before.auditFields()
+		}
+
+		err = writeAudit(ctx, tx, action, AuditEntityOccurrence, o.ID, beforeFields, o.auditFields())
+		if err != nil {
+			return err
+		}
+
+		// Only tell registrants once
+		if !o.Cancelled || (before != nil && before.Cancelled) {
+			return nil
+		}
+
+		message := fmt.Sprintf("%q on %s has been cancelled", event.Name, o.OccurrenceStart.Format(time.RFC3339))
+		notified, err = notifyOccurrenceRegistrants(ctx, tx, event.ID, o.OccurrenceStart, NotificationOccurrenceCancelled, message)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if notified > 0 {
+		slog.InfoContext(ctx, "registrants notified", "event_id", event.ID, "kind", NotificationOccurrenceCancelled, "count", notified)
+	}
+
+	return nil
+}
+
+// DeleteOccurrenceOverride puts an occurrence back to what the recurrence rule says
+func (event Event) DeleteOccurrenceOverride(ctx context.Context, start time.Time) error {
+	start = start.UTC()
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getOccurrenceOverride(ctx, tx, event.ID, start)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM event_occurrences WHERE id = ?`, before.ID)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, AuditDelete, AuditEntityOccurrence, before.ID, before.auditFields(), nil)
+	})
+}
+
+// getOccurrenceOverride returns ErrOccurrenceNotFound when the occurrence has no override
+func getOccurrenceOverride(ctx context.Context, q querier, eventId int64, start time.Time) (*OccurrenceOverride, error) {
+	query := `
+	SELECT ` + occurrenceSelectColumns + ` FROM event_occurrences WHERE event_id = ? AND occurrence_start = ?
+	`
+
+	o, err := scanOccurrenceOverride(q.QueryRowContext(ctx, query, eventId, start.UTC()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOccurrenceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
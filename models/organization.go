@@ -0,0 +1,408 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"example.com/event/db"
+)
+
+// OrganizationRole is what a member may do in an organization
+type OrganizationRole string
+
+const (
+	// OrganizationRoleOwner members manage everything, including the other owners
+	OrganizationRoleOwner OrganizationRole = "owner"
+
+	// OrganizationRoleAdmin members manage the settings, admins and members, and every event of the organization
+	OrganizationRoleAdmin OrganizationRole = "admin"
+
+	// OrganizationRoleMember members create events in the organization
+	OrganizationRoleMember OrganizationRole = "member"
+)
+
+// OrganizationRoles lists every role, most privileged first
+var OrganizationRoles = []OrganizationRole{OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleMember}
+
+func (r OrganizationRole) Valid() bool {
+	return slices.Contains(OrganizationRoles, r)
+}
+
+// CanAdminister reports whether the role manages the organization's settings and events
+func (r OrganizationRole) CanAdminister() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// CanManage reports whether the role may add and remove members with role:
+// owners manage everyone, admins everyone but owners
+func (r OrganizationRole) CanManage(role OrganizationRole) bool {
+	switch r {
+	case OrganizationRoleOwner:
+		return role.Valid()
+	case OrganizationRoleAdmin:
+		return role == OrganizationRoleAdmin || role == OrganizationRoleMember
+	default:
+		return false
+	}
+}
+
+// Branding is how an organization's pages look
+type Branding struct {
+	LogoURL string
+
+	// PrimaryColor is a hex color such as #00ADD8
+	PrimaryColor string
+}
+
+// Organization is a community hosting its events on the deployment, a tenant of its own
+type Organization struct {
+	ID int64
+
+	// Slug identifies the organization in URLs and the X-Organization header
+	Slug string
+	Name string
+
+	Branding Branding
+
+	// DefaultTimeZone (IANA name, optional) is given to new events of the organization that do not name one
+	DefaultTimeZone string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OrganizationMember is a user belonging to an organization
+type OrganizationMember struct {
+	ID             int64
+	OrganizationID int64
+	UserID         int64
+	Email          string
+	Role           OrganizationRole
+	CreatedAt      time.Time
+}
+
+type tenantKey struct{}
+
+// WithTenant scopes the events read and written with ctx to the organization
+func WithTenant(ctx context.Context, organizationId int64) context.Context {
+	return context.WithValue(ctx, tenantKey{}, organizationId)
+}
+
+// TenantFrom returns the organization ctx is scoped to, 0 for the events outside any organization
+func TenantFrom(ctx context.Context) int64 {
+	organizationId, _ := ctx.Value(tenantKey{}).(int64)
+	return organizationId
+}
+
+// tenantCondition restricts column (an organization_id) to the tenant of ctx, so requests for one
+// organization never see another's events, and requests outside any organization none of them
+func tenantCondition(ctx context.Context, column string) (string, []any) {
+	organizationId := TenantFrom(ctx)
+	if organizationId == 0 {
+		return column + " IS NULL", nil
+	}
+
+	return column + " = ?", []any{organizationId}
+}
+
+// tenantID is the organization_id written for new events made with ctx
+func tenantID(ctx context.Context) *int64 {
+	organizationId := TenantFrom(ctx)
+	if organizationId == 0 {
+		return nil
+	}
+
+	return &organizationId
+}
+
+// Columns selected for an Organization, in the order scanOrganization expects them
+const organizationSelectColumns = `id, slug, name, logo_url, primary_color, default_time_zone, created_at, updated_at`
+
+func scanOrganization(row scanner) (Organization, error) {
+	var o Organization
+
+	err := row.Scan(&o.ID, &o.Slug, &o.Name, &o.Branding.LogoURL, &o.Branding.PrimaryColor, &o.DefaultTimeZone, &o.CreatedAt, &o.UpdatedAt)
+
+	return o, err
+}
+
+// Save inserts the organization with ownerId as its first owner, ErrOrganizationSlugTaken when another organization has the slug
+func (o *Organization) Save(ctx context.Context, ownerId int64) error {
+	query := `
+	INSERT INTO organizations (slug, name, logo_url, primary_color, default_time_zone, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now().UTC()
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, o.Slug, o.Name, o.Branding.LogoURL, o.Branding.PrimaryColor, o.DefaultTimeZone, now, now)
+		if isUniqueViolation(err) {
+			return ErrOrganizationSlugTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		o.ID, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		o.CreatedAt = now
+		o.UpdatedAt = now
+
+		err = writeAudit(ctx, tx, AuditCreate, AuditEntityOrganization, o.ID, nil, o.auditFields())
+		if err != nil {
+			return err
+		}
+
+		owner := OrganizationMember{OrganizationID: o.ID, UserID: ownerId, Role: OrganizationRoleOwner, CreatedAt: now}
+
+		return owner.insert(ctx, tx)
+	})
+}
+
+// GetAllOrganizations lists every organization by name
+func GetAllOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := db.DB.QueryContext(ctx, `SELECT `+organizationSelectColumns+` FROM organizations ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	organizations := []Organization{}
+	for rows.Next() {
+		o, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		organizations = append(organizations, o)
+	}
+
+	return organizations, rows.Err()
+}
+
+// GetOrganizationBySlug returns the organization or ErrOrganizationNotFound
+func GetOrganizationBySlug(ctx context.Context, slug string) (*Organization, error) {
+	return getOrganization(ctx, db.DB, `slug = ?`, slug)
+}
+
+func getOrganization(ctx context.Context, q querier, condition string, arg any) (*Organization, error) {
+	o, err := scanOrganization(q.QueryRowContext(ctx, `SELECT `+organizationSelectColumns+` FROM organizations WHERE `+condition, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// Update writes the slug, name and settings of the organization
+func (o *Organization) Update(ctx context.Context) error {
+	query := `
+	UPDATE organizations SET slug = ?, name = ?, logo_url = ?, primary_color = ?, default_time_zone = ?, updated_at = ?
+	WHERE id = ?
+	`
+
+	updatedAt := time.Now().UTC()
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getOrganization(ctx, tx, `id = ?`, o.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, o.Slug, o.Name, o.Branding.LogoURL, o.Branding.PrimaryColor, o.DefaultTimeZone, updatedAt, o.ID)
+		if isUniqueViolation(err) {
+			return ErrOrganizationSlugTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		o.CreatedAt = before.CreatedAt
+		o.UpdatedAt = updatedAt
+
+		return writeAudit(ctx, tx, AuditUpdate, AuditEntityOrganization, o.ID, before.auditFields(), o.auditFields())
+	})
+}
+
+// GetOrganizationRole returns the user's role in the organization, empty when the user is not a member
+func GetOrganizationRole(ctx context.Context, organizationId, userId int64) (OrganizationRole, error) {
+	member, err := getOrganizationMember(ctx, db.DB, organizationId, userId)
+	if errors.Is(err, ErrMemberNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return member.Role, nil
+}
+
+// Columns selected for an OrganizationMember, in the order scanOrganizationMember expects them
+const organizationMemberSelectColumns = `m.id, m.organization_id, m.user_id, u.email, m.role, m.created_at`
+
+func scanOrganizationMember(row scanner) (OrganizationMember, error) {
+	var m OrganizationMember
+
+	err := row.Scan(&m.ID, &m.OrganizationID, &m.UserID, &m.Email, &m.Role, &m.CreatedAt)
+
+	return m, err
+}
+
+// GetOrganizationMembers lists the members of the organization in the order they joined
+func GetOrganizationMembers(ctx context.Context, organizationId int64) ([]OrganizationMember, error) {
+	query := `
+	SELECT ` + organizationMemberSelectColumns + `
+	FROM organization_members m JOIN users u ON u.id = m.user_id
+	WHERE m.organization_id = ? ORDER BY m.created_at, m.id
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	members := []OrganizationMember{}
+	for rows.Next() {
+		m, err := scanOrganizationMember(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// GetOrganizationMember returns the user's membership of the organization or ErrMemberNotFound
+func GetOrganizationMember(ctx context.Context, organizationId, userId int64) (*OrganizationMember, error) {
+	return getOrganizationMember(ctx, db.DB, organizationId, userId)
+}
+
+func getOrganizationMember(ctx context.Context, q querier, organizationId, userId int64) (*OrganizationMember, error) {
+	query := `
+	SELECT ` + organizationMemberSelectColumns + `
+	FROM organization_members m JOIN users u ON u.id = m.user_id
+	WHERE m.organization_id = ? AND m.user_id = ?
+	`
+
+	m, err := scanOrganizationMember(q.QueryRowContext(ctx, query, organizationId, userId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// AddMember gives the user with email the role in the organization.
+// ErrUserNotFound when nobody has the email, ErrAlreadyMember when the user is a member already.
+func (o Organization) AddMember(ctx context.Context, email string, role OrganizationRole) (*OrganizationMember, error) {
+	member := OrganizationMember{OrganizationID: o.ID, Email: email, Role: role, CreatedAt: time.Now().UTC()}
+
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, email).Scan(&member.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return member.insert(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// insert writes the membership and its audit entry, setting m.ID
+func (m *OrganizationMember) insert(ctx context.Context, tx *sql.Tx) error {
+	query := `
+	INSERT INTO organization_members (organization_id, user_id, role, created_at) VALUES (?, ?, ?, ?)
+	`
+
+	result, err := tx.ExecContext(ctx, query, m.OrganizationID, m.UserID, m.Role, m.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrAlreadyMember
+	}
+	if err != nil {
+		return err
+	}
+
+	m.ID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return writeAudit(ctx, tx, AuditCreate, AuditEntityOrganizationMember, m.ID, nil, m.auditFields())
+}
+
+// Delete removes the user from the organization, ErrLastOwner when the user is its only owner.
+// Events the user created stay with the organization.
+func (m OrganizationMember) Delete(ctx context.Context) error {
+	return withTx(ctx, func(tx *sql.Tx) error {
+		if m.Role == OrganizationRoleOwner {
+			var owners int
+			err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM organization_members WHERE organization_id = ? AND role = ?`, m.OrganizationID, OrganizationRoleOwner).Scan(&owners)
+			if err != nil {
+				return err
+			}
+
+			if owners <= 1 {
+				return ErrLastOwner
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM organization_members WHERE id = ?`, m.ID)
+		if err != nil {
+			return err
+		}
+
+		err = requireAffected(result, ErrMemberNotFound)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, AuditDelete, AuditEntityOrganizationMember, m.ID, m.auditFields(), nil)
+	})
+}
+
+// auditFields are the organization fields recorded in the audit log
+func (o Organization) auditFields() map[string]any {
+	return map[string]any{
+		"slug":            o.Slug,
+		"name":            o.Name,
+		"logoUrl":         o.Branding.LogoURL,
+		"primaryColor":    o.Branding.PrimaryColor,
+		"defaultTimeZone": o.DefaultTimeZone,
+	}
+}
+
+// auditFields are the membership fields recorded in the audit log
+func (m OrganizationMember) auditFields() map[string]any {
+	return map[string]any{
+		"organizationId": m.OrganizationID,
+		"userId":         m.UserID,
+		"role":           m.Role,
+	}
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationRole_Permissions(t *testing.T) {
+	assert.True(t, OrganizationRoleOwner.CanAdminister())
+	assert.True(t, OrganizationRoleAdmin.CanAdminister())
+	assert.False(t, OrganizationRoleMember.CanAdminister())
+
+	assert.True(t, OrganizationRoleOwner.CanManage(OrganizationRoleOwner))
+	assert.False(t, OrganizationRoleAdmin.CanManage(OrganizationRoleOwner))
+	assert.True(t, OrganizationRoleAdmin.CanManage(OrganizationRoleMember))
+	assert.False(t, OrganizationRoleMember.CanManage(OrganizationRoleMember))
+}
+
+func TestOrganization_MembersAndLastOwner(t *testing.T) {
+	ctx := context.Background()
+
+	owner := User{Email: "org-owner@example.com", Password: "secret123"}
+	assert.NoError(t, owner.Save(ctx))
+
+	admin := User{Email: "org-admin@example.com", Password: "secret123"}
+	assert.NoError(t, admin.Save(ctx))
+
+	organization := Organization{Slug: "members-org", Name: "Members"}
+	assert.NoError(t, organization.Save(ctx, owner.ID))
+
+	duplicate := Organization{Slug: "members-org", Name: "Again"}
+	assert.ErrorIs(t, duplicate.Save(ctx, owner.ID), ErrOrganizationSlugTaken)
+
+	_, err := organization.AddMember(ctx, admin.Email, OrganizationRoleAdmin)
+	assert.NoError(t, err)
+
+	_, err = organization.AddMember(ctx, admin.Email, OrganizationRoleMember)
+	assert.ErrorIs(t, err, ErrAlreadyMember)
+
+	role, err := GetOrganizationRole(ctx, organization.ID, admin.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, OrganizationRoleAdmin, role)
+
+	// Admins of the organization act as owners of its events
+	event := newTestEvent()
+	event.UserID = owner.ID
+	assert.NoError(t, event.Save(WithTenant(ctx, organization.ID)))
+
+	eventRole, err := GetEventRole(ctx, event.ID, admin.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, EventRoleOwner, eventRole)
+
+	member, err := GetOrganizationMember(ctx, organization.ID, owner.ID)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, member.Delete(ctx), ErrLastOwner)
+}
+
+func TestOrganization_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	owner := User{Email: "tenant-owner@example.com", Password: "secret123"}
+	assert.NoError(t, owner.Save(ctx))
+
+	organization := Organization{Slug: "tenant-org", Name: "Tenant", DefaultTimeZone: "Asia/Jakarta"}
+	assert.NoError(t, organization.Save(ctx, owner.ID))
+
+	tenantCtx := WithTenant(ctx, organization.ID)
+
+	scoped := newTestEvent()
+	scoped.UserID = owner.ID
+	scoped.Status = EventStatusPublished
+	assert.NoError(t, scoped.Save(tenantCtx))
+	assert.Equal(t, organization.ID, *scoped.OrganizationID)
+
+	unscoped := newTestEvent()
+	unscoped.UserID = owner.ID
+	unscoped.Status = EventStatusPublished
+	assert.NoError(t, unscoped.Save(ctx))
+	assert.Nil(t, unscoped.OrganizationID)
+
+	_, err := GetEventByID(ctx, scoped.ID)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	_, err = GetEventByID(tenantCtx, unscoped.ID)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	found, err := GetEventByID(tenantCtx, scoped.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, organization.ID, *found.OrganizationID)
+
+	ids := func(events []Event) []int64 {
+		result := []int64{}
+		for _, e := range events {
+			result = append(result, e.ID)
+		}
+		return result
+	}
+
+	events, err := GetAllEvents(tenantCtx, EventFilter{})
+	assert.NoError(t, err)
+	assert.Contains(t, ids(events), scoped.ID)
+	assert.NotContains(t, ids(events), unscoped.ID)
+
+	events, err = GetAllEvents(ctx, EventFilter{})
+	assert.NoError(t, err)
+	assert.True(t, slices.Contains(ids(events), unscoped.ID))
+	assert.False(t, slices.Contains(ids(events), scoped.ID))
+}
+
+func TestOrganization_TenantIsolatedWrites(t *testing.T) {
+	ctx := context.Background()
+
+	owner := User{Email: "tenant-writes@example.com", Password: "secret123"}
+	assert.NoError(t, owner.Save(ctx))
+
+	organization := Organization{Slug: "tenant-writes", Name: "Tenant Writes", DefaultTimeZone: "Asia/Jakarta"}
+	assert.NoError(t, organization.Save(ctx, owner.ID))
+
+	tenantCtx := WithTenant(ctx, organization.ID)
+
+	event := newTestEvent()
+	event.UserID = owner.ID
+	event.Status = EventStatusPublished
+	assert.NoError(t, event.Save(tenantCtx))
+
+	// Outside the organization every write misses the event
+	outside := event
+	outside.Name = "Renamed outside"
+	assert.ErrorIs(t, outside.UpdateColumns(ctx, []string{"name"}), ErrEventNotFound)
+
+	outside = event
+	assert.ErrorIs(t, outside.Delete(ctx), ErrEventNotFound)
+
+	outside = event
+	assert.ErrorIs(t, outside.TransferOwnership(ctx, owner.ID+1), ErrEventNotFound)
+
+	stored, err := GetEventByID(tenantCtx, event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, event.Name, stored.Name)
+	assert.Equal(t, event.Version, stored.Version)
+
+	// Inside it they go through, restore included
+	assert.NoError(t, event.Delete(tenantCtx))
+
+	deleted, err := GetDeletedEventByID(tenantCtx, event.ID)
+	assert.NoError(t, err)
+
+	outside = *deleted
+	assert.ErrorIs(t, outside.Restore(ctx), ErrEventNotFound)
+	assert.NoError(t, deleted.Restore(tenantCtx))
+}
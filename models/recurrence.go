@@ -0,0 +1,202 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Recurrence repeats an event following an RFC 5545 rule, the event's DateTime is the first occurrence
+type Recurrence struct {
+	// Rule is an RRULE value such as FREQ=WEEKLY;BYDAY=TU;COUNT=10
+	Rule string
+
+	// ExDates are occurrences (by their start) left out of the series
+	ExDates []time.Time
+}
+
+// MaxOccurrenceWindow bounds how far occurrences are expanded in one request
+const MaxOccurrenceWindow = 366 * 24 * time.Hour
+
+// RRULE parts we support, anything else (BYMONTHDAY, BYSETPOS...) is rejected
+var recurrenceRuleParts = []string{"FREQ", "INTERVAL", "BYDAY", "COUNT", "UNTIL"}
+
+// Frequencies of at least a day, so a window never expands to more than a few hundred occurrences
+var recurrenceFrequencies = []string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+// ValidateRecurrenceRule explains what is wrong with an RRULE value, nil when it is usable
+func ValidateRecurrenceRule(rule string) error {
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+
+		if !slices.Contains(recurrenceRuleParts, key) {
+			return fmt.Errorf("%s is not supported, use %s", key, strings.Join(recurrenceRuleParts, ", "))
+		}
+
+		if key == "FREQ" && !slices.Contains(recurrenceFrequencies, value) {
+			return fmt.Errorf("FREQ must be one of %s", strings.Join(recurrenceFrequencies, ", "))
+		}
+	}
+
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return err
+	}
+
+	if option.Count > 0 && !option.Until.IsZero() {
+		return errors.New("COUNT and UNTIL cannot be combined")
+	}
+
+	return nil
+}
+
+// recurrenceSet builds the rule starting at the event's DateTime.
+// It is expanded in the event's time zone so occurrences keep their local time (and weekday) across DST changes.
+func (e Event) recurrenceSet() (*rrule.Set, error) {
+	option, err := rrule.StrToROption(e.Recurrence.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	option.Dtstart = e.DateTime.In(e.Zone())
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &rrule.Set{}
+	set.RRule(rule)
+	for _, exdate := range e.Recurrence.ExDates {
+		set.ExDate(exdate)
+	}
+
+	return set, nil
+}
+
+// HasOccurrence reports whether the series has an occurrence starting at start (EXDATEs excluded).
+// A non-recurring event has a single occurrence at its DateTime.
+func (e Event) HasOccurrence(start time.Time) (bool, error) {
+	if e.Recurrence == nil {
+		return e.DateTime.Equal(start), nil
+	}
+
+	set, err := e.recurrenceSet()
+	if err != nil {
+		return false, err
+	}
+
+	return len(set.Between(start, start, true)) > 0, nil
+}
+
+// Occurrences expands the event between from (inclusive) and to (exclusive), applying overrides.
+// Each occurrence is a copy of the event with OccurrenceStart set to the start the rule gives it;
+// overrides may move it (DateTime), change its details or cancel it (Status).
+// A non-recurring event is returned as is when it starts within the window.
+func (e Event) Occurrences(from, to time.Time, overrides []OccurrenceOverride) ([]Event, error) {
+	if e.Recurrence == nil {
+		if e.DateTime.Before(from) || !e.DateTime.Before(to) {
+			return nil, nil
+		}
+
+		return []Event{e}, nil
+	}
+
+	set, err := e.recurrenceSet()
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := []Event{}
+	inWindow := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	starts := set.Between(from, to, true)
+	duration := e.Duration()
+
+	// Occurrences moved into the window from outside of it
+	for _, o := range overrides {
+		if o.DateTime != nil && inWindow(*o.DateTime) && !inWindow(o.OccurrenceStart) && len(set.Between(o.OccurrenceStart, o.OccurrenceStart, true)) > 0 {
+			starts = append(starts, o.OccurrenceStart)
+		}
+	}
+
+	for _, start := range starts {
+		start = start.UTC()
+
+		occurrence := e
+		occurrence.OccurrenceStart = &start
+		occurrence.DateTime = start
+
+		for _, o := range overrides {
+			if o.OccurrenceStart.Equal(start) {
+				o.applyTo(&occurrence)
+			}
+		}
+
+		// Moved out of the window
+		if !inWindow(occurrence.DateTime) {
+			continue
+		}
+
+		// Every occurrence lasts as long as the first one, moved ones included
+		occurrence.EndDateTime = occurrence.DateTime.Add(duration)
+
+		occurrences = append(occurrences, occurrence)
+	}
+
+	slices.SortFunc(occurrences, func(a, b Event) int {
+		return a.DateTime.Compare(b.DateTime)
+	})
+
+	return occurrences, nil
+}
+
+// recurrenceColumns returns the values stored in recurrence_rule and recurrence_exdates
+func (e Event) recurrenceColumns() (string, string) {
+	if e.Recurrence == nil {
+		return "", ""
+	}
+
+	exdates := make([]time.Time, 0, len(e.Recurrence.ExDates))
+	for _, exdate := range e.Recurrence.ExDates {
+		exdates = append(exdates, exdate.UTC())
+	}
+
+	// Marshalling a slice of times cannot fail
+	b, _ := json.Marshal(exdates)
+
+	return e.Recurrence.Rule, string(b)
+}
+
+// scanRecurrence is the inverse of recurrenceColumns
+func scanRecurrence(rule, exdates string) (*Recurrence, error) {
+	if rule == "" {
+		return nil, nil
+	}
+
+	recurrence := &Recurrence{Rule: rule}
+	if exdates != "" {
+		err := json.Unmarshal([]byte(exdates), &recurrence.ExDates)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return recurrence, nil
+}
+
+// Equal reports whether both recurrences give the same occurrences, nil means no recurrence
+func (r *Recurrence) Equal(other *Recurrence) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+
+	return r.Rule == other.Rule && slices.EqualFunc(r.ExDates, other.ExDates, time.Time.Equal)
+}
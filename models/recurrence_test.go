@@ -0,0 +1,265 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recurring makes a test event repeat by rule, from a Tuesday
+func recurring(rule string) func(*Event) {
+	return func(e *Event) {
+		e.DateTime = time.Date(2031, 1, 7, 9, 0, 0, 0, time.UTC)
+		e.Recurrence = &Recurrence{Rule: rule}
+	}
+}
+
+func TestValidateRecurrenceRule(t *testing.T) {
+	for rule, valid := range map[string]bool{
+		"FREQ=WEEKLY;BYDAY=TU,TH;COUNT=10":             true,
+		"FREQ=DAILY;INTERVAL=2;UNTIL=20311231T000000Z": true,
+		"FREQ=MONTHLY":           true,
+		"FREQ=HOURLY":            false,
+		"FREQ=WEEKLY;BYSETPOS=1": false,
+		"FREQ=WEEKLY;COUNT=2;UNTIL=20311231T000000Z": false,
+		"FREQ=WEEKLY;COUNT=abc":                      false,
+		"":                                           false,
+	} {
+		err := ValidateRecurrenceRule(rule)
+		assert.Equal(t, valid, err == nil, rule)
+	}
+}
+
+func TestEventOccurrences_ExpandsWithExDatesAndOverrides(t *testing.T) {
+	first := time.Date(2031, 1, 7, 9, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:       1,
+		DateTime: first,
+		Status:   EventStatusPublished,
+		Recurrence: &Recurrence{
+			Rule:    "FREQ=WEEKLY;COUNT=5",
+			ExDates: []time.Time{first.AddDate(0, 0, 7)},
+		},
+	}
+
+	name := "Special edition"
+	moved := first.AddDate(0, 0, 15)
+	overrides := []OccurrenceOverride{
+		{EventID: 1, OccurrenceStart: first.AddDate(0, 0, 14), Name: &name, DateTime: &moved},
+		{EventID: 1, OccurrenceStart: first.AddDate(0, 0, 21), Cancelled: true},
+	}
+
+	occurrences, err := event.Occurrences(first, first.AddDate(0, 2, 0), overrides)
+	assert.NoError(t, err)
+	if !assert.Len(t, occurrences, 4) {
+		return
+	}
+
+	// the second week is excluded
+	assert.Equal(t, first, occurrences[0].DateTime)
+	assert.Equal(t, first, *occurrences[0].OccurrenceStart)
+
+	assert.Equal(t, moved, occurrences[1].DateTime)
+	assert.Equal(t, first.AddDate(0, 0, 14), *occurrences[1].OccurrenceStart)
+	assert.Equal(t, name, occurrences[1].Name)
+
+	assert.Equal(t, EventStatusCancelled, occurrences[2].Status)
+	assert.Equal(t, EventStatusPublished, occurrences[3].Status)
+
+	// an occurrence moved into the window is listed even though the rule puts it outside
+	occurrences, err = event.Occurrences(moved, moved.Add(time.Hour), overrides)
+	assert.NoError(t, err)
+	if assert.Len(t, occurrences, 1) {
+		assert.Equal(t, name, occurrences[0].Name)
+	}
+}
+
+func TestEventSave_RoundTripsRecurrence(t *testing.T) {
+	event := newPublishedTestEvent(t, recurring("FREQ=WEEKLY;BYDAY=TU;COUNT=3"))
+	event.Recurrence.ExDates = []time.Time{time.Date(2031, 1, 14, 9, 0, 0, 0, time.UTC)}
+
+	err := event.UpdateColumns(context.Background(), []string{"recurrence_exdates"})
+	assert.NoError(t, err)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.True(t, event.Recurrence.Equal(saved.Recurrence))
+}
+
+func TestGetAllEvents_ExpandsOccurrencesInWindow(t *testing.T) {
+	event := newPublishedTestEvent(t, recurring("FREQ=WEEKLY;COUNT=4"))
+	first := event.DateTime
+
+	err := event.SaveOccurrenceOverride(context.Background(), &OccurrenceOverride{OccurrenceStart: first.AddDate(0, 0, 7), Cancelled: true})
+	assert.NoError(t, err)
+
+	starts := func(filter EventFilter) []time.Time {
+		events, err := GetAllEvents(context.Background(), filter)
+		assert.NoError(t, err)
+
+		var starts []time.Time
+		for _, e := range events {
+			if e.ID == event.ID {
+				starts = append(starts, e.DateTime)
+			}
+		}
+		return starts
+	}
+
+	window := EventFilter{From: first.AddDate(0, 0, 1), To: first.AddDate(0, 1, 0)}
+	assert.Equal(t, []time.Time{first.AddDate(0, 0, 14), first.AddDate(0, 0, 21)}, starts(window))
+
+	// cancelled occurrences are listed when asked for
+	window.Statuses = []EventStatus{EventStatusPublished, EventStatusCancelled}
+	assert.Len(t, starts(window), 3)
+
+	// without a window the series is listed once
+	assert.Equal(t, []time.Time{first}, starts(EventFilter{}))
+}
+
+func TestRegisterEvent_Occurrence(t *testing.T) {
+	event := newPublishedTestEvent(t, recurring("FREQ=WEEKLY;COUNT=4"))
+	first := event.DateTime
+	second := first.AddDate(0, 0, 7)
+
+	const attendee, seriesAttendee = int64(9101), int64(9102)
+
+	err := event.RegisterEvent(context.Background(), attendee, &second)
+	assert.NoError(t, err)
+
+	err = event.RegisterEvent(context.Background(), attendee, &second)
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	// a start the rule does not give
+	notAnOccurrence := first.Add(time.Hour)
+	err = event.RegisterEvent(context.Background(), attendee, &notAnOccurrence)
+	assert.ErrorIs(t, err, ErrOccurrenceNotFound)
+
+	// a series registration covers every occurrence
+	err = event.RegisterEvent(context.Background(), seriesAttendee, nil)
+	assert.NoError(t, err)
+	err = event.RegisterEvent(context.Background(), seriesAttendee, &second)
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	// and an occurrence seat overlaps the series
+	err = event.RegisterEvent(context.Background(), attendee, nil)
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	// cancelling the occurrence notifies both, and closes it
+	err = event.SaveOccurrenceOverride(context.Background(), &OccurrenceOverride{OccurrenceStart: second, Cancelled: true})
+	assert.NoError(t, err)
+
+	for _, userId := range []int64{attendee, seriesAttendee} {
+		notifications, err := GetNotificationsByUser(context.Background(), userId)
+		assert.NoError(t, err)
+		if assert.Len(t, notifications, 1) {
+			assert.Equal(t, NotificationOccurrenceCancelled, notifications[0].Kind)
+		}
+	}
+
+	err = event.RegisterEvent(context.Background(), attendee+10, &second)
+	assert.ErrorIs(t, err, ErrEventNotOpen)
+
+	err = event.UnregisterEvent(context.Background(), attendee, &first)
+	assert.ErrorIs(t, err, ErrNotRegistered)
+
+	err = event.UnregisterEvent(context.Background(), attendee, &second)
+	assert.NoError(t, err)
+}
+
+func TestDeleteOccurrenceOverride(t *testing.T) {
+	event := newPublishedTestEvent(t, recurring("FREQ=DAILY;COUNT=3"))
+	start := event.DateTime.AddDate(0, 0, 1)
+
+	location := "Bandung"
+	err := event.SaveOccurrenceOverride(context.Background(), &OccurrenceOverride{OccurrenceStart: start, Location: &location})
+	assert.NoError(t, err)
+
+	occurrences, err := event.GetOccurrences(context.Background(), start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, occurrences, 1) {
+		assert.Equal(t, location, occurrences[0].Location)
+	}
+
+	err = event.DeleteOccurrenceOverride(context.Background(), start)
+	assert.NoError(t, err)
+
+	err = event.DeleteOccurrenceOverride(context.Background(), start)
+	assert.ErrorIs(t, err, ErrOccurrenceNotFound)
+
+	occurrences, err = event.GetOccurrences(context.Background(), start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, occurrences, 1) {
+		assert.Equal(t, event.Location, occurrences[0].Location)
+	}
+}
+
+func TestEventOccurrences_KeepLocalTimeAcrossDST(t *testing.T) {
+	newYork, err := LoadTimeZone("America/New_York")
+	assert.NoError(t, err)
+
+	// Saturdays at 10:00 in New York, DST ends on Sunday 2031-11-02
+	first := time.Date(2031, 10, 25, 10, 0, 0, 0, newYork)
+	event := Event{
+		DateTime:    first.UTC(),
+		EndDateTime: first.Add(2 * time.Hour).UTC(),
+		TimeZone:    "America/New_York",
+		Recurrence:  &Recurrence{Rule: "FREQ=WEEKLY;COUNT=3"},
+	}
+
+	occurrences, err := event.Occurrences(first, first.AddDate(0, 0, 21), nil)
+	assert.NoError(t, err)
+	if !assert.Len(t, occurrences, 3) {
+		return
+	}
+
+	assert.Equal(t, time.Date(2031, 10, 25, 14, 0, 0, 0, time.UTC), occurrences[0].DateTime)
+	assert.Equal(t, time.Date(2031, 11, 1, 14, 0, 0, 0, time.UTC), occurrences[1].DateTime)
+	// still 10:00 local, which is an hour later in UTC
+	assert.Equal(t, time.Date(2031, 11, 8, 15, 0, 0, 0, time.UTC), occurrences[2].DateTime)
+	assert.Equal(t, time.Date(2031, 11, 8, 17, 0, 0, 0, time.UTC), occurrences[2].EndDateTime)
+}
+
+func TestEventSave_StoresUTCAndZone(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+
+	event := newTestEvent()
+	event.DateTime = time.Date(2032, 3, 1, 9, 0, 0, 0, jakarta)
+	event.TimeZone = "Asia/Jakarta"
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2032, 3, 1, 2, 0, 0, 0, time.UTC), saved.DateTime)
+	assert.Equal(t, time.Date(2032, 3, 1, 3, 0, 0, 0, time.UTC), saved.EndDateTime)
+	assert.Equal(t, "Asia/Jakarta", saved.TimeZone)
+
+	// the window is compared in SQL, events outside of it are not returned
+	events, err := GetAllEvents(context.Background(), EventFilter{
+		Statuses: []EventStatus{EventStatusDraft},
+		ViewerID: event.UserID,
+		From:     time.Date(2032, 3, 1, 0, 0, 0, 0, jakarta),
+		To:       time.Date(2032, 3, 2, 0, 0, 0, 0, jakarta),
+	})
+	assert.NoError(t, err)
+
+	var ids []int64
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	assert.Contains(t, ids, event.ID)
+
+	events, err = GetAllEvents(context.Background(), EventFilter{
+		Statuses: []EventStatus{EventStatusDraft},
+		ViewerID: event.UserID,
+		From:     time.Date(2032, 3, 1, 10, 0, 0, 0, jakarta),
+		To:       time.Date(2032, 3, 2, 0, 0, 0, 0, jakarta),
+	})
+	assert.NoError(t, err)
+	for _, e := range events {
+		assert.NotEqual(t, event.ID, e.ID)
+	}
+}
@@ -1,14 +1,30 @@
 package models
 
-import "example.com/event/db"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
 
-func (event Event) RegisterEvent(userId int64) error {
+	"example.com/event/db"
+)
+
+// RegisterEvent adds the user to a published event, going.
+// For a recurring event occurrence selects a single occurrence by its start, nil registers to the whole series.
+// A user who answered maybe or declined before is moved to going.
+func (event Event) RegisterEvent(ctx context.Context, userId int64, occurrence *time.Time) error {
+	_, err := event.setRSVP(ctx, userId, occurrence, RSVPGoing, true)
+	return err
+}
+
+// insert writes the registration to the event and its audit entry, setting r.ID
+func (r *Registration) insert(ctx context.Context, tx *sql.Tx, eventId int64) error {
 	query := `
-		INSERT INTO registrations(event_id, user_id) VALUES (?, ?)
+		INSERT INTO registrations(event_id, user_id, occurrence_start, status) VALUES (?, ?, ?, ?)
 	`
 
-	// DB.Prepare is used to create a prepared statement for execution
-	stmt, err := db.DB.Prepare(query)
+	// tx.PrepareContext is used to create a prepared statement for execution
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -16,32 +32,211 @@ func (event Event) RegisterEvent(userId int64) error {
 	// Ensure the statement is closed after execution
 	defer stmt.Close()
 
-	// stmt.Exec is used to execute a prepared statement with the given arguments
-	_, err = stmt.Exec(event.ID, userId)
+	// stmt.ExecContext is used to execute a prepared statement with the given arguments
+	result, err := stmt.ExecContext(ctx, eventId, r.UserID, r.OccurrenceStart, r.Status)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	r.ID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return writeAudit(ctx, tx, AuditCreate, AuditEntityRegistration, r.ID, nil, r.auditFields(eventId))
+}
+
+// updateStatus writes the new status of the registration and its audit entry
+func (r *Registration) updateStatus(ctx context.Context, tx *sql.Tx, eventId int64, status RSVPStatus) error {
+	before := r.auditFields(eventId)
+
+	_, err := tx.ExecContext(ctx, `UPDATE registrations SET status = ? WHERE id = ?`, status, r.ID)
+	if err != nil {
+		return err
+	}
+
+	r.Status = status
+
+	return writeAudit(ctx, tx, AuditUpdate, AuditEntityRegistration, r.ID, before, r.auditFields(eventId))
 }
 
-func (event Event) UnregisterEvent(userId int64) error {
+// getRegistration returns the user's registration to exactly the occurrence (nil for the series) or ErrNotRegistered
+func getRegistration(ctx context.Context, q querier, eventId, userId int64, occurrence *time.Time) (*Registration, error) {
 	query := `
-	DELETE FROM registrations WHERE event_id = ? AND user_id = ?
+	SELECT r.id, r.user_id, COALESCE(u.email, ''), r.occurrence_start, r.status
+	FROM registrations r LEFT JOIN users u ON u.id = r.user_id
+	WHERE r.event_id = ? AND r.user_id = ? AND r.occurrence_start IS ?
 	`
 
-	stmt, err := db.DB.Prepare(query)
+	var r Registration
+	err := q.QueryRowContext(ctx, query, eventId, userId, occurrence).Scan(&r.ID, &r.UserID, &r.Email, &r.OccurrenceStart, &r.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotRegistered
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Ensure the statement is closed after execution
-	defer stmt.Close()
+	return &r, nil
+}
+
+// UnregisterEvent removes the user's registration to one occurrence, or every registration to the event when occurrence is nil
+func (event Event) UnregisterEvent(ctx context.Context, userId int64, occurrence *time.Time) error {
+	query := `
+	DELETE FROM registrations WHERE event_id = ? AND user_id = ? RETURNING id, occurrence_start, status
+	`
+	args := []any{event.ID, userId}
+
+	if occurrence != nil {
+		query = `
+		DELETE FROM registrations WHERE event_id = ? AND user_id = ? AND occurrence_start = ? RETURNING id, occurrence_start, status
+		`
+		args = append(args, occurrence.UTC())
+	}
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		// defer rows.Close() ensures that the rows are closed after processing
+		defer rows.Close()
+
+		var registrations []Registration
+		for rows.Next() {
+			r := Registration{UserID: userId}
+			err := rows.Scan(&r.ID, &r.OccurrenceStart, &r.Status)
+			if err != nil {
+				return err
+			}
+
+			registrations = append(registrations, r)
+		}
+
+		err = rows.Err()
+		if err != nil {
+			return err
+		}
+
+		// Nothing deleted means the user was never registered
+		if len(registrations) == 0 {
+			return ErrNotRegistered
+		}
+
+		for _, r := range registrations {
+			err = writeAudit(ctx, tx, AuditDelete, AuditEntityRegistration, r.ID, r.auditFields(event.ID), nil)
+			if err != nil {
+				return err
+			}
+
+			err = writeRSVPChange(ctx, tx, event.ID, userId, r.OccurrenceStart, r.Status, "")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// requireSeat returns ErrEventFull when the event, or the occurrence, has as many seats taken as its capacity.
+// Series registrations take a seat at every occurrence, so registering to the series needs one at the busiest.
+// Users who answered maybe or declined take none.
+func (event Event) requireSeat(ctx context.Context, q querier, occurrence *time.Time) error {
+	if event.Capacity == nil {
+		return nil
+	}
 
-	_, err = stmt.Exec(event.ID, userId)
+	query := `
+	SELECT (SELECT COUNT(*) FROM registrations WHERE event_id = ? AND occurrence_start IS NULL AND status IN ` + seatStatuses + `)
+	+ (SELECT COALESCE(MAX(taken), 0) FROM (
+		SELECT COUNT(*) AS taken FROM registrations
+		WHERE event_id = ? AND occurrence_start IS NOT NULL AND status IN ` + seatStatuses + ` AND (? IS NULL OR occurrence_start = ?)
+		GROUP BY occurrence_start
+	))
+	`
+
+	var taken int
+	err := q.QueryRowContext(ctx, query, event.ID, event.ID, occurrence, occurrence).Scan(&taken)
 	if err != nil {
 		return err
 	}
 
+	if taken >= *event.Capacity {
+		return ErrEventFull
+	}
+
 	return nil
 }
+
+// auditFields are the registration fields recorded in the audit log
+func (r Registration) auditFields(eventId int64) map[string]any {
+	return map[string]any{
+		"eventId":         eventId,
+		"userId":          r.UserID,
+		"occurrenceStart": r.OccurrenceStart,
+		"status":          r.Status,
+	}
+}
+
+// IsRegistered reports whether the user has any registration to the event
+func (event Event) IsRegistered(ctx context.Context, userId int64) (bool, error) {
+	query := `
+	SELECT EXISTS(SELECT 1 FROM registrations WHERE event_id = ? AND user_id = ?)
+	`
+
+	var registered bool
+	err := db.DB.QueryRowContext(ctx, query, event.ID, userId).Scan(&registered)
+
+	return registered, err
+}
+
+// Registration is a user attending an event, or one occurrence of it
+type Registration struct {
+	ID     int64
+	UserID int64
+	Email  string
+
+	// OccurrenceStart is nil for a registration to the whole series
+	OccurrenceStart *time.Time
+
+	Status RSVPStatus
+}
+
+// GetRegistrations lists who registered to the event, by email.
+// For a recurring event occurrence (optional) keeps the registrations covering that occurrence.
+func (event Event) GetRegistrations(ctx context.Context, occurrence *time.Time) ([]Registration, error) {
+	query := `
+	SELECT r.id, r.user_id, COALESCE(u.email, ''), r.occurrence_start, r.status
+	FROM registrations r LEFT JOIN users u ON u.id = r.user_id
+	WHERE r.event_id = ? AND (? IS NULL OR r.occurrence_start IS NULL OR r.occurrence_start = ?)
+	ORDER BY u.email, r.id
+	`
+
+	var start any
+	if occurrence != nil {
+		start = occurrence.UTC()
+	}
+
+	rows, err := db.DB.QueryContext(ctx, query, event.ID, start, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	registrations := []Registration{}
+	for rows.Next() {
+		var r Registration
+		err := rows.Scan(&r.ID, &r.UserID, &r.Email, &r.OccurrenceStart, &r.Status)
+		if err != nil {
+			return nil, err
+		}
+
+		registrations = append(registrations, r)
+	}
+
+	return registrations, rows.Err()
+}
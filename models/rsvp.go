@@ -0,0 +1,309 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"example.com/event/db"
+)
+
+// RSVPStatus is the answer of a registered user, or whether they showed up
+type RSVPStatus string
+
+const (
+	// RSVPGoing takes a seat, it is what registering to an event answers
+	RSVPGoing RSVPStatus = "going"
+
+	// RSVPMaybe and RSVPDeclined keep the user on the list without taking a seat
+	RSVPMaybe    RSVPStatus = "maybe"
+	RSVPDeclined RSVPStatus = "declined"
+
+	// RSVPAttended and RSVPNoShow are recorded at check-in, the seat stays taken
+	RSVPAttended RSVPStatus = "attended"
+	RSVPNoShow   RSVPStatus = "no_show"
+)
+
+// RSVPStatuses lists every status, the answers first
+var RSVPStatuses = []RSVPStatus{RSVPGoing, RSVPMaybe, RSVPDeclined, RSVPAttended, RSVPNoShow}
+
+// AttendeeRSVPStatuses are the answers users give themselves, the others are recorded by the people running the event
+var AttendeeRSVPStatuses = []RSVPStatus{RSVPGoing, RSVPMaybe, RSVPDeclined}
+
+// seatStatuses are the statuses counted against the capacity, as an SQL list
+const seatStatuses = `('going', 'attended', 'no_show')`
+
+func (s RSVPStatus) Valid() bool {
+	return slices.Contains(RSVPStatuses, s)
+}
+
+// checkedIn reports whether the status was recorded at check-in
+func (s RSVPStatus) checkedIn() bool {
+	return s == RSVPAttended || s == RSVPNoShow
+}
+
+// takesSeat reports whether a registration with the status counts against the capacity
+func (s RSVPStatus) takesSeat() bool {
+	return s == RSVPGoing || s == RSVPAttended || s == RSVPNoShow
+}
+
+// SetRSVP changes the user's answer to the event, or to one occurrence of a recurring event.
+// Users who have no registration yet are registered, the event needs to be published.
+// Check-in statuses are recorded with CheckIn.
+func (event Event) SetRSVP(ctx context.Context, userId int64, occurrence *time.Time, status RSVPStatus) (*Registration, error) {
+	return event.setRSVP(ctx, userId, occurrence, status, false)
+}
+
+// setRSVP is SetRSVP, strict reports ErrAlreadyRegistered when the user already has a seat
+func (event Event) setRSVP(ctx context.Context, userId int64, occurrence *time.Time, status RSVPStatus, strict bool) (*Registration, error) {
+	if !slices.Contains(AttendeeRSVPStatuses, status) {
+		return nil, NewValidationError("status", "must be one of: going maybe declined")
+	}
+
+	if event.Status != EventStatusPublished {
+		return nil, ErrEventNotOpen
+	}
+
+	if occurrence != nil {
+		utc := occurrence.UTC()
+		occurrence = &utc
+
+		err := event.requireOpenOccurrence(ctx, utc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var registration *Registration
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		current, err := getRegistration(ctx, tx, event.ID, userId, occurrence)
+		if errors.Is(err, ErrNotRegistered) {
+			current, err = nil, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if strict && current != nil && current.Status.takesSeat() {
+			return ErrAlreadyRegistered
+		}
+
+		registration, err = event.changeRSVP(ctx, tx, userId, occurrence, current, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return registration, nil
+}
+
+// CheckIn records whether a registered user attended the event, or one occurrence of a recurring event.
+// RSVPGoing undoes a check-in. It never registers anyone, and goes on once the event is completed.
+func (event Event) CheckIn(ctx context.Context, userId int64, occurrence *time.Time, status RSVPStatus) (*Registration, error) {
+	if status != RSVPAttended && status != RSVPNoShow && status != RSVPGoing {
+		return nil, NewValidationError("status", "must be one of: attended no_show going")
+	}
+
+	if event.Status != EventStatusPublished && event.Status != EventStatusCompleted {
+		return nil, ErrEventNotOpen
+	}
+
+	if occurrence != nil {
+		utc := occurrence.UTC()
+		occurrence = &utc
+	}
+
+	var registration *Registration
+	err := withTx(ctx, func(tx *sql.Tx) error {
+		current, err := getRegistration(ctx, tx, event.ID, userId, occurrence)
+		if err != nil {
+			return err
+		}
+
+		// Going is only an undo here, answering for the user is theirs to do
+		if status == RSVPGoing && current.Status != RSVPGoing && !current.Status.checkedIn() {
+			return ErrNotCheckedIn
+		}
+
+		registration, err = event.changeRSVP(ctx, tx, userId, occurrence, current, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return registration, nil
+}
+
+// changeRSVP moves the user's registration (nil when they have none) to status in tx and records the change
+func (event Event) changeRSVP(ctx context.Context, tx *sql.Tx, userId int64, occurrence *time.Time, current *Registration, status RSVPStatus) (*Registration, error) {
+	from := RSVPStatus("")
+	if current != nil {
+		from = current.Status
+	}
+
+	if from == status {
+		return current, nil
+	}
+
+	// Counted in the transaction, so two last-seat answers cannot both get in
+	if status.takesSeat() && !from.takesSeat() {
+		err := event.requireNewSeat(ctx, tx, userId, occurrence)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if current == nil {
+		err = (&Registration{UserID: userId, OccurrenceStart: occurrence, Status: status}).insert(ctx, tx, event.ID)
+	} else {
+		err = current.updateStatus(ctx, tx, event.ID, status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeRSVPChange(ctx, tx, event.ID, userId, occurrence, from, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRegistration(ctx, tx, event.ID, userId, occurrence)
+}
+
+// requireOpenOccurrence returns an error unless start is an occurrence of the event that is not cancelled
+func (event Event) requireOpenOccurrence(ctx context.Context, start time.Time) error {
+	err := event.requireOccurrence(start)
+	if err != nil {
+		return err
+	}
+
+	override, err := getOccurrenceOverride(ctx, db.DB, event.ID, start)
+	if err != nil && !errors.Is(err, ErrOccurrenceNotFound) {
+		return err
+	}
+
+	if override != nil && override.Cancelled {
+		return ErrEventNotOpen
+	}
+
+	return nil
+}
+
+// requireNewSeat checks the user has no seat covering the occurrence yet (a series seat covers all of them,
+// a seat at any occurrence overlaps the series) and that one is left
+func (event Event) requireNewSeat(ctx context.Context, tx *sql.Tx, userId int64, occurrence *time.Time) error {
+	query := `
+	SELECT EXISTS(SELECT 1 FROM registrations WHERE event_id = ? AND user_id = ? AND status IN ` + seatStatuses + `
+	AND (occurrence_start IS NULL OR ? IS NULL OR occurrence_start = ?))
+	`
+
+	var seated bool
+	err := tx.QueryRowContext(ctx, query, event.ID, userId, occurrence, occurrence).Scan(&seated)
+	if err != nil {
+		return err
+	}
+
+	if seated {
+		return ErrAlreadyRegistered
+	}
+
+	return event.requireSeat(ctx, tx, occurrence)
+}
+
+// GetRSVPCounts counts the registrations to the event by status, every status is present
+func (event Event) GetRSVPCounts(ctx context.Context) (map[RSVPStatus]int, error) {
+	rows, err := db.DB.QueryContext(ctx, `SELECT status, COUNT(*) FROM registrations WHERE event_id = ? GROUP BY status`, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	counts := make(map[RSVPStatus]int, len(RSVPStatuses))
+	for _, status := range RSVPStatuses {
+		counts[status] = 0
+	}
+
+	for rows.Next() {
+		var status RSVPStatus
+		var count int
+		err := rows.Scan(&status, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// RSVPChange is a change of a user's RSVP status, FromStatus is empty when the user registered
+// and ToStatus when the registration was removed
+type RSVPChange struct {
+	ID              int64
+	EventID         int64
+	UserID          int64
+	Email           string
+	OccurrenceStart *time.Time
+	FromStatus      RSVPStatus
+	ToStatus        RSVPStatus
+
+	// ChangedBy is who made the change: the user, or whoever checked them in. Nil for background jobs.
+	ChangedBy *int64
+	CreatedAt time.Time
+}
+
+// writeRSVPChange records a status change in tx, made by the actor of ctx
+func writeRSVPChange(ctx context.Context, tx *sql.Tx, eventId, userId int64, occurrence *time.Time, from, to RSVPStatus) error {
+	query := `
+	INSERT INTO rsvp_changes (event_id, user_id, occurrence_start, from_status, to_status, changed_by, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var changedBy *int64
+	if actor := AuditActorFrom(ctx); actor.UserID != 0 {
+		changedBy = &actor.UserID
+	}
+
+	_, err := tx.ExecContext(ctx, query, eventId, userId, occurrence, from, to, changedBy, time.Now().UTC())
+
+	return err
+}
+
+// GetRSVPHistory lists the RSVP changes of the event, oldest first. userId (0 for everyone) keeps a single user's.
+func (event Event) GetRSVPHistory(ctx context.Context, userId int64) ([]RSVPChange, error) {
+	query := `
+	SELECT c.id, c.event_id, c.user_id, u.email, c.occurrence_start, c.from_status, c.to_status, c.changed_by, c.created_at
+	FROM rsvp_changes c JOIN users u ON u.id = c.user_id
+	WHERE c.event_id = ? AND (? = 0 OR c.user_id = ?)
+	ORDER BY c.created_at, c.id
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, event.ID, userId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	changes := []RSVPChange{}
+	for rows.Next() {
+		var c RSVPChange
+		err := rows.Scan(&c.ID, &c.EventID, &c.UserID, &c.Email, &c.OccurrenceStart, &c.FromStatus, &c.ToStatus, &c.ChangedBy, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRSVP_OnlyGoingTakesASeat(t *testing.T) {
+	event := newPublishedTestEvent(t)
+	capacity := 1
+	event.Capacity = &capacity
+
+	err := event.RegisterEvent(context.Background(), 9101, nil)
+	assert.NoError(t, err)
+
+	// the event is full, answers without a seat are still accepted
+	registration, err := event.SetRSVP(context.Background(), 9102, nil, RSVPMaybe)
+	assert.NoError(t, err)
+	assert.Equal(t, RSVPMaybe, registration.Status)
+
+	_, err = event.SetRSVP(context.Background(), 9102, nil, RSVPGoing)
+	assert.ErrorIs(t, err, ErrEventFull)
+
+	// declining frees the seat
+	_, err = event.SetRSVP(context.Background(), 9101, nil, RSVPDeclined)
+	assert.NoError(t, err)
+
+	_, err = event.SetRSVP(context.Background(), 9102, nil, RSVPGoing)
+	assert.NoError(t, err)
+
+	counts, err := event.GetRSVPCounts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[RSVPStatus]int{RSVPGoing: 1, RSVPMaybe: 0, RSVPDeclined: 1, RSVPAttended: 0, RSVPNoShow: 0}, counts)
+}
+
+func TestRegisterEvent_AfterDeclining(t *testing.T) {
+	event := newPublishedTestEvent(t)
+
+	_, err := event.SetRSVP(context.Background(), 9201, nil, RSVPDeclined)
+	assert.NoError(t, err)
+
+	err = event.RegisterEvent(context.Background(), 9201, nil)
+	assert.NoError(t, err)
+
+	err = event.RegisterEvent(context.Background(), 9201, nil)
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	registrations, err := event.GetRegistrations(context.Background(), nil)
+	assert.NoError(t, err)
+	if assert.Len(t, registrations, 1) {
+		assert.Equal(t, RSVPGoing, registrations[0].Status)
+	}
+}
+
+func TestSetRSVP_RejectsCheckInStatus(t *testing.T) {
+	event := newPublishedTestEvent(t)
+
+	_, err := event.SetRSVP(context.Background(), 9303, nil, RSVPAttended)
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestCheckIn_NeverRegisters(t *testing.T) {
+	event := newPublishedTestEvent(t)
+
+	for _, status := range []RSVPStatus{RSVPAttended, RSVPNoShow, RSVPGoing} {
+		_, err := event.CheckIn(context.Background(), 9301, nil, status)
+		assert.ErrorIs(t, err, ErrNotRegistered, status)
+	}
+
+	registrations, err := event.GetRegistrations(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, registrations)
+
+	// going only undoes a check-in, it does not answer for the user
+	_, err = event.SetRSVP(context.Background(), 9301, nil, RSVPMaybe)
+	assert.NoError(t, err)
+
+	_, err = event.CheckIn(context.Background(), 9301, nil, RSVPGoing)
+	assert.ErrorIs(t, err, ErrNotCheckedIn)
+}
+
+func TestCheckIn_UndoOnceCompleted(t *testing.T) {
+	event := newPublishedTestEvent(t)
+
+	err := event.RegisterEvent(context.Background(), 9302, nil)
+	assert.NoError(t, err)
+
+	_, err = event.CheckIn(context.Background(), 9302, nil, RSVPAttended)
+	assert.NoError(t, err)
+
+	// check-in goes on once the event is completed, answers do not
+	event.Status = EventStatusCompleted
+
+	registration, err := event.CheckIn(context.Background(), 9302, nil, RSVPGoing)
+	assert.NoError(t, err)
+	assert.Equal(t, RSVPGoing, registration.Status)
+
+	registration, err = event.CheckIn(context.Background(), 9302, nil, RSVPNoShow)
+	assert.NoError(t, err)
+	assert.Equal(t, RSVPNoShow, registration.Status)
+
+	_, err = event.SetRSVP(context.Background(), 9302, nil, RSVPDeclined)
+	assert.ErrorIs(t, err, ErrEventNotOpen)
+}
+
+func TestGetRSVPHistory(t *testing.T) {
+	attendee := User{Email: "rsvp-history@example.com", Password: "secret123"}
+	err := attendee.Save(context.Background())
+	assert.NoError(t, err)
+
+	event := newPublishedTestEvent(t)
+	ctx := WithAuditActor(context.Background(), AuditActor{UserID: attendee.ID})
+
+	_, err = event.SetRSVP(ctx, attendee.ID, nil, RSVPMaybe)
+	assert.NoError(t, err)
+
+	// the same answer again is not a change
+	_, err = event.SetRSVP(ctx, attendee.ID, nil, RSVPMaybe)
+	assert.NoError(t, err)
+
+	_, err = event.SetRSVP(ctx, attendee.ID, nil, RSVPGoing)
+	assert.NoError(t, err)
+
+	// checked in by the owner
+	_, err = event.CheckIn(WithAuditActor(context.Background(), AuditActor{UserID: event.UserID}), attendee.ID, nil, RSVPAttended)
+	assert.NoError(t, err)
+
+	changes, err := event.GetRSVPHistory(context.Background(), attendee.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 3) {
+		assert.Equal(t, RSVPStatus(""), changes[0].FromStatus)
+		assert.Equal(t, RSVPMaybe, changes[0].ToStatus)
+		assert.Equal(t, attendee.Email, changes[0].Email)
+		assert.Equal(t, attendee.ID, *changes[0].ChangedBy)
+
+		assert.Equal(t, RSVPMaybe, changes[1].FromStatus)
+		assert.Equal(t, RSVPGoing, changes[1].ToStatus)
+
+		assert.Equal(t, RSVPAttended, changes[2].ToStatus)
+		assert.Equal(t, event.UserID, *changes[2].ChangedBy)
+	}
+
+	err = event.UnregisterEvent(context.Background(), attendee.ID, nil)
+	assert.NoError(t, err)
+
+	changes, err = event.GetRSVPHistory(context.Background(), 0)
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 4) {
+		assert.Equal(t, RSVPAttended, changes[3].FromStatus)
+		assert.Equal(t, RSVPStatus(""), changes[3].ToStatus)
+		assert.Nil(t, changes[3].ChangedBy)
+	}
+}
@@ -0,0 +1,303 @@
+package models
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"example.com/event/db"
+)
+
+// MaxSearchTerms caps the words of a search query, the rest are ignored
+const MaxSearchTerms = 10
+
+// Markers around matches in snippets, replaced by <mark> once the snippet is HTML-escaped
+const (
+	snippetOpen  = "\x02"
+	snippetClose = "\x03"
+	snippetCut   = "…"
+)
+
+// snippetWords is about how many words a snippet shows around the matches
+const snippetWords = 16
+
+// SearchFilter narrows SearchEvents
+type SearchFilter struct {
+	// Query is free text, every word must match the start of a word in the name, description or location
+	Query string
+
+	// Statuses to search, DefaultEventStatuses when empty
+	Statuses []EventStatus
+
+	// ViewerID is the user searching (0 when anonymous), drafts are only found for their owner and members,
+	// private events for the users invited to them
+	ViewerID int64
+
+	Limit  int
+	Offset int
+}
+
+// SearchResult is an event found by SearchEvents
+type SearchResult struct {
+	Event
+
+	// Snippet is HTML: the best matching text, escaped, with matches wrapped in <mark>
+	Snippet string
+}
+
+// SearchTerms splits a query into the words searched for, lowercased and without duplicates
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := []string{}
+	for _, word := range words {
+		if len(terms) == MaxSearchTerms {
+			break
+		}
+
+		if !slices.Contains(terms, word) {
+			terms = append(terms, word)
+		}
+	}
+
+	return terms
+}
+
+// SearchEvents returns a page of the events matching the query, best matches first, and how many match in total.
+// Events are ranked with bm25 over the FTS5 index, name matches weighing most. When SQLite lacks FTS5,
+// LIKE finds the words anywhere in the text and events whose name matches come first.
+func SearchEvents(ctx context.Context, filter SearchFilter) ([]SearchResult, int, error) {
+	terms := SearchTerms(filter.Query)
+	if len(terms) == 0 {
+		return []SearchResult{}, 0, nil
+	}
+
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = DefaultEventStatuses
+	}
+
+	placeholders := make([]string, 0, len(statuses))
+	visibleArgs := make([]any, 0, len(statuses)+4)
+	for _, status := range statuses {
+		placeholders = append(placeholders, "?")
+		visibleArgs = append(visibleArgs, status)
+	}
+	visibleArgs = append(visibleArgs, filter.ViewerID, filter.ViewerID, filter.ViewerID, filter.ViewerID)
+
+	tenant, tenantArgs := tenantCondition(ctx, "e.organization_id")
+	visibleArgs = append(visibleArgs, tenantArgs...)
+
+	visible := `e.deleted_at IS NULL
+	AND e.status IN (` + strings.Join(placeholders, ", ") + `) AND ` + visibleEventCondition("e.") + `
+	AND ` + tenant
+
+	if db.FullTextSearch {
+		return searchFullText(ctx, terms, visible, visibleArgs, filter)
+	}
+
+	return searchLike(ctx, terms, visible, visibleArgs, filter)
+}
+
+// searchFullText queries events_fts, each term quoted (so no FTS5 syntax gets through) and matched as a prefix
+func searchFullText(ctx context.Context, terms []string, visible string, visibleArgs []any, filter SearchFilter) ([]SearchResult, int, error) {
+	phrases := make([]string, 0, len(terms))
+	for _, term := range terms {
+		phrases = append(phrases, `"`+term+`"*`)
+	}
+	match := strings.Join(phrases, " ")
+
+	from := `
+	FROM events_fts JOIN events e ON e.id = events_fts.rowid
+	WHERE events_fts MATCH ? AND ` + visible
+
+	var total int
+	err := db.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+from, append([]any{match}, visibleArgs...)...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// bm25 weights follow the column order of events_fts: name, description, location
+	query := `
+	SELECT ` + qualifiedEventColumns("e") + `, snippet(events_fts, -1, ?, ?, ?, ?)
+	` + from + `
+	ORDER BY bm25(events_fts, 10.0, 1.0, 5.0), e.id
+	LIMIT ? OFFSET ?
+	`
+
+	args := []any{snippetOpen, snippetClose, snippetCut, snippetWords, match}
+	args = append(args, visibleArgs...)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var snippet string
+		e, err := scanEvent(scannerWith(rows, &snippet))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		results = append(results, SearchResult{Event: e, Snippet: markSnippet(snippet)})
+	}
+
+	return results, total, rows.Err()
+}
+
+// searchLike is the fallback without FTS5: every term must appear in one of the text columns
+func searchLike(ctx context.Context, terms []string, visible string, visibleArgs []any, filter SearchFilter) ([]SearchResult, int, error) {
+	conditions := make([]string, 0, len(terms))
+	nameConditions := make([]string, 0, len(terms))
+	termArgs := make([]any, 0, 3*len(terms))
+	nameArgs := make([]any, 0, len(terms))
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+
+		conditions = append(conditions, `(e.name LIKE ? ESCAPE '\' OR e.description LIKE ? ESCAPE '\' OR e.location LIKE ? ESCAPE '\')`)
+		termArgs = append(termArgs, pattern, pattern, pattern)
+
+		nameConditions = append(nameConditions, `e.name LIKE ? ESCAPE '\'`)
+		nameArgs = append(nameArgs, pattern)
+	}
+
+	from := `
+	FROM events e
+	WHERE ` + strings.Join(conditions, " AND ") + ` AND ` + visible
+
+	args := append(termArgs, visibleArgs...)
+
+	var total int
+	err := db.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+from, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+	SELECT ` + qualifiedEventColumns("e") + from + `
+	ORDER BY CASE WHEN ` + strings.Join(nameConditions, " AND ") + ` THEN 0 ELSE 1 END, e.datetime, e.id
+	LIMIT ? OFFSET ?
+	`
+
+	args = append(args, nameArgs...)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		results = append(results, SearchResult{Event: e, Snippet: likeSnippet(e, terms)})
+	}
+
+	return results, total, rows.Err()
+}
+
+// qualifiedEventColumns is eventSelectColumns prefixed with a table alias,
+// subqueries refer to the aliased row instead of events
+func qualifiedEventColumns(alias string) string {
+	columns := strings.Split(eventSelectColumns, ", ")
+	for i, column := range columns {
+		if strings.HasPrefix(column, "(") {
+			columns[i] = strings.ReplaceAll(column, "events.", alias+".")
+			continue
+		}
+
+		columns[i] = alias + "." + column
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// scannerWith scans the event columns followed by extra columns into extra
+func scannerWith(row scanner, extra ...any) scanner {
+	return scanFunc(func(dest ...any) error {
+		return row.Scan(append(dest, extra...)...)
+	})
+}
+
+type scanFunc func(dest ...any) error
+
+func (f scanFunc) Scan(dest ...any) error {
+	return f(dest...)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes a term match literally in a LIKE pattern with ESCAPE '\'
+func escapeLike(term string) string {
+	return likeEscaper.Replace(term)
+}
+
+// markSnippet escapes a snippet for HTML, then turns the match markers into <mark> elements
+func markSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, snippetOpen, "<mark>")
+	return strings.ReplaceAll(escaped, snippetClose, "</mark>")
+}
+
+// likeSnippet builds a snippet like FTS5 would: the first column with a match,
+// cut to about snippetWords words around the first match
+func likeSnippet(e Event, terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+	// whole words are marked, as FTS5 marks whole tokens
+	matcher := regexp.MustCompile(`(?i)[\pL\pN]*(?:` + strings.Join(quoted, "|") + `)[\pL\pN]*`)
+
+	for _, text := range []string{e.Name, e.Description, e.Location} {
+		if !matcher.MatchString(text) {
+			continue
+		}
+
+		words := strings.Fields(text)
+		first := 0
+		for i, word := range words {
+			if matcher.MatchString(word) {
+				first = i
+				break
+			}
+		}
+
+		start := max(first-snippetWords/4, 0)
+		end := min(start+snippetWords, len(words))
+
+		snippet := strings.Join(words[start:end], " ")
+		snippet = matcher.ReplaceAllStringFunc(snippet, func(match string) string {
+			return snippetOpen + match + snippetClose
+		})
+
+		if start > 0 {
+			snippet = snippetCut + snippet
+		}
+		if end < len(words) {
+			snippet += snippetCut
+		}
+
+		return markSnippet(snippet)
+	}
+
+	return ""
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"example.com/event/db"
+	"github.com/stretchr/testify/assert"
+)
+
+// The FTS5 (bm25) search is only compiled in with -tags sqlite_fts5, which make test passes.
+// A plain go test ./... runs the SearchEvents tests against the LIKE fallback instead,
+// TestSearchEvents_LikeFallback covers the fallback in both builds.
+
+// described sets the searched text of a test event, words unique to a test keep other tests' events out of its results
+func described(name, description, location string) func(*Event) {
+	return func(e *Event) {
+		e.Name = name
+		e.Description = description
+		e.Location = location
+	}
+}
+
+func searchIDs(results []SearchResult) []int64 {
+	ids := make([]int64, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"go", "workshop", "jakarta"}, SearchTerms(`Go "workshop" (Jakarta) go*`))
+	assert.Empty(t, SearchTerms(` "*" - `))
+	assert.Len(t, SearchTerms("a b c d e f g h i j k l"), MaxSearchTerms)
+}
+
+func TestSearchEvents_RanksAndHighlights(t *testing.T) {
+	ctx := context.Background()
+
+	inName := newPublishedTestEvent(t, described("Quokkafest workshop", "A day of talks.", "Jakarta"))
+	inDescription := newPublishedTestEvent(t, described("Go meetup", "Talks, then the <b>quokkafest</b> afterparty & drinks.", "Bandung"))
+
+	draft := newTestEvent()
+	draft.Name = "Quokkafest planning"
+	err := draft.Save(ctx)
+	assert.NoError(t, err)
+
+	// a prefix is enough, drafts are left out
+	results, total, err := SearchEvents(ctx, SearchFilter{Query: "QUOKKA", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []int64{inName.ID, inDescription.ID}, searchIDs(results))
+
+	// snippets are HTML-escaped with the match marked
+	if assert.Len(t, results, 2) {
+		assert.Contains(t, results[0].Snippet, "<mark>Quokkafest</mark>")
+		assert.Contains(t, results[1].Snippet, "&lt;b&gt;<mark>quokkafest</mark>&lt;/b&gt;")
+		assert.Contains(t, results[1].Snippet, "&amp;")
+	}
+
+	// the owner finds their draft when asking for drafts
+	results, _, err = SearchEvents(ctx, SearchFilter{Query: "quokkafest", Statuses: []EventStatus{EventStatusDraft}, ViewerID: draft.UserID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{draft.ID}, searchIDs(results))
+
+	// every word has to match
+	results, _, err = SearchEvents(ctx, SearchFilter{Query: "quokkafest bandung", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{inDescription.ID}, searchIDs(results))
+}
+
+func TestSearchEvents_FollowsChanges(t *testing.T) {
+	ctx := context.Background()
+
+	event := newPublishedTestEvent(t, described("Wombatcon", "A beginner-friendly conference.", "Jakarta"))
+
+	event.Name = "Numbatcon"
+	err := event.UpdateColumns(ctx, []string{"name"})
+	assert.NoError(t, err)
+
+	results, _, err := SearchEvents(ctx, SearchFilter{Query: "wombatcon", Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, _, err = SearchEvents(ctx, SearchFilter{Query: "numbatcon", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{event.ID}, searchIDs(results))
+
+	err = event.Delete(ctx)
+	assert.NoError(t, err)
+
+	results, total, err := SearchEvents(ctx, SearchFilter{Query: "numbatcon", Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Zero(t, total)
+}
+
+func TestSearchEvents_Pages(t *testing.T) {
+	ctx := context.Background()
+
+	var ids []int64
+	for range 5 {
+		ids = append(ids, newPublishedTestEvent(t, described("Bilbyconf", "Same text for all.", "Jakarta")).ID)
+	}
+
+	results, total, err := SearchEvents(ctx, SearchFilter{Query: "bilbyconf", Limit: 2, Offset: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, total)
+	// equal ranks are ordered by id
+	assert.Equal(t, ids[2:4], searchIDs(results))
+}
+
+func TestSearchEvents_FullTextOperatorsAreLiteral(t *testing.T) {
+	if !db.FullTextSearch {
+		t.Skip("SQLite built without FTS5")
+	}
+
+	// FTS5 syntax in the query must not reach MATCH
+	_, _, err := SearchEvents(context.Background(), SearchFilter{Query: `NEAR(" OR name:* AND -`, Limit: 10})
+	assert.NoError(t, err)
+}
+
+func TestSearchEvents_LikeFallback(t *testing.T) {
+	ctx := context.Background()
+
+	// as if SQLite was built without FTS5
+	original := db.FullTextSearch
+	db.FullTextSearch = false
+	defer func() {
+		db.FullTextSearch = original
+	}()
+
+	inDescription := newPublishedTestEvent(t, described("Go meetup", "Talks, then the <b>echidnafest</b> afterparty.", "Bandung"))
+	inName := newPublishedTestEvent(t, described("Echidnafest workshop", "A day of talks.", "Jakarta"))
+
+	// words match anywhere, events matching by name come first
+	results, total, err := SearchEvents(ctx, SearchFilter{Query: "ECHIDNA", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []int64{inName.ID, inDescription.ID}, searchIDs(results))
+
+	// snippets are built like FTS5 ones, HTML-escaped with whole words marked
+	if assert.Len(t, results, 2) {
+		assert.Contains(t, results[0].Snippet, "<mark>Echidnafest</mark>")
+		assert.Contains(t, results[1].Snippet, "&lt;b&gt;<mark>echidnafest</mark>&lt;/b&gt;")
+	}
+
+	// every word has to match
+	results, _, err = SearchEvents(ctx, SearchFilter{Query: "echidnafest bandung", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{inDescription.ID}, searchIDs(results))
+
+	// pages follow the same order
+	results, _, err = SearchEvents(ctx, SearchFilter{Query: "echidnafest", Offset: 1, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{inDescription.ID}, searchIDs(results))
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// EventStatus is the lifecycle state of an event
+type EventStatus string
+
+const (
+	// EventStatusDraft events are only visible to their owner and members
+	EventStatusDraft EventStatus = "draft"
+
+	// EventStatusPublished events are listed and open for registration
+	EventStatusPublished EventStatus = "published"
+
+	// EventStatusCancelled events keep their registrations but are hidden from listings by default
+	EventStatusCancelled EventStatus = "cancelled"
+
+	// EventStatusCompleted events already took place
+	EventStatusCompleted EventStatus = "completed"
+)
+
+// EventStatuses lists every status, in lifecycle order
+var EventStatuses = []EventStatus{EventStatusDraft, EventStatusPublished, EventStatusCancelled, EventStatusCompleted}
+
+// DefaultEventStatuses are the statuses listed when no status filter is given
+var DefaultEventStatuses = []EventStatus{EventStatusPublished, EventStatusCompleted}
+
+// Allowed transitions, cancelled and completed are final
+var eventStatusTransitions = map[EventStatus][]EventStatus{
+	EventStatusDraft:     {EventStatusPublished},
+	EventStatusPublished: {EventStatusCancelled, EventStatusCompleted},
+}
+
+func (s EventStatus) Valid() bool {
+	return slices.Contains(EventStatuses, s)
+}
+
+func (s EventStatus) CanTransitionTo(next EventStatus) bool {
+	return slices.Contains(eventStatusTransitions[s], next)
+}
+
+// ChangeStatus moves the event to next, event.Version must be the version the caller read.
+// Cancelling queues a notification for every registrant in the same transaction,
+// registrations themselves are kept.
+func (event *Event) ChangeStatus(ctx context.Context, next EventStatus, reason string) error {
+	if !event.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, event.Status, next)
+	}
+
+	// Only cancellation carries a reason
+	if next != EventStatusCancelled {
+		reason = ""
+	}
+
+	query := `
+	UPDATE events
+	SET status = ?, cancellation_reason = ?, version = version + 1, updated_at = ?
+	WHERE id = ? AND version = ?
+	`
+
+	updatedAt := time.Now().UTC()
+
+	var notified int64
+	err := event.writeVersioned(ctx, AuditUpdate, query, []any{next, reason, updatedAt}, func(tx *sql.Tx) error {
+		if next != EventStatusCancelled {
+			return nil
+		}
+
+		message := fmt.Sprintf("%q has been cancelled", event.Name)
+		if reason != "" {
+			message += ": " + reason
+		}
+
+		var err error
+		notified, err = notifyRegistrants(ctx, tx, event.ID, NotificationEventCancelled, message)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if notified > 0 {
+		slog.InfoContext(ctx, "registrants notified", "event_id", event.ID, "kind", NotificationEventCancelled, "count", notified)
+	}
+
+	event.Status = next
+	event.CancellationReason = reason
+	event.UpdatedAt = updatedAt
+
+	return nil
+}
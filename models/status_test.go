@@ -0,0 +1,160 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSave_DefaultsToDraft(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, EventStatusDraft, event.Status)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, EventStatusDraft, saved.Status)
+}
+
+func TestEventChangeStatus_Transitions(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	// drafts cannot skip publishing
+	err = event.ChangeStatus(context.Background(), EventStatusCompleted, "")
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	err = event.ChangeStatus(context.Background(), EventStatusPublished, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), event.Version)
+
+	err = event.ChangeStatus(context.Background(), EventStatusCompleted, "")
+	assert.NoError(t, err)
+
+	// completed is final
+	err = event.ChangeStatus(context.Background(), EventStatusCancelled, "")
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, EventStatusCompleted, saved.Status)
+}
+
+func TestEventChangeStatus_ErrorStaleVersion(t *testing.T) {
+	event := newTestEvent()
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	stale := event
+	err = event.ChangeStatus(context.Background(), EventStatusPublished, "")
+	assert.NoError(t, err)
+
+	// stale copy still believes it is a draft at version 1
+	err = stale.ChangeStatus(context.Background(), EventStatusPublished, "")
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+}
+
+func TestEventCancel_KeepsRegistrationsAndNotifies(t *testing.T) {
+	event := newTestEvent()
+	event.Status = EventStatusPublished
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	const attendee = int64(4242)
+	err = event.RegisterEvent(context.Background(), attendee, nil)
+	assert.NoError(t, err)
+
+	err = event.ChangeStatus(context.Background(), EventStatusCancelled, "venue flooded")
+	assert.NoError(t, err)
+	assert.Equal(t, "venue flooded", event.CancellationReason)
+
+	registered, err := event.IsRegistered(context.Background(), attendee)
+	assert.NoError(t, err)
+	assert.True(t, registered)
+
+	notifications, err := GetNotificationsByUser(context.Background(), attendee)
+	assert.NoError(t, err)
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, event.ID, notifications[0].EventID)
+		assert.Equal(t, NotificationEventCancelled, notifications[0].Kind)
+		assert.Contains(t, notifications[0].Message, "venue flooded")
+		assert.Nil(t, notifications[0].SentAt)
+	}
+
+	// cancelled events no longer accept registrations
+	err = event.RegisterEvent(context.Background(), attendee+1, nil)
+	assert.ErrorIs(t, err, ErrEventNotOpen)
+}
+
+func TestEventCancel_NotifiesOncePerUser(t *testing.T) {
+	event := newPublishedTestEvent(t, recurring("FREQ=WEEKLY;COUNT=4"))
+
+	first := event.DateTime
+	second := first.AddDate(0, 0, 7)
+
+	const attendee = int64(4343)
+	for _, start := range []time.Time{first, second} {
+		err := event.RegisterEvent(context.Background(), attendee, &start)
+		assert.NoError(t, err)
+	}
+
+	err := event.ChangeStatus(context.Background(), EventStatusCancelled, "speaker ill")
+	assert.NoError(t, err)
+
+	notifications, err := GetNotificationsByUser(context.Background(), attendee)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+}
+
+func TestGetAllEvents_FiltersByStatus(t *testing.T) {
+	const owner, other = int64(7001), int64(7002)
+
+	draft := newTestEvent()
+	draft.UserID = owner
+	err := draft.Save(context.Background())
+	assert.NoError(t, err)
+
+	cancelled := newTestEvent()
+	cancelled.UserID = owner
+	cancelled.Status = EventStatusPublished
+	err = cancelled.Save(context.Background())
+	assert.NoError(t, err)
+	err = cancelled.ChangeStatus(context.Background(), EventStatusCancelled, "")
+	assert.NoError(t, err)
+
+	published := newTestEvent()
+	published.UserID = owner
+	published.Status = EventStatusPublished
+	err = published.Save(context.Background())
+	assert.NoError(t, err)
+
+	ids := func(filter EventFilter) []int64 {
+		events, err := GetAllEvents(context.Background(), filter)
+		assert.NoError(t, err)
+
+		var ids []int64
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	// default hides drafts and cancelled events
+	listed := ids(EventFilter{ViewerID: owner})
+	assert.Contains(t, listed, published.ID)
+	assert.NotContains(t, listed, draft.ID)
+	assert.NotContains(t, listed, cancelled.ID)
+
+	listed = ids(EventFilter{Statuses: []EventStatus{EventStatusCancelled}})
+	assert.Contains(t, listed, cancelled.ID)
+
+	// drafts are only listed for their owner
+	all := []EventStatus{EventStatusDraft, EventStatusPublished}
+	assert.Contains(t, ids(EventFilter{Statuses: all, ViewerID: owner}), draft.ID)
+	assert.NotContains(t, ids(EventFilter{Statuses: all, ViewerID: other}), draft.ID)
+	assert.NotContains(t, ids(EventFilter{Statuses: all}), draft.ID)
+}
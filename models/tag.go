@@ -0,0 +1,170 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"slices"
+	"strings"
+
+	"example.com/event/db"
+)
+
+// MaxTagLength is the longest tag name accepted
+const MaxTagLength = 30
+
+// Tags are lowercase letters, digits and inner hyphens, e.g. "go" or "machine-learning"
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// TagCount is a tag with the number of events using it
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// TagFilter narrows GetTagCounts to the events a listing would show
+type TagFilter struct {
+	// Prefix (optional) only counts tags starting with it, for autocomplete
+	Prefix string
+
+	// Category (optional slug) and Tags only count events in the category carrying every tag
+	Category string
+	Tags     []string
+
+	Limit int
+}
+
+// ValidTag reports whether name is a valid tag once normalized
+func ValidTag(name string) bool {
+	name = normalizeTag(name)
+	return len(name) <= MaxTagLength && tagPattern.MatchString(name)
+}
+
+// NormalizeTags lowercases and trims the tags, dropping blanks and duplicates, sorted
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return normalized
+}
+
+func normalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// scanTags splits the comma-separated tags read by eventTagsColumn
+func scanTags(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	tags := strings.Split(value, ",")
+	slices.Sort(tags)
+
+	return tags
+}
+
+// writeTags replaces the tags of the event, creating the tags nobody used before
+func writeTags(ctx context.Context, tx *sql.Tx, eventId int64, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM event_tags WHERE event_id = ?`, eventId)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		_, err = tx.ExecContext(ctx, `INSERT INTO tags (name) VALUES (?) ON CONFLICT (name) DO NOTHING`, tag)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO event_tags (event_id, tag_id) SELECT ?, id FROM tags WHERE name = ?`, eventId, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tagConditions selects events carrying every tag and, when category is set, in that category
+func tagConditions(category string, tags []string) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+
+	if category != "" {
+		conditions = append(conditions, "AND category_id = (SELECT id FROM categories WHERE slug = ?)")
+		args = append(args, category)
+	}
+
+	for _, tag := range tags {
+		conditions = append(conditions, "AND id IN (SELECT event_tags.event_id FROM event_tags JOIN tags ON tags.id = event_tags.tag_id WHERE tags.name = ?)")
+		args = append(args, tag)
+	}
+
+	return strings.Join(conditions, "\n\t"), args
+}
+
+// GetTagCounts counts the public events listed by default (DefaultEventStatuses) using each tag, most used first.
+// Events are counted once, however many occurrences they have.
+func GetTagCounts(ctx context.Context, filter TagFilter) ([]TagCount, error) {
+	placeholders := make([]string, 0, len(DefaultEventStatuses))
+	args := make([]any, 0, len(DefaultEventStatuses))
+	for _, status := range DefaultEventStatuses {
+		placeholders = append(placeholders, "?")
+		args = append(args, status)
+	}
+
+	conditions, conditionArgs := tagConditions(filter.Category, NormalizeTags(filter.Tags))
+	args = append(args, conditionArgs...)
+
+	tenant, tenantArgs := tenantCondition(ctx, "organization_id")
+	args = append(args, tenantArgs...)
+
+	prefix := ""
+	if filter.Prefix != "" {
+		prefix = `AND tags.name LIKE ? ESCAPE '\'`
+		args = append(args, escapeLike(normalizeTag(filter.Prefix))+"%")
+	}
+
+	query := `
+	SELECT tags.name, COUNT(*) FROM event_tags JOIN tags ON tags.id = event_tags.tag_id
+	WHERE event_tags.event_id IN (
+		SELECT id FROM events
+		WHERE deleted_at IS NULL AND status IN (` + strings.Join(placeholders, ", ") + `)
+		` + conditions + `
+		AND visibility = 'public' AND ` + tenant + `
+	)
+	` + prefix + `
+	GROUP BY tags.name
+	ORDER BY COUNT(*) DESC, tags.name
+	LIMIT ?
+	`
+
+	rows, err := db.DB.QueryContext(ctx, query, append(args, filter.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	counts := []TagCount{}
+	for rows.Next() {
+		var c TagCount
+		err := rows.Scan(&c.Name, &c.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCategory(t *testing.T, slug string) Category {
+	category := Category{Slug: slug, Name: "Workshop"}
+
+	err := category.Save(context.Background())
+	assert.NoError(t, err)
+
+	return category
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"go", "web"}, NormalizeTags([]string{" Web", "go", "GO", ""}))
+
+	assert.True(t, ValidTag("Machine-Learning"))
+	assert.False(t, ValidTag("c++"))
+	assert.False(t, ValidTag("-go"))
+}
+
+func TestEventTags_SaveUpdate(t *testing.T) {
+	event := newTestEvent()
+	event.Tags = []string{"Go", "tag-test-web"}
+
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "tag-test-web"}, event.Tags)
+
+	saved, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "tag-test-web"}, saved.Tags)
+
+	saved.Tags = []string{"tag-test-cli"}
+	err = saved.UpdateColumns(context.Background(), []string{"tags"})
+	assert.NoError(t, err)
+
+	updated, err := GetEventByID(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tag-test-cli"}, updated.Tags)
+	assert.Equal(t, event.Version+1, updated.Version)
+
+	// the audit log has the tags before and after
+	entries, err := GetAuditLog(context.Background(), AuditFilter{EntityType: AuditEntityEvent, EntityID: event.ID, Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Contains(t, string(entries[0].Before), `"tags":["go","tag-test-web"]`)
+		assert.Contains(t, string(entries[0].After), `"tags":["tag-test-cli"]`)
+	}
+}
+
+func TestGetAllEvents_CategoryAndTags(t *testing.T) {
+	category := newTestCategory(t, "tag-test-workshop")
+
+	tagged := newTestEvent()
+	tagged.Name = "Tagged Workshop"
+	tagged.Status = EventStatusPublished
+	tagged.CategoryID = &category.ID
+	tagged.Tags = []string{"tag-test-go", "tag-test-beginner"}
+	err := tagged.Save(context.Background())
+	assert.NoError(t, err)
+
+	other := newTestEvent()
+	other.Name = "Tagged Talk"
+	other.Status = EventStatusPublished
+	other.Tags = []string{"tag-test-go"}
+	err = other.Save(context.Background())
+	assert.NoError(t, err)
+
+	for _, tt := range []struct {
+		filter   EventFilter
+		expected []string
+	}{
+		{EventFilter{Tags: []string{"tag-test-go"}}, []string{"Tagged Workshop", "Tagged Talk"}},
+		{EventFilter{Tags: []string{"tag-test-go", "tag-test-beginner"}}, []string{"Tagged Workshop"}},
+		{EventFilter{Category: "tag-test-workshop"}, []string{"Tagged Workshop"}},
+		{EventFilter{Category: "tag-test-workshop", Tags: []string{"tag-test-missing"}}, nil},
+	} {
+		events, err := GetAllEvents(context.Background(), tt.filter)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, tt.expected, eventNames(events, "Tagged Workshop", "Tagged Talk"), tt.filter)
+	}
+
+	events, err := GetAllEvents(context.Background(), EventFilter{Category: "tag-test-workshop"})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "tag-test-workshop", events[0].Category)
+		assert.Equal(t, []string{"tag-test-beginner", "tag-test-go"}, events[0].Tags)
+	}
+}
+
+func TestGetTagCounts(t *testing.T) {
+	category := newTestCategory(t, "tag-count-meetup")
+
+	for i, tags := range [][]string{{"tag-count-go", "tag-count-web"}, {"tag-count-go"}, {"tag-count-go", "tag-count-web"}} {
+		event := newTestEvent()
+		event.Status = EventStatusPublished
+		event.Tags = tags
+		if i == 0 {
+			event.CategoryID = &category.ID
+		}
+
+		err := event.Save(context.Background())
+		assert.NoError(t, err)
+	}
+
+	// drafts are not counted
+	draft := newTestEvent()
+	draft.Tags = []string{"tag-count-web"}
+	err := draft.Save(context.Background())
+	assert.NoError(t, err)
+
+	counts, err := GetTagCounts(context.Background(), TagFilter{Prefix: "TAG-COUNT", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []TagCount{{Name: "tag-count-go", Count: 3}, {Name: "tag-count-web", Count: 2}}, counts)
+
+	counts, err = GetTagCounts(context.Background(), TagFilter{Prefix: "tag-count", Category: "tag-count-meetup", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []TagCount{{Name: "tag-count-go", Count: 1}, {Name: "tag-count-web", Count: 1}}, counts)
+
+	counts, err = GetTagCounts(context.Background(), TagFilter{Prefix: "tag-count", Tags: []string{"tag-count-web"}, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []TagCount{{Name: "tag-count-go", Count: 2}}, counts)
+}
+
+func TestCategory_SaveDelete(t *testing.T) {
+	category := newTestCategory(t, "category-test")
+
+	duplicate := Category{Slug: "category-test", Name: "Again"}
+	err := duplicate.Save(context.Background())
+	assert.ErrorIs(t, err, ErrCategorySlugTaken)
+
+	event := newTestEvent()
+	event.CategoryID = &category.ID
+	err = event.Save(context.Background())
+	assert.NoError(t, err)
+
+	err = category.Delete(context.Background())
+	assert.ErrorIs(t, err, ErrCategoryInUse)
+
+	err = event.Delete(context.Background())
+	assert.NoError(t, err)
+
+	err = category.Delete(context.Background())
+	assert.NoError(t, err)
+
+	_, err = GetCategoryBySlug(context.Background(), "category-test")
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
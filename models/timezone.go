@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	// Zone data compiled in, hosts and containers without /usr/share/zoneinfo still resolve IANA names
+	_ "time/tzdata"
+)
+
+// DefaultTimeZone is used for events created without a time zone, and for events created before zones existed
+const DefaultTimeZone = "UTC"
+
+// DefaultEventDuration is used for events created without an end
+const DefaultEventDuration = time.Hour
+
+// LoadTimeZone resolves an IANA time zone name such as Asia/Jakarta
+func LoadTimeZone(name string) (*time.Location, error) {
+	// "" and "Local" are accepted by time.LoadLocation but depend on the server
+	if name == "" || name == "Local" {
+		return nil, errors.New("unknown time zone " + name)
+	}
+
+	return time.LoadLocation(name)
+}
+
+// TimeZoneName is the event's IANA time zone, DefaultTimeZone when unset
+func (e Event) TimeZoneName() string {
+	if e.TimeZone == "" {
+		return DefaultTimeZone
+	}
+
+	return e.TimeZone
+}
+
+// Zone is the event's time zone, UTC when its name cannot be resolved
+func (e Event) Zone() *time.Location {
+	loc, err := LoadTimeZone(e.TimeZoneName())
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// End is when the event finishes, DefaultEventDuration after the start when no end was set
+func (e Event) End() time.Time {
+	if e.EndDateTime.IsZero() {
+		return e.DateTime.Add(DefaultEventDuration)
+	}
+
+	return e.EndDateTime
+}
+
+// Duration is how long the event (and each of its occurrences) lasts
+func (e Event) Duration() time.Duration {
+	return e.End().Sub(e.DateTime)
+}
+
+// normalizeSchedule stores instants in UTC and fills in the defaults,
+// the original offset of a request is not kept, TimeZone is what renders local times
+func (e *Event) normalizeSchedule() {
+	e.EndDateTime = e.End().UTC()
+	e.DateTime = e.DateTime.UTC()
+	e.TimeZone = e.TimeZoneName()
+}
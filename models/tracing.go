@@ -0,0 +1,7 @@
+package models
+
+import "go.opentelemetry.io/otel"
+
+// tracer is used for spans around non-SQL work done in models (e.g. password hashing),
+// SQL statements are traced by the otelsql driver wrapper in db
+var tracer = otel.Tracer("example.com/event/models")
@@ -1,71 +1,124 @@
 package models
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"log/slog"
 
 	"example.com/event/db"
 	"example.com/event/utils"
 )
 
+// Roles a user can have, admins may act on any user's resources
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID       int64
-	Email    string `binding:"required,email"`
-	Password string `binding:"required"`
+	Email    string
+	Password string
+}
+
+// LogValue keeps the password out of logs whenever a User is logged
+func (u User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("id", u.ID),
+		slog.String("email", u.Email),
+	)
 }
 
-func (u *User) Save() error {
+func (u *User) Save(ctx context.Context) error {
 	query := `
 	INSERT INTO users (email, password) VALUES (?, ?)
 	`
 
-	stmt, err := db.DB.Prepare(query)
+	// bcrypt is intentionally slow, give it its own span (and keep it out of the transaction)
+	_, span := tracer.Start(ctx, "bcrypt.HashPassword")
+	hashedPassword, err := utils.HashPassword(u.Password)
+	span.End()
 	if err != nil {
 		return err
 	}
 
-	defer stmt.Close()
+	var userId int64
+	err = withTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
 
-	hashedPassword, err := utils.HashPassword(u.Password)
-	if err != nil {
-		return err
-	}
+		defer stmt.Close()
 
-	result, err := stmt.Exec(u.Email, hashedPassword)
-	if err != nil {
-		return err
-	}
+		result, err := stmt.ExecContext(ctx, u.Email, hashedPassword)
 
-	userId, err := result.LastInsertId()
+		// email column is UNIQUE
+		if isUniqueViolation(err) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		userId, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// Never the password, not even hashed
+		after := map[string]any{"email": u.Email, "role": RoleUser}
+
+		return writeAudit(ctx, tx, AuditCreate, AuditEntityUser, userId, nil, after)
+	})
 	if err != nil {
 		return err
 	}
 
 	u.ID = userId
 
-	return err
+	return nil
 }
 
-func (u *User) ValidateCredentials() error {
+func (u *User) ValidateCredentials(ctx context.Context) error {
 	query := `
 		SELECT id, password FROM users WHERE email = ?
 	`
 
-	row := db.DB.QueryRow(query, u.Email)
+	row := db.DB.QueryRowContext(ctx, query, u.Email)
 
 	var retrievedPassword string
 
 	// Scan will return error if no row matches the query
 	err := row.Scan(&u.ID, &retrievedPassword)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Do not reveal whether the email exists
+		return ErrInvalidCredentials
+	}
 	if err != nil {
-		// Return error if no user found
 		return err
 	}
 
+	_, span := tracer.Start(ctx, "bcrypt.CheckPassword")
 	isPasswordValid := utils.CheckPassword(u.Password, retrievedPassword)
+	span.End()
 
 	if !isPasswordValid {
-		return errors.New("invalid credentials")
+		return ErrInvalidCredentials
 	}
 
 	return nil
 }
+
+// IsAdmin reports whether the user has the admin role, unknown users are not admins
+func IsAdmin(ctx context.Context, userId int64) (bool, error) {
+	query := `
+	SELECT EXISTS(SELECT 1 FROM users WHERE id = ? AND role = ?)
+	`
+
+	var admin bool
+	err := db.DB.QueryRowContext(ctx, query, userId, RoleAdmin).Scan(&admin)
+
+	return admin, err
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"example.com/event/db"
+)
+
+// Venue is a place events are held at, shared by every event referencing it
+type Venue struct {
+	ID      int64
+	Name    string
+	Address Address
+
+	// Latitude and Longitude place the venue on a map, both set or both nil
+	Latitude  *float64
+	Longitude *float64
+
+	// Capacity is how many people the venue holds, nil when unknown.
+	// Events at the venue default to it.
+	Capacity *int
+
+	AccessibilityNotes string
+
+	// UserID is who added the venue, only they and admins can change it
+	UserID int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Columns selected for a Venue, in the order scanVenue expects them
+const venueSelectColumns = `id, name, address_street, address_city, address_region, address_postal_code, address_country, latitude, longitude, capacity, accessibility_notes, user_id, created_at, updated_at`
+
+func scanVenue(row scanner) (Venue, error) {
+	var v Venue
+
+	err := row.Scan(&v.ID, &v.Name, &v.Address.Street, &v.Address.City, &v.Address.Region, &v.Address.PostalCode, &v.Address.Country, &v.Latitude, &v.Longitude, &v.Capacity, &v.AccessibilityNotes, &v.UserID, &v.CreatedAt, &v.UpdatedAt)
+
+	return v, err
+}
+
+// Save inserts the venue
+func (v *Venue) Save(ctx context.Context) error {
+	query := `
+	INSERT INTO venues (name, address_street, address_city, address_region, address_postal_code, address_country, latitude, longitude, capacity, accessibility_notes, user_id, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now().UTC()
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, v.Name, v.Address.Street, v.Address.City, v.Address.Region, v.Address.PostalCode, v.Address.Country, v.Latitude, v.Longitude, v.Capacity, v.AccessibilityNotes, v.UserID, now, now)
+		if err != nil {
+			return err
+		}
+
+		v.ID, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		v.CreatedAt = now
+		v.UpdatedAt = now
+
+		return writeAudit(ctx, tx, AuditCreate, AuditEntityVenue, v.ID, nil, v.auditFields())
+	})
+}
+
+// GetAllVenues lists every venue by name
+func GetAllVenues(ctx context.Context) ([]Venue, error) {
+	rows, err := db.DB.QueryContext(ctx, `SELECT `+venueSelectColumns+` FROM venues ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+
+	// defer rows.Close() ensures that the rows are closed after processing
+	defer rows.Close()
+
+	venues := []Venue{}
+	for rows.Next() {
+		v, err := scanVenue(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		venues = append(venues, v)
+	}
+
+	return venues, rows.Err()
+}
+
+// GetVenueByID returns the venue or ErrVenueNotFound
+func GetVenueByID(ctx context.Context, venueId int64) (*Venue, error) {
+	return getVenue(ctx, db.DB, venueId)
+}
+
+func getVenue(ctx context.Context, q querier, venueId int64) (*Venue, error) {
+	v, err := scanVenue(q.QueryRowContext(ctx, `SELECT `+venueSelectColumns+` FROM venues WHERE id = ?`, venueId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVenueNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// Update writes every field of the venue but its owner. Events keep the details they were given,
+// only new events and events moved to the venue pick up the changes.
+func (v *Venue) Update(ctx context.Context) error {
+	query := `
+	UPDATE venues SET name = ?, address_street = ?, address_city = ?, address_region = ?, address_postal_code = ?, address_country = ?,
+	latitude = ?, longitude = ?, capacity = ?, accessibility_notes = ?, updated_at = ?
+	WHERE id = ?
+	`
+
+	updatedAt := time.Now().UTC()
+
+	return withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getVenue(ctx, tx, v.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, v.Name, v.Address.Street, v.Address.City, v.Address.Region, v.Address.PostalCode, v.Address.Country, v.Latitude, v.Longitude, v.Capacity, v.AccessibilityNotes, updatedAt, v.ID)
+		if err != nil {
+			return err
+		}
+
+		v.UserID = before.UserID
+		v.CreatedAt = before.CreatedAt
+		v.UpdatedAt = updatedAt
+
+		return writeAudit(ctx, tx, AuditUpdate, AuditEntityVenue, v.ID, before.auditFields(), v.auditFields())
+	})
+}
+
+// Delete removes the venue unless an event still takes place there (ErrVenueInUse).
+// Soft deleted events lose their reference to it, they keep their location.
+func (v Venue) Delete(ctx context.Context) error {
+	return withTx(ctx, func(tx *sql.Tx) error {
+		before, err := getVenue(ctx, tx, v.ID)
+		if err != nil {
+			return err
+		}
+
+		var inUse bool
+		err = tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE venue_id = ? AND deleted_at IS NULL)`, v.ID).Scan(&inUse)
+		if err != nil {
+			return err
+		}
+
+		if inUse {
+			return ErrVenueInUse
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE events SET venue_id = NULL WHERE venue_id = ?`, v.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM venues WHERE id = ?`, v.ID)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, AuditDelete, AuditEntityVenue, v.ID, before.auditFields(), nil)
+	})
+}
+
+// auditFields are the venue fields recorded in the audit log
+func (v Venue) auditFields() map[string]any {
+	return map[string]any{
+		"name":               v.Name,
+		"address":            v.Address,
+		"latitude":           v.Latitude,
+		"longitude":          v.Longitude,
+		"capacity":           v.Capacity,
+		"accessibilityNotes": v.AccessibilityNotes,
+		"userId":             v.UserID,
+	}
+}
+
+// ApplyTo fills what the event leaves out from the venue: location (the venue name), address, coordinates and capacity
+func (v Venue) ApplyTo(e *Event) {
+	e.VenueID = &v.ID
+
+	if e.Location == "" {
+		e.Location = v.Name
+	}
+	if e.Address.IsZero() {
+		e.Address = v.Address
+	}
+	if e.Latitude == nil && e.Longitude == nil {
+		e.Latitude, e.Longitude = v.Latitude, v.Longitude
+	}
+	if e.Capacity == nil {
+		e.Capacity = v.Capacity
+	}
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/event/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVenue(t *testing.T, capacity int) Venue {
+	venue := Venue{
+		Name:     "Sudirman Hall",
+		Address:  Address{Street: "Jl. Jend. Sudirman Kav. 52-53", City: "Jakarta", Country: "ID"},
+		Capacity: &capacity,
+		UserID:   1,
+	}
+
+	err := venue.Save(context.Background())
+	assert.NoError(t, err)
+
+	return venue
+}
+
+func TestVenue_SaveUpdateDelete(t *testing.T) {
+	venue := newTestVenue(t, 120)
+
+	saved, err := GetVenueByID(context.Background(), venue.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, venue.Address, saved.Address)
+	assert.Equal(t, 120, *saved.Capacity)
+
+	// the owner is kept whatever the update says
+	saved.Name = "Sudirman Grand Hall"
+	saved.UserID = 2
+	err = saved.Update(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), saved.UserID)
+
+	updated, err := GetVenueByID(context.Background(), venue.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sudirman Grand Hall", updated.Name)
+	assert.Equal(t, int64(1), updated.UserID)
+
+	err = venue.Delete(context.Background())
+	assert.NoError(t, err)
+
+	_, err = GetVenueByID(context.Background(), venue.ID)
+	assert.ErrorIs(t, err, ErrVenueNotFound)
+}
+
+func TestVenueDelete_ErrorInUse(t *testing.T) {
+	venue := newTestVenue(t, 120)
+
+	event := newTestEvent()
+	venue.ApplyTo(&event)
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	err = venue.Delete(context.Background())
+	assert.ErrorIs(t, err, ErrVenueInUse)
+
+	// once the event is deleted the venue can go, the event keeps its location
+	err = event.Delete(context.Background())
+	assert.NoError(t, err)
+
+	err = venue.Delete(context.Background())
+	assert.NoError(t, err)
+
+	deleted, err := getEventForAudit(context.Background(), db.DB, event.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, deleted.VenueID)
+	assert.Equal(t, "Jakarta", deleted.Location)
+}
+
+func TestGetAllEvents_AtVenue(t *testing.T) {
+	venue := newTestVenue(t, 120)
+
+	event := newTestEvent()
+	event.Status = EventStatusPublished
+	event.DateTime = time.Now().Add(24 * time.Hour).UTC()
+	event.Location = ""
+	venue.ApplyTo(&event)
+	err := event.Save(context.Background())
+	assert.NoError(t, err)
+
+	// elsewhere
+	newPublishedTestEvent(t)
+
+	now := time.Now()
+	events, err := GetAllEvents(context.Background(), EventFilter{VenueID: venue.ID, From: now, To: now.Add(MaxOccurrenceWindow)})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, event.ID, events[0].ID)
+		assert.Equal(t, "Sudirman Hall", events[0].Location)
+		assert.Equal(t, 120, *events[0].Capacity)
+	}
+}
+
+func TestRegisterEvent_ErrorFull(t *testing.T) {
+	event := newPublishedTestEvent(t)
+	capacity := 2
+	event.Capacity = &capacity
+
+	for _, userId := range []int64{6101, 6102} {
+		err := event.RegisterEvent(context.Background(), userId, nil)
+		assert.NoError(t, err)
+	}
+
+	err := event.RegisterEvent(context.Background(), 6103, nil)
+	assert.ErrorIs(t, err, ErrEventFull)
+}
+
+func TestRegisterEvent_ErrorFullOccurrence(t *testing.T) {
+	event := newPublishedTestEvent(t, recurring("FREQ=WEEKLY;COUNT=4"))
+	capacity := 2
+	event.Capacity = &capacity
+
+	first := event.DateTime
+	second := first.AddDate(0, 0, 7)
+
+	// a series registration takes a seat at every occurrence
+	err := event.RegisterEvent(context.Background(), 6201, nil)
+	assert.NoError(t, err)
+
+	err = event.RegisterEvent(context.Background(), 6202, &first)
+	assert.NoError(t, err)
+
+	err = event.RegisterEvent(context.Background(), 6203, &first)
+	assert.ErrorIs(t, err, ErrEventFull)
+
+	// other occurrences still have a seat, but the series is full at the first one
+	err = event.RegisterEvent(context.Background(), 6203, &second)
+	assert.NoError(t, err)
+
+	err = event.RegisterEvent(context.Background(), 6204, nil)
+	assert.ErrorIs(t, err, ErrEventFull)
+}
@@ -0,0 +1,45 @@
+package models
+
+import "slices"
+
+// EventVisibility is who can find and see an event
+type EventVisibility string
+
+const (
+	// VisibilityPublic events are listed and found by everyone
+	VisibilityPublic EventVisibility = "public"
+
+	// VisibilityUnlisted events are left out of listings and search, anyone with the link sees them
+	VisibilityUnlisted EventVisibility = "unlisted"
+
+	// VisibilityPrivate events are only seen by the users invited to them, and the people running them
+	VisibilityPrivate EventVisibility = "private"
+)
+
+// EventVisibilities lists every visibility, the most open first
+var EventVisibilities = []EventVisibility{VisibilityPublic, VisibilityUnlisted, VisibilityPrivate}
+
+func (v EventVisibility) Valid() bool {
+	return slices.Contains(EventVisibilities, v)
+}
+
+// VisibilityName is the event's visibility, public when it was never set
+func (e Event) VisibilityName() EventVisibility {
+	if e.Visibility == "" {
+		return VisibilityPublic
+	}
+
+	return e.Visibility
+}
+
+// visibleEventCondition lets through the events listed for the viewer: every event the viewer helps run
+// (as owner, member or admin of the organization hosting it), and the other events that are not drafts
+// when they are public or private with the viewer invited. prefix qualifies the events columns ("" or "e."),
+// the condition takes the viewer id four times.
+func visibleEventCondition(prefix string) string {
+	return `(` + prefix + `user_id = ?
+	OR ` + prefix + `id IN (SELECT event_id FROM event_members WHERE user_id = ?)
+	OR ` + prefix + `organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ? AND role IN ('owner', 'admin'))
+	OR ` + prefix + `status != 'draft' AND (` + prefix + `visibility = 'public'
+		OR ` + prefix + `visibility = 'private' AND ` + prefix + `id IN (SELECT event_id FROM event_invitations WHERE user_id = ?)))`
+}
@@ -0,0 +1,276 @@
+package problems
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"example.com/event/models"
+	"example.com/event/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ContentType of every error body (RFC 7807)
+const ContentType = "application/problem+json"
+
+// Non-standard status (popularised by nginx) for requests the client abandoned
+const StatusClientClosedRequest = 499
+
+var (
+	// ErrInvalidRequestBody wraps request bodies that could not be decoded at all
+	ErrInvalidRequestBody = errors.New("invalid request body")
+
+	// ErrUnsupportedMediaType is returned when the request Content-Type is not accepted by the route
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	// ErrInvalidPatch wraps a patch document that cannot be applied to the resource
+	ErrInvalidPatch = errors.New("patch cannot be applied")
+
+	// ErrPreconditionRequired is returned when a conditional header (If-Match) is required but missing
+	ErrPreconditionRequired = errors.New("precondition required")
+
+	// ErrPatchTestFailed is returned when a JSON Patch "test" operation does not match
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+)
+
+// Problem is an RFC 7807 problem details body.
+// Code is a stable machine-readable identifier clients can switch on.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"requestId,omitempty"`
+	Errors    []models.FieldError `json:"errors,omitempty"`
+
+	// Conflicts lists the overlapping bookings of a venue_conflict problem
+	Conflicts []models.VenueConflict `json:"conflicts,omitempty"`
+}
+
+type mapping struct {
+	err    error
+	status int
+	code   string
+	title  string
+}
+
+// Known errors and the problem they map to, checked in order with errors.Is
+var mappings = []mapping{
+	{models.ErrEventNotFound, http.StatusNotFound, "event_not_found", "Event not found"},
+	{models.ErrOccurrenceNotFound, http.StatusNotFound, "occurrence_not_found", "Occurrence not found"},
+	{models.ErrCalendarFeedNotFound, http.StatusNotFound, "calendar_feed_not_found", "Calendar feed not found"},
+	{models.ErrVenueNotFound, http.StatusNotFound, "venue_not_found", "Venue not found"},
+	{models.ErrCategoryNotFound, http.StatusNotFound, "category_not_found", "Category not found"},
+	{models.ErrOrganizationNotFound, http.StatusNotFound, "organization_not_found", "Organization not found"},
+	{models.ErrInvitationNotFound, http.StatusNotFound, "invitation_not_found", "Invitation not found"},
+	{models.ErrUserNotFound, http.StatusNotFound, "user_not_found", "User not found"},
+	{models.ErrMemberNotFound, http.StatusNotFound, "member_not_found", "Member not found"},
+	{models.ErrNotOwner, http.StatusForbidden, "not_owner", "Not the event owner"},
+	{models.ErrInvalidInvitation, http.StatusForbidden, "invalid_invitation", "Invalid invitation"},
+	{models.ErrNotInvited, http.StatusForbidden, "not_invited", "Not invited"},
+	{models.ErrNotOrganizationMember, http.StatusForbidden, "not_organization_member", "Not a member of the organization"},
+	{models.ErrRoleNotPermitted, http.StatusForbidden, "role_not_permitted", "Role not permitted"},
+	{models.ErrNotVenueOwner, http.StatusForbidden, "not_venue_owner", "Not the venue owner"},
+	{models.ErrAdminRequired, http.StatusForbidden, "admin_required", "Admin required"},
+	{models.ErrVersionMismatch, http.StatusPreconditionFailed, "precondition_failed", "Precondition failed"},
+	{models.ErrAlreadyRegistered, http.StatusConflict, "already_registered", "Already registered"},
+	{models.ErrNotRegistered, http.StatusConflict, "not_registered", "Not registered"},
+	{models.ErrNotCheckedIn, http.StatusConflict, "not_checked_in", "Not checked in"},
+	{models.ErrInvalidStatusTransition, http.StatusConflict, "invalid_status_transition", "Invalid status transition"},
+	{models.ErrEventNotOpen, http.StatusConflict, "event_not_open", "Event not open for registration"},
+	{models.ErrEventFull, http.StatusConflict, "event_full", "Event full"},
+	{models.ErrVenueInUse, http.StatusConflict, "venue_in_use", "Venue in use"},
+	{models.ErrVenueConflict, http.StatusConflict, "venue_conflict", "Venue already booked"},
+	{models.ErrCategorySlugTaken, http.StatusConflict, "category_slug_taken", "Category slug taken"},
+	{models.ErrCategoryInUse, http.StatusConflict, "category_in_use", "Category in use"},
+	{models.ErrAlreadyMember, http.StatusConflict, "already_member", "Already a member"},
+	{models.ErrAlreadyInvited, http.StatusConflict, "already_invited", "Already invited"},
+	{models.ErrOrganizationSlugTaken, http.StatusConflict, "organization_slug_taken", "Organization slug taken"},
+	{models.ErrLastOwner, http.StatusConflict, "last_owner", "Last owner"},
+	{models.ErrRestoreExpired, http.StatusGone, "restore_expired", "Restore window expired"},
+	{models.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", "Invalid credentials"},
+	{models.ErrEmailTaken, http.StatusConflict, "email_taken", "Email already registered"},
+	{utils.ErrMissingToken, http.StatusUnauthorized, "missing_token", "Not authorized"},
+	{utils.ErrInvalidToken, http.StatusUnauthorized, "invalid_token", "Could not verify token"},
+	{ErrInvalidRequestBody, http.StatusBadRequest, "invalid_request_body", "Could not parse request"},
+	{ErrPreconditionRequired, http.StatusPreconditionRequired, "precondition_required", "Precondition required"},
+	{ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type"},
+	{ErrPatchTestFailed, http.StatusConflict, "patch_test_failed", "Patch test failed"},
+	{ErrInvalidPatch, http.StatusUnprocessableEntity, "invalid_patch", "Patch cannot be applied"},
+	{stdcontext.DeadlineExceeded, http.StatusGatewayTimeout, "request_timeout", "Request timed out"},
+	{stdcontext.Canceled, StatusClientClosedRequest, "request_cancelled", "Request cancelled"},
+}
+
+// Token problems get a fixed detail, why verification failed is logged by Abort instead of returned
+var tokenDetails = []struct {
+	err    error
+	detail string
+}{
+	{utils.ErrExpiredToken, "The token has expired, log in again"},
+	{utils.ErrInvalidToken, "The token is malformed, not signed by this server or not meant for this request"},
+}
+
+// FromError maps err to a problem, anything unknown becomes a generic 500
+// that does not expose the underlying error
+func FromError(err error) Problem {
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		problem := New(http.StatusBadRequest, "validation_failed", "Validation failed", "One or more fields are invalid")
+		problem.Errors = validationErr.Fields
+		return problem
+	}
+
+	var conflictErr *models.VenueConflictError
+	if errors.As(err, &conflictErr) {
+		problem := New(http.StatusConflict, "venue_conflict", "Venue already booked", models.ErrVenueConflict.Error())
+		problem.Conflicts = conflictErr.Conflicts
+		return problem
+	}
+
+	for _, m := range mappings {
+		if errors.Is(err, m.err) {
+			return New(m.status, m.code, m.title, detail(err))
+		}
+	}
+
+	return New(http.StatusInternalServerError, "internal_error", "Internal server error", "An unexpected error occurred")
+}
+
+// detail describes err to the client, token errors with a fixed text
+func detail(err error) string {
+	for _, t := range tokenDetails {
+		if errors.Is(err, t.err) {
+			return t.detail
+		}
+	}
+
+	return err.Error()
+}
+
+func New(status int, code, title, detail string) Problem {
+	return Problem{
+		Type:   "/problems/" + strings.ReplaceAll(code, "_", "-"),
+		Title:  title,
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Abort maps err to a problem and writes it, internal errors are logged instead of returned
+func Abort(context *gin.Context, err error) {
+	problem := FromError(err)
+
+	// Attach to context so the error ends up in the access log
+	_ = context.Error(err)
+
+	if problem.Code == "internal_error" {
+		slog.ErrorContext(context.Request.Context(), "internal error",
+			"error", err,
+			"path", context.Request.URL.Path,
+		)
+	}
+
+	if errors.Is(err, utils.ErrInvalidToken) {
+		slog.WarnContext(context.Request.Context(), "token rejected",
+			"error", err,
+			"path", context.Request.URL.Path,
+		)
+	}
+
+	AbortWithProblem(context, problem)
+}
+
+// AbortWithProblem writes problem as application/problem+json and stops the handler chain
+func AbortWithProblem(context *gin.Context, problem Problem) {
+	problem.Instance = context.Request.URL.Path
+	problem.RequestID = context.GetString("requestId")
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		context.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	context.Data(problem.Status, ContentType, body)
+	context.Abort()
+}
+
+// BindError converts an error from ShouldBindJSON into a validation error (per field)
+// or ErrInvalidRequestBody when the body is not valid JSON for the target type
+func BindError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		result := &models.ValidationError{}
+		for _, fe := range validationErrs {
+			result.Add(fe.Field(), validationMessage(fe))
+		}
+		return result
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return models.NewValidationError(typeErr.Field, "must be of type "+typeErr.Type.String())
+	}
+
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: request body is empty", ErrInvalidRequestBody)
+	}
+
+	return fmt.Errorf("%w: %v", ErrInvalidRequestBody, err)
+}
+
+// validationMessage turns a validator tag into a readable message
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must be at least " + fe.Param() + sizeUnit(fe)
+	case "max":
+		return "must be at most " + fe.Param() + sizeUnit(fe)
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "url":
+		return "must be an absolute URL"
+	case "hexcolor":
+		return "must be a hex color such as #00ADD8"
+	case "latitude":
+		return "must be a latitude between -90 and 90"
+	case "longitude":
+		return "must be a longitude between -180 and 180"
+	case "tag":
+		return "must be a tag of letters, digits and hyphens such as machine-learning, at most 30 characters"
+	case "slug":
+		return "must be a slug of lowercase letters, digits and hyphens such as workshop"
+	case "iso3166_1_alpha2":
+		return "must be an ISO 3166-1 alpha-2 country code such as ID"
+	default:
+		return "failed " + fe.Tag() + " validation"
+	}
+}
+
+// sizeUnit is what min and max count: characters of strings, items of lists, nothing for numbers
+func sizeUnit(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,98 @@
+package problems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/event/models"
+	"example.com/event/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromError_DomainErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{models.ErrEventNotFound, http.StatusNotFound, "event_not_found"},
+		{models.ErrNotOwner, http.StatusForbidden, "not_owner"},
+		{models.ErrAlreadyRegistered, http.StatusConflict, "already_registered"},
+		{fmt.Errorf("wrapped: %w", models.ErrEventNotFound), http.StatusNotFound, "event_not_found"},
+	}
+
+	for _, tt := range tests {
+		problem := FromError(tt.err)
+
+		assert.Equal(t, tt.status, problem.Status)
+		assert.Equal(t, tt.code, problem.Code)
+	}
+}
+
+func TestFromError_ValidationError(t *testing.T) {
+	validationErr := models.NewValidationError("name", "is required")
+	validationErr.Add("location", "is required")
+
+	problem := FromError(validationErr)
+
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "validation_failed", problem.Code)
+	assert.Len(t, problem.Errors, 2)
+}
+
+func TestFromError_VenueConflictError(t *testing.T) {
+	conflictErr := &models.VenueConflictError{Conflicts: []models.VenueConflict{{EventID: 7, Name: "Go Meetup"}}}
+
+	problem := FromError(fmt.Errorf("wrapped: %w", conflictErr))
+
+	assert.Equal(t, http.StatusConflict, problem.Status)
+	assert.Equal(t, "venue_conflict", problem.Code)
+	assert.Equal(t, conflictErr.Conflicts, problem.Conflicts)
+	assert.ErrorIs(t, conflictErr, models.ErrVenueConflict)
+}
+
+func TestFromError_FixedTokenDetails(t *testing.T) {
+	tests := []struct {
+		err    error
+		detail string
+	}{
+		{fmt.Errorf("%w: token is malformed: could not base64 decode header", utils.ErrInvalidToken), "The token is malformed, not signed by this server or not meant for this request"},
+		{fmt.Errorf("%w: token has invalid claims: token is expired", utils.ErrExpiredToken), "The token has expired, log in again"},
+	}
+
+	for _, tt := range tests {
+		problem := FromError(tt.err)
+
+		assert.Equal(t, http.StatusUnauthorized, problem.Status)
+		assert.Equal(t, "invalid_token", problem.Code)
+		assert.Equal(t, tt.detail, problem.Detail)
+	}
+}
+
+func TestAbort_HidesInternalErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/boom", func(context *gin.Context) {
+		Abort(context, errors.New("sql: database is locked"))
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/boom", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.NotContains(t, w.Body.String(), "database is locked")
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "internal_error", response["code"])
+	assert.Equal(t, "/boom", response["instance"])
+}
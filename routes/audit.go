@@ -0,0 +1,124 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+)
+
+type AuditEntryResponse struct {
+	ID         int64           `json:"id"`
+	ActorID    *int64          `json:"actorId"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   int64           `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+func newAuditEntryResponses(entries []models.AuditEntry) []AuditEntryResponse {
+	responses := make([]AuditEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		responses = append(responses, AuditEntryResponse{
+			ID:         e.ID,
+			ActorID:    e.ActorID,
+			Action:     e.Action,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			Before:     e.Before,
+			After:      e.After,
+			RequestID:  e.RequestID,
+			IP:         e.IP,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+
+	return responses
+}
+
+func getAuditLog(context *gin.Context) {
+	filter, err := parseAuditFilter(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	entries, err := handlers.GetAuditLog(context.Request.Context(), filter)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Audit log",
+		"data":    newAuditEntryResponses(entries),
+	})
+}
+
+// parseAuditFilter reads the query, reporting every invalid parameter at once
+func parseAuditFilter(context *gin.Context) (models.AuditFilter, error) {
+	validationErr := &models.ValidationError{}
+
+	filter := models.AuditFilter{
+		EntityType: context.Query("entity_type"),
+		Action:     context.Query("action"),
+		EntityID:   parsePositiveQuery(context, "entity_id", validationErr),
+		ActorID:    parsePositiveQuery(context, "actor_id", validationErr),
+		BeforeID:   parsePositiveQuery(context, "before_id", validationErr),
+		Limit:      int(parsePositiveQuery(context, "limit", validationErr)),
+	}
+
+	entityTypes := []string{models.AuditEntityEvent, models.AuditEntityUser, models.AuditEntityRegistration, models.AuditEntityOccurrence, models.AuditEntityVenue, models.AuditEntityCategory, models.AuditEntityMember, models.AuditEntityOrganization, models.AuditEntityOrganizationMember, models.AuditEntityInvitation}
+	if filter.EntityType != "" && !slices.Contains(entityTypes, filter.EntityType) {
+		validationErr.Add("entity_type", "must be one of event, user, registration, occurrence, venue, category, member, organization, organization_member, invitation")
+	}
+
+	actions := []string{models.AuditCreate, models.AuditUpdate, models.AuditDelete, models.AuditRestore, models.AuditPurge}
+	if filter.Action != "" && !slices.Contains(actions, filter.Action) {
+		validationErr.Add("action", "must be one of create, update, delete, restore, purge")
+	}
+
+	if filter.Limit > maxAuditLimit {
+		validationErr.Add("limit", "must be at most "+strconv.Itoa(maxAuditLimit))
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return filter, validationErr
+	}
+
+	return filter, nil
+}
+
+// parsePositiveQuery reads an optional positive integer query parameter, 0 when absent
+func parsePositiveQuery(context *gin.Context, name string, validationErr *models.ValidationError) int64 {
+	value := context.Query(name)
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		validationErr.Add(name, "must be a positive integer")
+		return 0
+	}
+
+	return n
+}
@@ -0,0 +1,122 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/event/handlers"
+	"example.com/event/middlewares"
+	"example.com/event/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks the audit log with user 3 as the only admin
+func mockAuditLog(t *testing.T) *models.AuditFilter {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalIsAdmin := handlers.IsAdmin
+	originalGetAuditLog := handlers.GetAuditLog
+	t.Cleanup(func() {
+		handlers.IsAdmin = originalIsAdmin
+		handlers.GetAuditLog = originalGetAuditLog
+	})
+
+	handlers.IsAdmin = func(ctx context.Context, userId int64) (bool, error) {
+		return userId == 3, nil
+	}
+
+	received := &models.AuditFilter{}
+	handlers.GetAuditLog = func(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+		*received = filter
+		return []models.AuditEntry{{ID: 1, Action: models.AuditCreate, EntityType: models.AuditEntityEvent, EntityID: 7}}, nil
+	}
+
+	return received
+}
+
+func TestGetAuditLog_RequiresAdmin(t *testing.T) {
+	mockAuditLog(t)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"":       http.StatusUnauthorized,
+		"user-1": http.StatusForbidden,
+		"user-3": http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/audit", http.NoBody)
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+}
+
+func TestGetAuditLog_Filters(t *testing.T) {
+	received := mockAuditLog(t)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/audit?entity_type=event&entity_id=7&actor_id=1&before_id=100", http.NoBody)
+	req.Header.Set("Authorization", "user-3")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.AuditFilter{EntityType: "event", EntityID: 7, ActorID: 1, BeforeID: 100, Limit: defaultAuditLimit}, *received)
+	assert.Contains(t, w.Body.String(), `"entityType":"event"`)
+}
+
+func TestGetAuditLog_ErrorInvalidFilters(t *testing.T) {
+	mockAuditLog(t)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/audit?entity_type=ticket&actor_id=abc&limit=1000", http.NoBody)
+	req.Header.Set("Authorization", "user-3")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	for _, field := range []string{"entity_type", "actor_id", "limit"} {
+		assert.Contains(t, w.Body.String(), `"field":"`+field+`"`)
+	}
+}
+
+func TestAuditActor_ThreadedToHandlers(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalCreateEvent := handlers.CreateEvent
+	defer func() {
+		handlers.CreateEvent = originalCreateEvent
+	}()
+
+	var actor models.AuditActor
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
+		actor = models.AuditActorFrom(ctx)
+		return nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares.RequestID, middlewares.AuditActor)
+	router.POST("/events", middlewares.Authenticate, createEvent)
+
+	req, _ := http.NewRequest(http.MethodPost, "/events", toJSON(t, createEventPayload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-1")
+	req.Header.Set(middlewares.RequestIDHeader, "req-42")
+	req.RemoteAddr = "198.51.100.4:5555"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, models.AuditActor{UserID: 1, RequestID: "req-42", IP: "198.51.100.4"}, actor)
+}
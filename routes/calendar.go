@@ -0,0 +1,195 @@
+package routes
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/ics"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"example.com/event/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// calendarProdID identifies this API in exported calendars
+const calendarProdID = "-//golang-event//Events//EN"
+
+// USER_CALENDAR_PATH is the feed calendar clients subscribe to with ?token=
+const USER_CALENDAR_PATH = "/api/v1/users/me/calendar.ics"
+
+// Subscribed clients refresh on their own schedule, a short cache spares repeated hits
+const calendarCacheControl = "private, max-age=300"
+
+var icsStatuses = map[models.EventStatus]string{
+	models.EventStatusDraft:     ics.StatusTentative,
+	models.EventStatusPublished: ics.StatusConfirmed,
+	models.EventStatusCompleted: ics.StatusConfirmed,
+	models.EventStatusCancelled: ics.StatusCancelled,
+}
+
+// toICSEvent maps an event or an occurrence, each occurrence gets its own UID derived from the start the rule gave it
+func toICSEvent(e models.Event) ics.Event {
+	uid := fmt.Sprintf("event-%d@golang-event", e.ID)
+	if e.OccurrenceStart != nil {
+		uid = fmt.Sprintf("event-%d-%s@golang-event", e.ID, e.OccurrenceStart.UTC().Format("20060102T150405Z"))
+	}
+
+	stamp := e.UpdatedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	// SEQUENCE starts at 0 and grows with every revision
+	sequence := max(e.Version-1, 0)
+
+	return ics.Event{
+		UID:         uid,
+		Stamp:       stamp,
+		Sequence:    sequence,
+		Start:       e.DateTime,
+		End:         e.End(),
+		Summary:     e.Name,
+		Description: e.Description,
+		Location:    e.Location,
+		Status:      icsStatuses[e.Status],
+	}
+}
+
+// calendarWindow is the span of recurring events exported, from a month ago to a year ahead
+func calendarWindow() (time.Time, time.Time) {
+	now := time.Now().UTC()
+	return now.Add(-models.CalendarFeedPast), now.Add(models.CalendarFeedAhead)
+}
+
+// writeCalendar renders the events as an iCalendar document
+func writeCalendar(context *gin.Context, name string, events []models.Event) {
+	calendar := ics.Calendar{
+		ProdID: calendarProdID,
+		Name:   name,
+		Events: make([]ics.Event, 0, len(events)),
+	}
+
+	for _, e := range events {
+		calendar.Events = append(calendar.Events, toICSEvent(e))
+	}
+
+	var buf bytes.Buffer
+	err := calendar.Write(&buf)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.Header("Cache-Control", calendarCacheControl)
+	context.Data(http.StatusOK, ics.MediaType, buf.Bytes())
+}
+
+// getEventsCalendar is the public feed of published events, cancelled ones stay so subscribers see the cancellation
+func getEventsCalendar(context *gin.Context) {
+	from, to := calendarWindow()
+
+	events, err := handlers.GetAllEvents(context.Request.Context(), models.EventFilter{
+		Statuses: []models.EventStatus{models.EventStatusPublished, models.EventStatusCompleted, models.EventStatusCancelled},
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	writeCalendar(context, "Events", events)
+}
+
+// getEventCalendar exports one event, recurring events as their occurrences within the calendar window
+func getEventCalendar(context *gin.Context) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	event, err := findEvent(context, eventId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	events := []models.Event{*event}
+	if event.Recurrence != nil {
+		from, to := calendarWindow()
+
+		events, err = handlers.GetOccurrences(context.Request.Context(), event, from, to)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+	}
+
+	context.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.ics"`, event.ID))
+	writeCalendar(context, event.Name, events)
+}
+
+// getUserCalendar is the feed of the events a user registered to.
+// Calendar clients cannot send headers, so the feed token in ?token= authenticates instead of a JWT.
+func getUserCalendar(context *gin.Context) {
+	token := context.Query("token")
+	if token == "" {
+		problems.Abort(context, utils.ErrMissingToken)
+		return
+	}
+
+	userId, err := handlers.GetUserIDByCalendarToken(context.Request.Context(), token)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	from, to := calendarWindow()
+
+	events, err := handlers.GetRegisteredEvents(context.Request.Context(), userId, from, to)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	writeCalendar(context, "My events", events)
+}
+
+type CalendarTokenResponse struct {
+	Token   string `json:"token"`
+	FeedURL string `json:"feedUrl"`
+}
+
+// rotateCalendarToken issues the current user's feed token, replacing (and revoking) any previous one
+func rotateCalendarToken(context *gin.Context) {
+	token, err := handlers.RotateCalendarToken(context.Request.Context(), context.GetInt64("userId"))
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Calendar feed token issued, it is only shown once",
+		"data": CalendarTokenResponse{
+			Token:   token,
+			FeedURL: USER_CALENDAR_PATH + "?token=" + url.QueryEscape(token),
+		},
+	})
+}
+
+func revokeCalendarToken(context *gin.Context) {
+	err := handlers.RevokeCalendarToken(context.Request.Context(), context.GetInt64("userId"))
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Calendar feed token revoked",
+	})
+}
@@ -0,0 +1,282 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/ics"
+	"example.com/event/models"
+	"example.com/event/utils"
+	goical "github.com/emersion/go-ical"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeCalendar checks the response is an RFC 5545 calendar: CRLF lines of at most 75 octets
+// that go-ical parses and can encode again, which enforces the required VEVENT properties
+func decodeCalendar(t *testing.T, w *httptest.ResponseRecorder) []goical.Event {
+	t.Helper()
+
+	assert.Equal(t, ics.MediaType, w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasSuffix(body, "\r\n"))
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+
+	calendar, err := goical.NewDecoder(strings.NewReader(body)).Decode()
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	err = goical.NewEncoder(&bytes.Buffer{}).Encode(calendar)
+	assert.NoError(t, err)
+
+	return calendar.Events()
+}
+
+func TestGetEventCalendar(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/7/calendar.ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="event-7.ics"`, w.Header().Get("Content-Disposition"))
+
+	events := decodeCalendar(t, w)
+	if !assert.Len(t, events, 1) {
+		return
+	}
+
+	uid, _ := events[0].Props.Text(goical.PropUID)
+	assert.Equal(t, "event-7@golang-event", uid)
+
+	start, err := events[0].DateTimeStart(nil)
+	assert.NoError(t, err)
+	assert.True(t, start.Equal(time.Date(2030, 12, 16, 9, 0, 0, 0, time.UTC)))
+
+	end, err := events[0].DateTimeEnd(nil)
+	assert.NoError(t, err)
+	assert.True(t, end.Equal(start.Add(models.DefaultEventDuration)))
+
+	// version 2 is the first revision after creation
+	assert.Equal(t, "1", events[0].Props.Get(goical.PropSequence).Value)
+
+	status, _ := events[0].Status()
+	assert.Equal(t, goical.EventConfirmed, status)
+}
+
+func TestGetEventCalendar_DraftOnlyVisibleToOwner(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusDraft)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"":       http.StatusNotFound,
+		"user-2": http.StatusNotFound,
+		"user-1": http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/7/calendar.ics", nil)
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+}
+
+func TestGetEventCalendar_RecurringExportsOccurrences(t *testing.T) {
+	originalGetEventByID := handlers.GetEventByID
+	originalGetOccurrences := handlers.GetOccurrences
+	defer func() {
+		handlers.GetEventByID = originalGetEventByID
+		handlers.GetOccurrences = originalGetOccurrences
+	}()
+
+	series := models.Event{
+		ID:         7,
+		Name:       "Weekly Go meetup",
+		DateTime:   time.Date(2031, 1, 7, 9, 0, 0, 0, time.UTC),
+		Status:     models.EventStatusPublished,
+		Version:    1,
+		Recurrence: &models.Recurrence{Rule: "FREQ=WEEKLY"},
+	}
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &series, nil
+	}
+
+	var from, to time.Time
+	handlers.GetOccurrences = func(ctx context.Context, event *models.Event, f, t time.Time) ([]models.Event, error) {
+		from, to = f, t
+
+		var occurrences []models.Event
+		for i := range 3 {
+			o := *event
+			start := event.DateTime.AddDate(0, 0, 7*i)
+			o.DateTime, o.OccurrenceStart = start, &start
+			occurrences = append(occurrences, o)
+		}
+		occurrences[1].Status = models.EventStatusCancelled
+
+		return occurrences, nil
+	}
+
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/7/calendar.ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.WithinDuration(t, time.Now().Add(-models.CalendarFeedPast), from, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(models.CalendarFeedAhead), to, time.Minute)
+
+	events := decodeCalendar(t, w)
+	if !assert.Len(t, events, 3) {
+		return
+	}
+
+	// one VEVENT per occurrence, each with its own UID
+	uid, _ := events[1].Props.Text(goical.PropUID)
+	assert.Equal(t, "event-7-20310114T090000Z@golang-event", uid)
+
+	status, _ := events[1].Status()
+	assert.Equal(t, goical.EventCancelled, status)
+}
+
+func TestGetEventsCalendar(t *testing.T) {
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() { handlers.GetAllEvents = originalGetAllEvents }()
+
+	var filter models.EventFilter
+	handlers.GetAllEvents = func(ctx context.Context, f models.EventFilter) ([]models.Event, error) {
+		filter = f
+		return []models.Event{
+			{ID: 1, Name: "Go Workshop; Jakarta, Indonesia", Location: "Jakarta", DateTime: time.Date(2030, 12, 16, 2, 0, 0, 0, time.UTC), Status: models.EventStatusPublished, Version: 1},
+			{ID: 2, Name: "Cancelled meetup", DateTime: time.Date(2030, 12, 20, 2, 0, 0, 0, time.UTC), Status: models.EventStatusCancelled, Version: 3},
+		}, nil
+	}
+
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/calendar.ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.ElementsMatch(t, []models.EventStatus{models.EventStatusPublished, models.EventStatusCompleted, models.EventStatusCancelled}, filter.Statuses)
+	assert.False(t, filter.From.IsZero())
+
+	events := decodeCalendar(t, w)
+	if assert.Len(t, events, 2) {
+		summary, _ := events[0].Props.Text(goical.PropSummary)
+		assert.Equal(t, "Go Workshop; Jakarta, Indonesia", summary)
+	}
+}
+
+func TestGetUserCalendar(t *testing.T) {
+	originalGetUserID := handlers.GetUserIDByCalendarToken
+	originalGetRegisteredEvents := handlers.GetRegisteredEvents
+	defer func() {
+		handlers.GetUserIDByCalendarToken = originalGetUserID
+		handlers.GetRegisteredEvents = originalGetRegisteredEvents
+	}()
+
+	handlers.GetUserIDByCalendarToken = func(ctx context.Context, token string) (int64, error) {
+		if token != "feed-secret" {
+			return 0, utils.ErrInvalidToken
+		}
+		return 4, nil
+	}
+
+	var userId int64
+	handlers.GetRegisteredEvents = func(ctx context.Context, id int64, from, to time.Time) ([]models.Event, error) {
+		userId = id
+		return []models.Event{
+			{ID: 1, Name: "Go Workshop Jakarta", DateTime: time.Date(2030, 12, 16, 2, 0, 0, 0, time.UTC), Status: models.EventStatusPublished, Version: 1},
+		}, nil
+	}
+
+	router := setupRegisteredRouter()
+
+	for query, expected := range map[string]int{
+		"":                   http.StatusUnauthorized,
+		"?token=wrong":       http.StatusUnauthorized,
+		"?token=feed-secret": http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, USER_CALENDAR_PATH+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, query)
+		if w.Code == http.StatusOK {
+			assert.Len(t, decodeCalendar(t, w), 1)
+		}
+	}
+
+	assert.Equal(t, int64(4), userId)
+}
+
+func TestCalendarToken_RotateAndRevoke(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalRotate := handlers.RotateCalendarToken
+	originalRevoke := handlers.RevokeCalendarToken
+	defer func() {
+		handlers.RotateCalendarToken = originalRotate
+		handlers.RevokeCalendarToken = originalRevoke
+	}()
+
+	var rotatedFor, revokedFor int64
+	handlers.RotateCalendarToken = func(ctx context.Context, userId int64) (string, error) {
+		rotatedFor = userId
+		return "feed-secret", nil
+	}
+	handlers.RevokeCalendarToken = func(ctx context.Context, userId int64) error {
+		revokedFor = userId
+		return models.ErrCalendarFeedNotFound
+	}
+
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/users/me/calendar-token", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/users/me/calendar-token", nil)
+	req.Header.Set("Authorization", "user-3")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, int64(3), rotatedFor)
+
+	var response struct {
+		Data CalendarTokenResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "feed-secret", response.Data.Token)
+	assert.Equal(t, USER_CALENDAR_PATH+"?token=feed-secret", response.Data.FeedURL)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/v1/users/me/calendar-token", nil)
+	req.Header.Set("Authorization", "user-3")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, int64(3), revokedFor)
+	assert.Contains(t, w.Body.String(), "calendar_feed_not_found")
+}
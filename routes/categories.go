@@ -0,0 +1,195 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryRequest is the body accepted when creating or updating a category
+type CategoryRequest struct {
+	Slug        string `json:"slug" binding:"required,slug,max=50"`
+	Name        string `json:"name" binding:"required,notblank,max=100"`
+	Description string `json:"description,omitempty" binding:"max=500"`
+}
+
+type CategoryResponse struct {
+	ID          int64  `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// EventCount is the number of published and completed events in the category, only set when listing
+	EventCount *int `json:"eventCount,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (r CategoryRequest) toCategory() models.Category {
+	return models.Category{
+		Slug:        r.Slug,
+		Name:        strings.TrimSpace(r.Name),
+		Description: strings.TrimSpace(r.Description),
+	}
+}
+
+func newCategoryResponse(c models.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:          c.ID,
+		Slug:        c.Slug,
+		Name:        c.Name,
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+func bindCategoryRequest(context *gin.Context) (CategoryRequest, error) {
+	var request CategoryRequest
+
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		return request, problems.BindError(err)
+	}
+
+	return request, nil
+}
+
+func getCategories(context *gin.Context) {
+	categories, err := handlers.GetAllCategories(context.Request.Context())
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]CategoryResponse, 0, len(categories))
+	for _, c := range categories {
+		response := newCategoryResponse(c)
+		response.EventCount = &c.EventCount
+		responses = append(responses, response)
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of categories",
+		"data":    responses,
+	})
+}
+
+func getCategoryById(context *gin.Context) {
+	category, err := findCategory(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Successfully get category details",
+		"data":    newCategoryResponse(*category),
+	})
+}
+
+func createCategory(context *gin.Context) {
+	request, err := bindCategoryRequest(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	category := request.toCategory()
+
+	err = handlers.CreateCategory(context.Request.Context(), &category)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Category created successfully",
+		"data":    newCategoryResponse(category),
+	})
+}
+
+// updateCategory may change the slug, events follow since they reference the category by id
+func updateCategory(context *gin.Context) {
+	request, err := bindCategoryRequest(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	category, err := findCategory(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	updatedCategory := request.toCategory()
+	updatedCategory.ID = category.ID
+
+	err = handlers.UpdateCategory(context.Request.Context(), &updatedCategory)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Category updated successfully",
+		"data":    newCategoryResponse(updatedCategory),
+	})
+}
+
+func deleteCategory(context *gin.Context) {
+	category, err := findCategory(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = handlers.DeleteCategory(context.Request.Context(), category)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Category deleted successfully",
+	})
+}
+
+// findCategory loads the category of the :categoryId path parameter
+func findCategory(context *gin.Context) (*models.Category, error) {
+	categoryId, err := strconv.ParseInt(context.Param("categoryId"), 10, 64)
+	if err != nil || categoryId <= 0 {
+		return nil, models.NewValidationError("categoryId", "must be a positive integer")
+	}
+
+	return handlers.GetCategoryByID(context.Request.Context(), categoryId)
+}
+
+// applyCategory resolves the category slug of the event, an unknown category is a validation error
+func applyCategory(context *gin.Context, event *models.Event) error {
+	if event.Category == "" {
+		event.CategoryID = nil
+		return nil
+	}
+
+	category, err := handlers.GetCategoryBySlug(context.Request.Context(), event.Category)
+	if errors.Is(err, models.ErrCategoryNotFound) {
+		return models.NewValidationError("category", "must be the slug of an existing category")
+	}
+	if err != nil {
+		return err
+	}
+
+	event.CategoryID = &category.ID
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCategories serves category 4 ("workshop") on top of mockVenues, user 3 is an admin
+func mockCategories(t *testing.T) *models.Event {
+	created, _ := mockVenues(t)
+
+	originalGetCategoryBySlug := handlers.GetCategoryBySlug
+	originalCreateCategory := handlers.CreateCategory
+	t.Cleanup(func() {
+		handlers.GetCategoryBySlug = originalGetCategoryBySlug
+		handlers.CreateCategory = originalCreateCategory
+	})
+
+	handlers.GetCategoryBySlug = func(ctx context.Context, slug string) (*models.Category, error) {
+		if slug != "workshop" {
+			return nil, models.ErrCategoryNotFound
+		}
+
+		return &models.Category{ID: 4, Slug: "workshop", Name: "Workshop"}, nil
+	}
+
+	handlers.CreateCategory = func(ctx context.Context, category *models.Category) error {
+		category.ID = 4
+		return nil
+	}
+
+	return created
+}
+
+func TestCreateCategory_AdminOnly(t *testing.T) {
+	mockCategories(t)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"user-1": http.StatusForbidden,
+		"user-3": http.StatusCreated,
+	} {
+		body := toJSON(t, map[string]any{"slug": "workshop", "name": "Workshop"})
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/categories", body)
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+
+	body := toJSON(t, map[string]any{"slug": "Hands On", "name": "Workshop"})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/categories", body)
+	req.Header.Set("Authorization", "user-3")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"slug"`)
+}
+
+func TestCreateEvent_CategoryAndTags(t *testing.T) {
+	created := mockCategories(t)
+	router := setupRegisteredRouter()
+
+	newRequest := func(category string, tags []string) *http.Request {
+		body := toJSON(t, map[string]any{
+			"name":        "Go Workshop Jakarta",
+			"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+			"location":    "Jakarta",
+			"dateTime":    "2030-12-16T09:00:00+07:00",
+			"category":    category,
+			"tags":        tags,
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events", body)
+		req.Header.Set("Authorization", "user-1")
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest("workshop", []string{"Go", "web", "go"}))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if assert.NotNil(t, created.CategoryID) {
+		assert.Equal(t, int64(4), *created.CategoryID)
+	}
+	assert.Equal(t, []string{"go", "web"}, created.Tags)
+	assert.Contains(t, w.Body.String(), `"tags":["go","web"]`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest("meetup", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"category"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest("workshop", []string{"go", "c++"}))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"tags[1]"`)
+}
+
+func TestGetEvents_CategoryAndTagFilter(t *testing.T) {
+	router := setupRegisteredRouter()
+
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() {
+		handlers.GetAllEvents = originalGetAllEvents
+	}()
+
+	var got models.EventFilter
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+		got = filter
+		return []models.Event{}, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/events?category=workshop&tag=Web,go&tag=cli", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "workshop", got.Category)
+	assert.Equal(t, []string{"cli", "go", "web"}, got.Tags)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/events?tag=c%2B%2B", http.NoBody))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"tag"`)
+}
+
+func TestGetTags(t *testing.T) {
+	router := setupRegisteredRouter()
+
+	originalGetTagCounts := handlers.GetTagCounts
+	defer func() {
+		handlers.GetTagCounts = originalGetTagCounts
+	}()
+
+	var got models.TagFilter
+	handlers.GetTagCounts = func(ctx context.Context, filter models.TagFilter) ([]models.TagCount, error) {
+		got = filter
+		return []models.TagCount{{Name: "go", Count: 12}}, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tags?category=workshop&tag=web", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `{"name":"go","count":12}`)
+	assert.Equal(t, models.TagFilter{Category: "workshop", Tags: []string{"web"}, Limit: defaultTagLimit}, got)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tags/autocomplete?q=g", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.TagFilter{Prefix: "g", Limit: defaultSuggestionLimit}, got)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tags/autocomplete?limit=500", http.NoBody))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"q"`)
+	assert.Contains(t, w.Body.String(), `"field":"limit"`)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"net/http"
+
+	"example.com/event/docs"
+	"github.com/gin-gonic/gin"
+)
+
+func getOpenAPI(context *gin.Context) {
+	context.Data(http.StatusOK, "application/json", docs.OpenAPI)
+}
+
+func getSwaggerUI(context *gin.Context) {
+	context.Data(http.StatusOK, "text/html; charset=utf-8", docs.SwaggerUI)
+}
@@ -0,0 +1,609 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/docs"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+// routes serving the documentation itself are not described in the spec
+var undocumentedRoutes = map[string]bool{
+	"/openapi.json":              true,
+	"/docs":                      true,
+	"/docs/swagger-ui.css":       true,
+	"/docs/swagger-ui-bundle.js": true,
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+type openAPISpec struct {
+	OpenAPI string                    `json:"openapi"`
+	Paths   map[string]map[string]any `json:"paths"`
+}
+
+func loadSpec(t *testing.T) openAPISpec {
+	var spec openAPISpec
+	err := json.Unmarshal(docs.OpenAPI, &spec)
+	assert.NoError(t, err)
+
+	return spec
+}
+
+func TestOpenAPI_DocumentsAllRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	RegisterRoutes(server)
+
+	spec := loadSpec(t)
+
+	for _, route := range server.Routes() {
+		if undocumentedRoutes[route.Path] {
+			continue
+		}
+
+		// gin uses :eventId, OpenAPI uses {eventId}
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		method := strings.ToLower(route.Method)
+
+		_, ok := spec.Paths[path][method]
+		assert.True(t, ok, "route %s %s is missing from docs/openapi.json", route.Method, path)
+	}
+}
+
+func TestOpenAPI_NoStaleOperations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	RegisterRoutes(server)
+
+	registered := map[string]bool{}
+	for _, route := range server.Routes() {
+		registered[strings.ToLower(route.Method)+" "+pathParam.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+
+	spec := loadSpec(t)
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+
+			assert.True(t, registered[method+" "+path], "docs/openapi.json describes %s %s which is not registered", method, path)
+		}
+	}
+}
+
+func TestGetOpenAPI_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	RegisterRoutes(server)
+
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", http.NoBody)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var spec openAPISpec
+	err := json.Unmarshal(w.Body.Bytes(), &spec)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3.1.0", spec.OpenAPI)
+}
+
+func TestGetSwaggerUI_ServesVendoredAssets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	RegisterRoutes(server)
+
+	req, _ := http.NewRequest(http.MethodGet, "/docs", http.NoBody)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	for _, asset := range []string{"/docs/swagger-ui.css", "/docs/swagger-ui-bundle.js"} {
+		assert.Contains(t, w.Body.String(), `"`+asset+`"`)
+
+		req, _ := http.NewRequest(http.MethodGet, asset, http.NoBody)
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, asset)
+		assert.NotEmpty(t, w.Body.Bytes(), asset)
+	}
+}
+
+// schemaDTOs maps the components of docs/openapi.json to the types the handlers bind or render,
+// so the schemas cannot drift from them unnoticed
+var schemaDTOs = []struct {
+	schema string
+	dto    any
+
+	// request bodies must mark the fields bound with binding:"required" as required, a merge patch marks none
+	request bool
+	patch   bool
+}{
+	{"EventRequest", EventRequest{}, true, false},
+	{"EventMergePatch", EventRequest{}, true, true},
+	{"EventResponse", EventResponse{}, false, false},
+	{"Address", AddressRequest{}, true, false},
+	{"Address", AddressResponse{}, false, false},
+	{"Recurrence", RecurrenceRequest{}, true, false},
+	{"Recurrence", RecurrenceResponse{}, false, false},
+	{"UserRequest", UserRequest{}, true, false},
+	{"UserResponse", UserResponse{}, false, false},
+	{"Problem", problems.Problem{}, false, false},
+	{"FieldError", models.FieldError{}, false, false},
+	{"CancelEventRequest", CancelEventRequest{}, true, false},
+	{"AuditEntry", AuditEntryResponse{}, false, false},
+	{"VenueRequest", VenueRequest{}, true, false},
+	{"VenueResponse", VenueResponse{}, false, false},
+	{"VenueConflict", models.VenueConflict{}, false, false},
+	{"TimeSlot", models.TimeSlot{}, false, false},
+	{"VenueAvailability", VenueAvailabilityResponse{}, false, false},
+	{"OccurrenceRequest", OccurrenceRequest{}, true, false},
+	{"OccurrenceResponse", OccurrenceResponse{}, false, false},
+	{"RegistrationRequest", RegistrationRequest{}, true, false},
+	{"EventRegistration", RegistrationResponse{}, false, false},
+	{"CalendarTokenResponse", CalendarTokenResponse{}, false, false},
+	{"ImportRow", ImportRowResponse{}, false, false},
+	{"ImportResponse", ImportResponse{}, false, false},
+	{"SearchResult", SearchResultResponse{}, false, false},
+	{"Pagination", PaginationResponse{}, false, false},
+	{"CategoryRequest", CategoryRequest{}, true, false},
+	{"CategoryResponse", CategoryResponse{}, false, false},
+	{"TagCount", TagCountResponse{}, false, false},
+	{"MemberRequest", MemberRequest{}, true, false},
+	{"MemberResponse", MemberResponse{}, false, false},
+	{"TransferRequest", TransferRequest{}, true, false},
+	{"OrganizationRequest", OrganizationRequest{}, true, false},
+	{"OrganizationResponse", OrganizationResponse{}, false, false},
+	{"Branding", BrandingRequest{}, true, false},
+	{"Branding", BrandingResponse{}, false, false},
+	{"OrganizationMemberRequest", OrganizationMemberRequest{}, true, false},
+	{"OrganizationMemberResponse", OrganizationMemberResponse{}, false, false},
+	{"InvitationRequest", InvitationRequest{}, true, false},
+	{"InvitationResponse", InvitationResponse{}, false, false},
+	{"InvitationLinkRequest", InvitationLinkRequest{}, true, false},
+	{"InvitationLinkResponse", InvitationLinkResponse{}, false, false},
+	{"AcceptInvitationRequest", AcceptInvitationRequest{}, true, false},
+	{"RSVPRequest", RSVPRequest{}, true, false},
+	{"CheckInRequest", CheckInRequest{}, true, false},
+	{"RSVPChange", RSVPChangeResponse{}, false, false},
+}
+
+// dtoField is a JSON field of a DTO
+type dtoField struct {
+	// jsonType is empty for raw JSON, which can be of any type
+	jsonType string
+	required bool
+
+	// optional fields are left out when empty, nullable ones are rendered as null
+	optional bool
+	nullable bool
+}
+
+// dtoFields lists the JSON fields of a struct type, embedded structs flattened like encoding/json does
+func dtoFields(t reflect.Type, request bool) map[string]dtoField {
+	fields := map[string]dtoField{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			maps.Copy(fields, dtoFields(field.Type, request))
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		binding := strings.Split(field.Tag.Get("binding"), ",")
+		optional := slices.Contains(strings.Split(options, ","), "omitempty")
+		fields[name] = dtoField{
+			jsonType: jsonType(field.Type),
+			required: request && slices.Contains(binding, "required"),
+			optional: optional,
+			nullable: !optional && field.Type.Kind() == reflect.Pointer,
+		}
+	}
+
+	return fields
+}
+
+// jsonType is the JSON Schema type encoding/json renders t as
+func jsonType(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeFor[json.RawMessage]():
+		return ""
+	case t == reflect.TypeFor[time.Time]():
+		return "string"
+	case t.Kind() == reflect.String:
+		return "string"
+	case t.Kind() == reflect.Bool:
+		return "boolean"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return "integer"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return "number"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "string"
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// objectSchema resolves $ref and allOf into the properties and required fields of an object schema
+func objectSchema(components map[string]any, schema map[string]any) (map[string]map[string]any, []string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		return objectSchema(components, components[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any))
+	}
+
+	properties := map[string]map[string]any{}
+	required := []string{}
+
+	for _, part := range asSlice(schema["allOf"]) {
+		partProperties, partRequired := objectSchema(components, part.(map[string]any))
+		maps.Copy(properties, partProperties)
+		required = append(required, partRequired...)
+	}
+
+	for name, property := range asMap(schema["properties"]) {
+		properties[name] = property.(map[string]any)
+	}
+	for _, name := range asSlice(schema["required"]) {
+		required = append(required, name.(string))
+	}
+
+	return properties, required
+}
+
+// schemaTypes are the JSON types a property schema allows, resolving $ref. Empty when it does not say.
+func schemaTypes(components map[string]any, schema map[string]any) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return schemaTypes(components, components[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any))
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := []string{}
+		for _, name := range t {
+			types = append(types, name.(string))
+		}
+		return types
+	}
+
+	// allOf of a single schema only adds a description to it
+	if parts := asSlice(schema["allOf"]); len(parts) == 1 {
+		return schemaTypes(components, parts[0].(map[string]any))
+	}
+
+	if len(asSlice(schema["allOf"])) > 0 || schema["properties"] != nil {
+		return []string{"object"}
+	}
+
+	return nil
+}
+
+func asMap(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
+
+func asSlice(value any) []any {
+	s, _ := value.([]any)
+	return s
+}
+
+func TestOpenAPI_SchemasMatchDTOs(t *testing.T) {
+	var spec map[string]any
+	err := json.Unmarshal(docs.OpenAPI, &spec)
+	assert.NoError(t, err)
+
+	components := asMap(asMap(spec["components"])["schemas"])
+
+	for _, tt := range schemaDTOs {
+		dtoType := reflect.TypeOf(tt.dto)
+		name := tt.schema + "/" + dtoType.String()
+
+		schema := asMap(components[tt.schema])
+		if !assert.NotNil(t, schema, "%s: schema is missing", name) {
+			continue
+		}
+
+		properties, required := objectSchema(components, schema)
+		fields := dtoFields(dtoType, tt.request)
+
+		for field, f := range fields {
+			property, ok := properties[field]
+			if !assert.True(t, ok, "%s: %s is not in the schema", name, field) {
+				continue
+			}
+
+			if types := schemaTypes(components, property); len(types) > 0 && f.jsonType != "" {
+				assert.Contains(t, types, f.jsonType, "%s: %s has the wrong type", name, field)
+
+				if f.nullable && !tt.request {
+					assert.Contains(t, types, "null", "%s: %s can be null", name, field)
+				}
+			}
+
+			if f.required && !tt.patch {
+				assert.Contains(t, required, field, "%s: %s is required by the binding", name, field)
+			}
+		}
+
+		for property := range properties {
+			assert.Contains(t, fields, property, "%s: %s is not a field of the type", name, property)
+		}
+
+		for _, field := range required {
+			f := fields[field]
+			if tt.request {
+				assert.True(t, f.required, "%s: %s is required by the schema but not by the binding", name, field)
+			} else {
+				assert.False(t, f.optional, "%s: %s is required by the schema but can be left out", name, field)
+			}
+		}
+	}
+}
+
+// httpSample is a request of the api-test/*.http files with the sample responses written after it
+type httpSample struct {
+	file        string
+	method      string
+	path        string
+	contentType string
+	body        string
+	responses   map[string]string
+}
+
+var (
+	httpRequestLine    = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE) https?://[^/]+(/[^?\s]*)`)
+	httpSampleResponse = regexp.MustCompile(`^### Sample \w+ Response \((\d{3})\)`)
+)
+
+// loadHTTPSamples parses the api-test/*.http files: requests are separated by ### lines, a
+// "### Sample ... Response (status)" block holds a commented out JSON body answered by the request before it
+func loadHTTPSamples(t *testing.T) []*httpSample {
+	files, err := filepath.Glob("../api-test/*.http")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	samples := []*httpSample{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		var current *httpSample
+		status := ""
+		inBody := false
+
+		for _, line := range strings.Split(string(content), "\n") {
+			if strings.HasPrefix(line, "###") {
+				status, inBody = "", false
+
+				if match := httpSampleResponse.FindStringSubmatch(line); match != nil && current != nil {
+					status = match[1]
+				}
+				continue
+			}
+
+			if status != "" {
+				if strings.HasPrefix(line, "#") {
+					current.responses[status] += strings.TrimPrefix(strings.TrimPrefix(line, "#"), " ") + "\n"
+				}
+				continue
+			}
+
+			if match := httpRequestLine.FindStringSubmatch(line); match != nil {
+				current = &httpSample{file: filepath.Base(file), method: strings.ToLower(match[1]), path: match[2], responses: map[string]string{}}
+				samples = append(samples, current)
+				continue
+			}
+
+			switch {
+			case current == nil || strings.HasPrefix(line, "#"):
+			case inBody:
+				current.body += line + "\n"
+			case strings.TrimSpace(line) == "":
+				inBody = true
+			case strings.HasPrefix(strings.ToLower(line), "content-type:"):
+				current.contentType = strings.TrimSpace(line[len("content-type:"):])
+			}
+		}
+	}
+
+	return samples
+}
+
+// specPath is the path of the spec matching a request path, literal segments win over parameters
+func specPath(paths map[string]any, path string) string {
+	best, bestParams := "", -1
+	for template := range paths {
+		templateSegments := strings.Split(template, "/")
+		segments := strings.Split(path, "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+
+		params := 0
+		for i, segment := range templateSegments {
+			if strings.HasPrefix(segment, "{") {
+				params++
+				continue
+			}
+			if segment != segments[i] {
+				params = -1
+				break
+			}
+		}
+
+		if params >= 0 && (bestParams < 0 || params < bestParams) {
+			best, bestParams = template, params
+		}
+	}
+
+	return best
+}
+
+// schemaPointer is the URI fragment of a JSON pointer into the spec
+func schemaPointer(tokens ...string) string {
+	escaped := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+		escaped = append(escaped, url.PathEscape(token))
+	}
+
+	return "openapi.json#/" + strings.Join(escaped, "/")
+}
+
+// unknownProperties lists the members of instance its schema does not describe, and in responses the date-times
+// not rendered in UTC (only local* times carry an offset). JSON Schema allows both, the API does not.
+func unknownProperties(components map[string]any, schema map[string]any, instance any, at string, response bool) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = asMap(components[strings.TrimPrefix(ref, "#/components/schemas/")])
+	}
+
+	problems := []string{}
+	switch value := instance.(type) {
+	case map[string]any:
+		// alternatives and maps (additionalProperties) are left to the schema validation
+		if schema["oneOf"] != nil || schema["anyOf"] != nil || schema["additionalProperties"] != nil {
+			return nil
+		}
+
+		properties, _ := objectSchema(components, schema)
+		if len(properties) == 0 {
+			return nil
+		}
+
+		for name, member := range value {
+			property, ok := properties[name]
+			if !ok {
+				problems = append(problems, at+"."+name+" is not in the schema")
+				continue
+			}
+
+			problems = append(problems, unknownProperties(components, property, member, at+"."+name, response)...)
+		}
+	case []any:
+		items := asMap(schema["items"])
+		for i, item := range value {
+			if items != nil {
+				problems = append(problems, unknownProperties(components, items, item, at+"["+strconv.Itoa(i)+"]", response)...)
+			}
+		}
+	case string:
+		name := at[strings.LastIndex(at, ".")+1:]
+		if response && schema["format"] == "date-time" && !strings.HasPrefix(name, "local") && !strings.HasSuffix(value, "Z") {
+			problems = append(problems, at+" is not in UTC: "+value)
+		}
+	}
+
+	return problems
+}
+
+func TestOpenAPI_HTTPSamplesMatchSchemas(t *testing.T) {
+	var spec map[string]any
+	err := json.Unmarshal(docs.OpenAPI, &spec)
+	assert.NoError(t, err)
+
+	paths := asMap(spec["paths"])
+	components := asMap(asMap(spec["components"])["schemas"])
+
+	document, err := jsonschema.UnmarshalJSON(bytes.NewReader(docs.OpenAPI))
+	assert.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	err = compiler.AddResource("openapi.json", document)
+	assert.NoError(t, err)
+
+	// validate checks body against the schema of content, given as JSON pointer tokens into the spec
+	validate := func(name, body string, response bool, content map[string]any, tokens ...string) {
+		instance, err := jsonschema.UnmarshalJSON(strings.NewReader(body))
+		if !assert.NoError(t, err, "%s: body is not JSON", name) {
+			return
+		}
+
+		schema, err := compiler.Compile(schemaPointer(tokens...))
+		if !assert.NoError(t, err, name) {
+			return
+		}
+
+		err = schema.Validate(instance)
+		assert.NoError(t, err, "%s does not match docs/openapi.json", name)
+
+		for _, problem := range unknownProperties(components, asMap(content["schema"]), instance, "body", response) {
+			assert.Fail(t, name+": "+problem)
+		}
+	}
+
+	for _, sample := range loadHTTPSamples(t) {
+		name := sample.file + ": " + strings.ToUpper(sample.method) + " " + sample.path
+
+		path := specPath(paths, sample.path)
+		operation := asMap(asMap(paths[path])[sample.method])
+		if !assert.NotNil(t, operation, "%s is not in docs/openapi.json", name) {
+			continue
+		}
+
+		if strings.Contains(sample.contentType, "json") && strings.TrimSpace(sample.body) != "" {
+			content := asMap(asMap(asMap(operation["requestBody"])["content"])[sample.contentType])
+			if assert.NotNil(t, content, "%s: %s request body is not documented", name, sample.contentType) {
+				validate(name+" request", sample.body, false, content, "paths", path, sample.method, "requestBody", "content", sample.contentType, "schema")
+			}
+		}
+
+		for status, body := range sample.responses {
+			response := asMap(asMap(operation["responses"])[status])
+			if !assert.NotNil(t, response, "%s: %s response is not documented", name, status) {
+				continue
+			}
+
+			for _, contentType := range []string{"application/json", "application/problem+json"} {
+				if content := asMap(asMap(response["content"])[contentType]); content != nil {
+					validate(name+" "+status+" response", body, true, content, "paths", path, sample.method, "responses", status, "content", contentType, "schema")
+					break
+				}
+			}
+		}
+	}
+}
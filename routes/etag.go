@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"example.com/event/models"
+	"github.com/gin-gonic/gin"
+)
+
+// eventETag is a strong validator for the current version of an event
+func eventETag(e models.Event) string {
+	return fmt.Sprintf(`"%d"`, e.Version)
+}
+
+// eventDetailETag validates the event detail, which also carries the RSVP counts. They change without
+// a new version, so a hash of them follows the version: "3-1a2b3c4d". If-Match only compares the version.
+func eventDetailETag(e models.Event, counts map[models.RSVPStatus]int) string {
+	hash := fnv.New32a()
+	for _, status := range models.RSVPStatuses {
+		fmt.Fprintf(hash, "%s=%d;", status, counts[status])
+	}
+
+	return fmt.Sprintf(`"%d-%08x"`, e.Version, hash.Sum32())
+}
+
+// versionTag drops the RSVP counts hash of an event detail ETag, leaving the version tag
+func versionTag(tag string) string {
+	if !strings.HasPrefix(tag, `"`) {
+		return tag
+	}
+
+	version, _, found := strings.Cut(tag, "-")
+	if !found {
+		return tag
+	}
+
+	return version + `"`
+}
+
+// checkIfMatch fails with ErrVersionMismatch when If-Match is sent and does not match the event.
+// A missing header passes, routes requiring it use middlewares.RequireIfMatch.
+func checkIfMatch(context *gin.Context, event models.Event) error {
+	header := context.GetHeader("If-Match")
+	if header == "" {
+		return nil
+	}
+
+	etag := eventETag(event)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		// If-Match uses strong comparison, weak tags never match
+		if tag == "*" || versionTag(tag) == etag {
+			return nil
+		}
+	}
+
+	return models.ErrVersionMismatch
+}
+
+// notModified reports whether If-None-Match already contains etag
+func notModified(context *gin.Context, etag string) bool {
+	header := context.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		// If-None-Match uses weak comparison
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,246 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks event lookup with an event at version 3 owned by user 1
+func mockVersionedEvent(t *testing.T) {
+	originalVerifyToken := utils.VerifyToken
+	originalGetEventByID := handlers.GetEventByID
+	originalUpdateEvent := handlers.UpdateEvent
+	originalDeleteEvent := handlers.DeleteEvent
+	t.Cleanup(func() {
+		utils.VerifyToken = originalVerifyToken
+		handlers.GetEventByID = originalGetEventByID
+		handlers.UpdateEvent = originalUpdateEvent
+		handlers.DeleteEvent = originalDeleteEvent
+	})
+
+	utils.VerifyToken = func(token string) (int64, error) {
+		return 1, nil
+	}
+
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &models.Event{
+				ID:          1,
+				Name:        "Go Workshop Jakarta",
+				Description: "A beginner-friendly workshop covering Go fundamentals and best practices.",
+				Location:    "Jakarta",
+				DateTime:    time.Date(2030, 12, 16, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+				UserID:      1,
+				Version:     3,
+			},
+			nil
+	}
+
+	handlers.UpdateEvent = func(ctx context.Context, event *models.Event) error {
+		event.Version++
+		return nil
+	}
+
+	handlers.DeleteEvent = func(ctx context.Context, event *models.Event) error {
+		return nil
+	}
+
+	mockRSVPCounts(t)
+}
+
+// getEventDetail sends GET /events/1 with the given If-None-Match
+func getEventDetail(ifNoneMatch string) *httptest.ResponseRecorder {
+	router := setupRouter()
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		strings.Replace(GET_EVENTS_BY_ID_PATH, ":eventId", "1", 1),
+		http.NoBody,
+	)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestGetEventById_ETag(t *testing.T) {
+	mockVersionedEvent(t)
+
+	w := getEventDetail("")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Regexp(t, `^"3-[0-9a-f]{8}"$`, w.Header().Get("ETag"))
+}
+
+func TestGetEventById_NotModified(t *testing.T) {
+	mockVersionedEvent(t)
+
+	etag := getEventDetail("").Header().Get("ETag")
+
+	w := getEventDetail("W/" + etag)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// the version alone does not cover the RSVP counts
+	w = getEventDetail(`"3"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetEventById_ModifiedByRSVP(t *testing.T) {
+	mockVersionedEvent(t)
+
+	etag := getEventDetail("").Header().Get("ETag")
+
+	// an RSVP changes the counts but not the version
+	handlers.GetRSVPCounts = func(ctx context.Context, event *models.Event) (map[models.RSVPStatus]int, error) {
+		return map[models.RSVPStatus]int{models.RSVPGoing: 2}, nil
+	}
+
+	w := getEventDetail(etag)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), `"going":2`)
+}
+
+func TestUpdateEvent_IfMatchEventDetailETag(t *testing.T) {
+	mockVersionedEvent(t)
+
+	// the ETag of a GET can be sent back as If-Match, only its version is compared
+	etag := getEventDetail("").Header().Get("ETag")
+
+	router := setupRouter()
+	req, _ := http.NewRequest(
+		http.MethodPut,
+		strings.Replace(UPDATE_EVENT_PATH, ":eventId", "1", 1),
+		toJSON(t, updateEventPayload),
+	)
+	req.Header.Set("Authorization", "test-token")
+	req.Header.Set("If-Match", etag)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func TestUpdateEvent_IfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		etag    string
+	}{
+		{"matching version", `"3"`, http.StatusOK, `"4"`},
+		{"any version", `*`, http.StatusOK, `"4"`},
+		{"stale version", `"2"`, http.StatusPreconditionFailed, ""},
+		{"weak tag never matches", `W/"3"`, http.StatusPreconditionFailed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVersionedEvent(t)
+			router := setupRouter()
+
+			req, _ := http.NewRequest(
+				http.MethodPut,
+				strings.Replace(UPDATE_EVENT_PATH, ":eventId", "1", 1),
+				toJSON(t, updateEventPayload),
+			)
+			req.Header.Set("Authorization", "test-token")
+			req.Header.Set("If-Match", tt.ifMatch)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.etag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestDeleteEvent_ErrorStaleIfMatch(t *testing.T) {
+	mockVersionedEvent(t)
+	router := setupRouter()
+
+	req, _ := http.NewRequest(
+		http.MethodDelete,
+		strings.Replace(DELETE_EVENT_PATH, ":eventId", "1", 1),
+		http.NoBody,
+	)
+	req.Header.Set("Authorization", "test-token")
+	req.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "precondition_failed", response["code"])
+}
+
+func TestRegisterRoutes_V1WritesRequireIfMatch(t *testing.T) {
+	mockVersionedEvent(t)
+	router := setupRegisteredRouter()
+
+	for _, method := range []string{http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, _ := http.NewRequest(method, "/api/v1/events/1", toJSON(t, updateEventPayload))
+		req.Header.Set("Authorization", "test-token")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code, method)
+	}
+}
+
+func TestRegisterRoutes_LegacyWritesRequireIfMatch(t *testing.T) {
+	mockVersionedEvent(t)
+	router := setupRegisteredRouter()
+
+	for _, method := range []string{http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, _ := http.NewRequest(method, "/event/1", toJSON(t, updateEventPayload))
+		req.Header.Set("Authorization", "test-token")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code, method)
+	}
+}
+
+func TestRegisterRoutes_LegacyWritesErrorStaleIfMatch(t *testing.T) {
+	mockVersionedEvent(t)
+	router := setupRegisteredRouter()
+
+	for _, method := range []string{http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, _ := http.NewRequest(method, "/event/1", toJSON(t, updateEventPayload))
+		req.Header.Set("Authorization", "test-token")
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", `"2"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code, method)
+	}
+}
@@ -1,63 +1,525 @@
 package routes
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"example.com/event/handlers"
 	"example.com/event/models"
+	"example.com/event/problems"
 	"github.com/gin-gonic/gin"
 )
 
+// Radius of ?near= listings, in kilometres
+const (
+	defaultRadiusKm = 25
+	maxRadiusKm     = 500
+)
+
+// EventRequest is the body accepted when creating or updating an event,
+// id and owner are never taken from the client
+type EventRequest struct {
+	Name        string    `json:"name" binding:"required,notblank"`
+	Description string    `json:"description" binding:"required,notblank,max=2000"`
+	Location    string    `json:"location" binding:"omitempty,notblank,max=200"`
+	DateTime    time.Time `json:"dateTime" binding:"required"`
+
+	// VenueID places the event at a venue, location, address, coordinates and capacity default to the venue's.
+	// Location is required without it.
+	VenueID *int64 `json:"venueId,omitempty" binding:"omitempty,min=1"`
+
+	// Capacity caps registrations (per occurrence), unlimited when omitted and the venue has none
+	Capacity *int `json:"capacity,omitempty" binding:"omitempty,min=1,max=1000000"`
+
+	// The end is either given or derived from a duration, the event lasts an hour when neither is sent
+	EndDateTime     *time.Time `json:"endDateTime,omitempty"`
+	DurationMinutes int        `json:"durationMinutes,omitempty" binding:"omitempty,min=1"`
+
+	// TimeZone is an IANA name, local times are rendered in it (UTC when omitted)
+	TimeZone string `json:"timeZone,omitempty" binding:"omitempty,max=64"`
+
+	// Recurrence repeats the event, dateTime is its first occurrence
+	Recurrence *RecurrenceRequest `json:"recurrence,omitempty"`
+
+	// Coordinates put the event on the map for "near me" listings, both or neither must be sent
+	Latitude  *float64 `json:"latitude,omitempty" binding:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude,omitempty" binding:"omitempty,longitude"`
+
+	Address *AddressRequest `json:"address,omitempty"`
+
+	// Category is the slug of an existing category, Tags are free-form and created on first use
+	Category string   `json:"category,omitempty" binding:"omitempty,slug"`
+	Tags     []string `json:"tags,omitempty" binding:"max=10,dive,tag"`
+
+	// Visibility is public when creating without it, and left as it is when updating without it
+	Visibility models.EventVisibility `json:"visibility,omitempty" binding:"omitempty,oneof=public unlisted private"`
+}
+
+// AddressRequest is the structured form of location, every part is optional
+type AddressRequest struct {
+	Street     string `json:"street,omitempty" binding:"max=200"`
+	City       string `json:"city,omitempty" binding:"max=100"`
+	Region     string `json:"region,omitempty" binding:"max=100"`
+	PostalCode string `json:"postalCode,omitempty" binding:"max=20"`
+	Country    string `json:"country,omitempty" binding:"omitempty,iso3166_1_alpha2"`
+}
+
+type AddressResponse struct {
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+// RecurrenceRequest is an RFC 5545 RRULE value plus the occurrences (EXDATE) left out
+type RecurrenceRequest struct {
+	Rule    string      `json:"rule" binding:"required,max=500"`
+	ExDates []time.Time `json:"exDates,omitempty" binding:"max=366"`
+}
+
+type RecurrenceResponse struct {
+	Rule    string      `json:"rule"`
+	ExDates []time.Time `json:"exDates,omitempty"`
+}
+
+// EventResponse renders times in UTC, and again in the event's time zone as local times
+type EventResponse struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	DateTime    time.Time `json:"dateTime"`
+	UserID      int64     `json:"userId"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	EndDateTime      time.Time `json:"endDateTime"`
+	TimeZone         string    `json:"timeZone"`
+	LocalDateTime    time.Time `json:"localDateTime"`
+	LocalEndDateTime time.Time `json:"localEndDateTime"`
+
+	Status             models.EventStatus `json:"status"`
+	CancellationReason string             `json:"cancellationReason,omitempty"`
+
+	Visibility models.EventVisibility `json:"visibility"`
+
+	Recurrence *RecurrenceResponse `json:"recurrence,omitempty"`
+
+	// OccurrenceStart identifies an occurrence of a recurring event, dateTime may differ when it was moved
+	OccurrenceStart *time.Time `json:"occurrenceStart,omitempty"`
+
+	// ExternalUID is set on imported events, re-importing it updates the event
+	ExternalUID string `json:"externalUid,omitempty"`
+
+	Latitude  *float64         `json:"latitude,omitempty"`
+	Longitude *float64         `json:"longitude,omitempty"`
+	Address   *AddressResponse `json:"address,omitempty"`
+
+	VenueID  *int64 `json:"venueId,omitempty"`
+	Capacity *int   `json:"capacity,omitempty"`
+
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+
+	OrganizationID *int64 `json:"organizationId,omitempty"`
+
+	// RSVPCounts is only set on the event details, registrations by status. Its ETag covers them (eventDetailETag).
+	RSVPCounts map[models.RSVPStatus]int `json:"rsvpCounts,omitempty"`
+
+	// DistanceKm is only set when listing events near a point
+	DistanceKm *float64 `json:"distanceKm,omitempty"`
+}
+
+// toEvent copies the request into a model with surrounding whitespace trimmed
+func (r EventRequest) toEvent() models.Event {
+	event := models.Event{
+		Name:        strings.TrimSpace(r.Name),
+		Description: strings.TrimSpace(r.Description),
+		Location:    strings.TrimSpace(r.Location),
+		DateTime:    r.DateTime.UTC(),
+		TimeZone:    r.TimeZone,
+		Recurrence:  r.Recurrence.toRecurrence(),
+		Latitude:    r.Latitude,
+		Longitude:   r.Longitude,
+		Address:     r.Address.toAddress(),
+		VenueID:     r.VenueID,
+		Capacity:    r.Capacity,
+		Category:    r.Category,
+		Tags:        models.NormalizeTags(r.Tags),
+		Visibility:  r.Visibility,
+	}
+
+	switch {
+	case r.EndDateTime != nil:
+		event.EndDateTime = r.EndDateTime.UTC()
+	case r.DurationMinutes > 0:
+		event.EndDateTime = event.DateTime.Add(time.Duration(r.DurationMinutes) * time.Minute)
+	default:
+		event.EndDateTime = event.End()
+	}
+
+	event.TimeZone = event.TimeZoneName()
+
+	return event
+}
+
+func (r *RecurrenceRequest) toRecurrence() *models.Recurrence {
+	if r == nil {
+		return nil
+	}
+
+	return &models.Recurrence{
+		Rule:    strings.TrimSpace(r.Rule),
+		ExDates: r.ExDates,
+	}
+}
+
+// toAddress trims every part, a missing address is an empty one
+func (r *AddressRequest) toAddress() models.Address {
+	if r == nil {
+		return models.Address{}
+	}
+
+	return models.Address{
+		Street:     strings.TrimSpace(r.Street),
+		City:       strings.TrimSpace(r.City),
+		Region:     strings.TrimSpace(r.Region),
+		PostalCode: strings.TrimSpace(r.PostalCode),
+		Country:    r.Country,
+	}
+}
+
+func newEventRequest(e models.Event) EventRequest {
+	end := e.End()
+
+	request := EventRequest{
+		Name:        e.Name,
+		Description: e.Description,
+		Location:    e.Location,
+		DateTime:    e.DateTime,
+		EndDateTime: &end,
+		TimeZone:    e.TimeZoneName(),
+		Latitude:    e.Latitude,
+		Longitude:   e.Longitude,
+		VenueID:     e.VenueID,
+		Capacity:    e.Capacity,
+		Category:    e.Category,
+		Tags:        e.Tags,
+		Visibility:  e.VisibilityName(),
+	}
+
+	if !e.Address.IsZero() {
+		request.Address = &AddressRequest{
+			Street:     e.Address.Street,
+			City:       e.Address.City,
+			Region:     e.Address.Region,
+			PostalCode: e.Address.PostalCode,
+			Country:    e.Address.Country,
+		}
+	}
+
+	if e.Recurrence != nil {
+		request.Recurrence = &RecurrenceRequest{
+			Rule:    e.Recurrence.Rule,
+			ExDates: e.Recurrence.ExDates,
+		}
+	}
+
+	return request
+}
+
+func newEventResponse(e models.Event) EventResponse {
+	zone := e.Zone()
+
+	return EventResponse{
+		ID:          e.ID,
+		Name:        e.Name,
+		Description: e.Description,
+		Location:    e.Location,
+		DateTime:    e.DateTime.UTC(),
+		UserID:      e.UserID,
+		UpdatedAt:   e.UpdatedAt,
+
+		EndDateTime:      e.End().UTC(),
+		TimeZone:         e.TimeZoneName(),
+		LocalDateTime:    e.DateTime.In(zone),
+		LocalEndDateTime: e.End().In(zone),
+
+		Status:             e.Status,
+		CancellationReason: e.CancellationReason,
+
+		Visibility: e.VisibilityName(),
+
+		Recurrence:      newRecurrenceResponse(e.Recurrence),
+		OccurrenceStart: e.OccurrenceStart,
+		ExternalUID:     e.ExternalUID,
+
+		Latitude:   e.Latitude,
+		Longitude:  e.Longitude,
+		Address:    newAddressResponse(e.Address),
+		VenueID:    e.VenueID,
+		Capacity:   e.Capacity,
+		Category:   e.Category,
+		Tags:       e.Tags,
+		DistanceKm: roundDistance(e.DistanceKm),
+
+		OrganizationID: e.OrganizationID,
+	}
+}
+
+func newAddressResponse(a models.Address) *AddressResponse {
+	if a.IsZero() {
+		return nil
+	}
+
+	return &AddressResponse{
+		Street:     a.Street,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+// roundDistance keeps distances to the nearest 10 m
+func roundDistance(km *float64) *float64 {
+	if km == nil {
+		return nil
+	}
+
+	rounded := math.Round(*km*100) / 100
+
+	return &rounded
+}
+
+func newRecurrenceResponse(r *models.Recurrence) *RecurrenceResponse {
+	if r == nil {
+		return nil
+	}
+
+	return &RecurrenceResponse{
+		Rule:    r.Rule,
+		ExDates: r.ExDates,
+	}
+}
+
+func newEventResponses(events []models.Event) []EventResponse {
+	responses := make([]EventResponse, 0, len(events))
+	for _, e := range events {
+		responses = append(responses, newEventResponse(e))
+	}
+
+	return responses
+}
+
+// bindEventRequest binds and validates the body, collecting every invalid field into one error.
+// New events must be scheduled in the future.
+func bindEventRequest(context *gin.Context, isNew bool) (EventRequest, error) {
+	var request EventRequest
+
+	err := context.ShouldBindJSON(&request)
+
+	// Events of an organization are scheduled in its time zone unless they name one
+	if request.TimeZone == "" {
+		request.TimeZone = organizationTimeZone(context)
+	}
+
+	return request, checkEventRequest(request, err, isNew)
+}
+
+// checkEventRequest merges the binding/validator error with rules the validator cannot express,
+// so every invalid field is reported at once. requireFuture enforces dateTime in the future.
+func checkEventRequest(request EventRequest, bindErr error, requireFuture bool) error {
+	validationErr := &models.ValidationError{}
+
+	if bindErr != nil {
+		err := problems.BindError(bindErr)
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+	}
+
+	// The name is saved trimmed, so its length is checked trimmed (notblank already reports a blank one)
+	if name := strings.TrimSpace(request.Name); name != "" {
+		length := utf8.RuneCountInString(name)
+		if length < 3 {
+			validationErr.Add("name", "must be at least 3 characters")
+		} else if length > 100 {
+			validationErr.Add("name", "must be at most 100 characters")
+		}
+	}
+
+	if request.Location == "" && request.VenueID == nil {
+		validationErr.Add("location", "is required unless venueId is given")
+	}
+
+	if requireFuture && !request.DateTime.IsZero() && !request.DateTime.After(time.Now()) {
+		validationErr.Add("dateTime", "must be in the future")
+	}
+
+	if request.EndDateTime != nil && request.DurationMinutes > 0 {
+		validationErr.Add("durationMinutes", "cannot be combined with endDateTime")
+	}
+
+	if request.EndDateTime != nil && !request.DateTime.IsZero() && !request.EndDateTime.After(request.DateTime) {
+		validationErr.Add("endDateTime", "must be after dateTime")
+	}
+
+	if (request.Latitude == nil) != (request.Longitude == nil) {
+		if request.Latitude == nil {
+			validationErr.Add("latitude", "is required with longitude")
+		} else {
+			validationErr.Add("longitude", "is required with latitude")
+		}
+	}
+
+	if request.TimeZone != "" {
+		_, err := models.LoadTimeZone(request.TimeZone)
+		if err != nil {
+			validationErr.Add("timeZone", "must be an IANA time zone name such as Asia/Jakarta")
+		}
+	}
+
+	if request.Recurrence != nil && request.Recurrence.Rule != "" {
+		err := models.ValidateRecurrenceRule(strings.TrimSpace(request.Recurrence.Rule))
+		if err != nil {
+			validationErr.Add("recurrence.rule", err.Error())
+		}
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return validationErr
+	}
+
+	return nil
+}
+
 func getEvents(context *gin.Context) {
-	events, err := handlers.GetAllEvents()
+	statuses, err := parseStatusFilter(context)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Could not retrieve events",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
+		return
+	}
+
+	from, to, err := parseOccurrenceWindow(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	near, radiusKm, err := parseNearFilter(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	category, tags, err := parseTagFilter(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// userId is only set when the caller sent a token, drafts are listed for their owner only
+	filter := models.EventFilter{
+		Statuses: statuses,
+		ViewerID: context.GetInt64("userId"),
+		From:     from,
+		To:       to,
+		Near:     near,
+		RadiusKm: radiusKm,
+		Category: category,
+		Tags:     tags,
+	}
+
+	events, err := handlers.GetAllEvents(context.Request.Context(), filter)
+	if err != nil {
+		problems.Abort(context, err)
 		return
 	}
 
 	context.JSON(http.StatusOK, gin.H{
 		"message": "List of events",
-		"data":    events,
+		"data":    newEventResponses(events),
 	})
 }
 
 func getEventById(context *gin.Context) {
-	eventId, err := strconv.ParseInt(context.Param("eventId"), 10, 64)
+	eventId, err := parseEventId(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	event, err := findEvent(context, eventId)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Could not parse event id",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	event, err := handlers.GetEventByID(eventId)
+	counts, err := handlers.GetRSVPCounts(context.Request.Context(), event)
 	if err != nil {
-		context.JSON(http.StatusNotFound, gin.H{
-			"message": "Event not found",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
+
+	etag := eventDetailETag(*event, counts)
+	context.Header("ETag", etag)
+
+	// Client already has this version, with the same counts
+	if notModified(context, etag) {
+		context.Status(http.StatusNotModified)
+		return
+	}
+
+	response := newEventResponse(*event)
+	response.RSVPCounts = counts
+
 	context.JSON(http.StatusOK, gin.H{
 		"message": "Successfully get event details",
-		"data":    event,
+		"data":    response,
 	})
 }
 
-func createEvent(context *gin.Context) {
-	var newEvent models.Event
-	err := context.ShouldBindJSON(&newEvent)
+// createEvent saves new events as drafts, the owner publishes them once ready
+var createEvent = createEventAs(models.EventStatusDraft)
+
+// createEventAs returns the create handler saving new events with the given status
+func createEventAs(status models.EventStatus) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		saveNewEvent(context, status)
+	}
+}
+
+func saveNewEvent(context *gin.Context, status models.EventStatus) {
+	err := requireOrganizationMember(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	request, err := bindEventRequest(context, true)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	newEvent := request.toEvent()
+	newEvent.Status = status
 
+	err = applyVenue(context, &newEvent)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Could not parse request",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
+		return
+	}
+
+	err = applyCategory(context, &newEvent)
+	if err != nil {
+		problems.Abort(context, err)
 		return
 	}
 
@@ -66,116 +528,137 @@ func createEvent(context *gin.Context) {
 
 	newEvent.UserID = userId
 
-	err = handlers.CreateEvent(&newEvent)
+	// The venue is checked when the event is written
+	err = allowVenueConflicts(context)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Could not save event",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
+	err = handlers.CreateEvent(context.Request.Context(), &newEvent)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.Header("ETag", eventETag(newEvent))
 	context.JSON(http.StatusCreated, gin.H{
 		"message": "Event created successfully",
-		"data":    newEvent,
+		"data":    newEventResponse(newEvent),
 	})
 }
 
 func updateEvent(context *gin.Context) {
-	eventId, err := strconv.ParseInt(context.Param("eventId"), 10, 64)
+	eventId, err := parseEventId(context)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid event ID",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	var updatedEvent models.Event
-	err = context.ShouldBindJSON(&updatedEvent)
+	// Check if the event exists
+	event, err := findEvent(context, eventId)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Could not parse request",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	// Check if the event exists
-	event, err := handlers.GetEventByID(eventId)
+	// Event can be updated by its owner and co-organizers
+	err = requireEventPermission(context, event, models.PermissionEdit)
 	if err != nil {
-		context.JSON(http.StatusNotFound, gin.H{
-			"message": "Event not found",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	// Event only can be updated by event creator
-	userId := context.GetInt64("userId")
-	if event.UserID != userId {
-		context.JSON(http.StatusUnauthorized, gin.H{
-			"message": "Not authorized to update event",
-			"error":   "not authorized to update event",
-		})
+	// Reject if the client edited an older version
+	err = checkIfMatch(context, *event)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	request, err := bindEventRequest(context, false)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	updatedEvent := request.toEvent()
+
+	err = applyVenue(context, &updatedEvent)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = applyCategory(context, &updatedEvent)
+	if err != nil {
+		problems.Abort(context, err)
 		return
 	}
 
 	updatedEvent.ID = eventId
-	updatedEvent.UserID = userId
+	updatedEvent.UserID = event.UserID
+	updatedEvent.Version = event.Version
+
+	// Fields a PUT does not edit are returned as stored
+	keepStoredEventFields(&updatedEvent, *event)
 
-	err = handlers.UpdateEvent(&updatedEvent)
+	// Leaving visibility out never opens up a private event
+	if updatedEvent.Visibility == "" {
+		updatedEvent.Visibility = event.VisibilityName()
+	}
+
+	// The venue is checked when the event is written
+	err = allowVenueConflicts(context)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Could not update event",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
+	err = handlers.UpdateEvent(context.Request.Context(), &updatedEvent)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.Header("ETag", eventETag(updatedEvent))
 	context.JSON(http.StatusOK, gin.H{
 		"message": "Event updated successfully",
-		"data":    updatedEvent,
+		"data":    newEventResponse(updatedEvent),
 	})
 }
 
 func deleteEvent(context *gin.Context) {
-	eventId, err := strconv.ParseInt(context.Param("eventId"), 10, 64)
+	eventId, err := parseEventId(context)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid event ID",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
 	// Check if the event exists
-	event, err := handlers.GetEventByID(eventId)
+	event, err := findEvent(context, eventId)
 	if err != nil {
-		context.JSON(http.StatusNotFound, gin.H{
-			"message": "Event not found",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	userId := context.GetInt64("userId")
+	// Event only can be deleted by its owner
+	err = requireEventPermission(context, event, models.PermissionDelete)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
 
-	// Event only can be deleted by event creator
-	if event.UserID != userId {
-		context.JSON(http.StatusUnauthorized, gin.H{
-			"message": "Not authorized to delete event",
-			"error":   "not authorized to delete event",
-		})
+	// Reject if the client saw an older version
+	err = checkIfMatch(context, *event)
+	if err != nil {
+		problems.Abort(context, err)
 		return
 	}
 
-	err = handlers.DeleteEvent(event)
+	err = handlers.DeleteEvent(context.Request.Context(), event)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Could not delete event",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
@@ -183,3 +666,221 @@ func deleteEvent(context *gin.Context) {
 		"message": "Event deleted successfully",
 	})
 }
+
+// findEvent loads an event, a draft is reported as not found to anyone but its owner and members
+// so its existence does not leak, and so is a private event to users not invited to it.
+func findEvent(context *gin.Context, eventId int64) (*models.Event, error) {
+	event, err := findEventToJoin(context, eventId)
+	if errors.Is(err, models.ErrNotInvited) {
+		return nil, models.ErrEventNotFound
+	}
+
+	return event, err
+}
+
+// findEventToJoin is findEvent for registering and answering, where a signed-in user not invited to
+// a private event gets ErrNotInvited instead. They reached it through a link, the event is not secret
+// to them, and they need to know to ask for an invitation.
+func findEventToJoin(context *gin.Context, eventId int64) (*models.Event, error) {
+	event, err := handlers.GetEventByID(context.Request.Context(), eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status != models.EventStatusDraft && event.VisibilityName() != models.VisibilityPrivate {
+		return event, nil
+	}
+
+	role, err := eventRole(context, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != "" {
+		return event, nil
+	}
+
+	// Drafts stay hidden from everyone else, private events are shown to the users invited to them
+	if event.Status == models.EventStatusDraft {
+		return nil, models.ErrEventNotFound
+	}
+
+	err = requireInvitation(context, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// parseStatusFilter reads ?status=published,cancelled, nil means the default statuses
+func parseStatusFilter(context *gin.Context) ([]models.EventStatus, error) {
+	value := context.Query("status")
+	if value == "" {
+		return nil, nil
+	}
+
+	var statuses []models.EventStatus
+	for _, part := range strings.Split(value, ",") {
+		status := models.EventStatus(strings.TrimSpace(part))
+		if !status.Valid() {
+			return nil, models.NewValidationError("status", "must be a comma-separated list of draft, published, cancelled, completed")
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// parseOccurrenceWindow reads ?from=&to=, both or neither must be given, or ?date= for a single day.
+// Each is an RFC 3339 date-time or a calendar date (2006-01-02) taken in the ?tz= zone (UTC by default);
+// a date as to includes that whole day.
+func parseOccurrenceWindow(context *gin.Context) (time.Time, time.Time, error) {
+	fromValue, toValue, dateValue := context.Query("from"), context.Query("to"), context.Query("date")
+	if fromValue == "" && toValue == "" && dateValue == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+
+	zone := time.UTC
+	if name := context.Query("tz"); name != "" {
+		var err error
+		zone, err = models.LoadTimeZone(name)
+		if err != nil {
+			return time.Time{}, time.Time{}, models.NewValidationError("tz", "must be an IANA time zone name such as Asia/Jakarta")
+		}
+	}
+
+	if dateValue != "" {
+		if fromValue != "" || toValue != "" {
+			return time.Time{}, time.Time{}, models.NewValidationError("date", "cannot be combined with from and to")
+		}
+
+		day, err := time.ParseInLocation(time.DateOnly, dateValue, zone)
+		if err != nil {
+			return time.Time{}, time.Time{}, models.NewValidationError("date", "must be a date such as 2006-01-02")
+		}
+
+		// AddDate keeps local midnight, so days with a DST change are 23 or 25 hours long
+		return day, day.AddDate(0, 0, 1), nil
+	}
+
+	validationErr := &models.ValidationError{}
+
+	from, _, err := parseWindowBound(fromValue, zone)
+	if err != nil {
+		validationErr.Add("from", "must be an RFC 3339 date-time or a date")
+	}
+
+	to, isDate, err := parseWindowBound(toValue, zone)
+	if err != nil {
+		validationErr.Add("to", "must be an RFC 3339 date-time or a date")
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return time.Time{}, time.Time{}, validationErr
+	}
+
+	if isDate {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, models.NewValidationError("to", "must be after from")
+	}
+
+	if to.Sub(from) > models.MaxOccurrenceWindow {
+		return time.Time{}, time.Time{}, models.NewValidationError("to", "must be at most 366 days after from")
+	}
+
+	return from, to, nil
+}
+
+// parseNearFilter reads ?near=lat,lng&radius_km=, the radius defaults to defaultRadiusKm
+func parseNearFilter(context *gin.Context) (*models.GeoPoint, float64, error) {
+	nearValue, radiusValue := context.Query("near"), context.Query("radius_km")
+	if nearValue == "" {
+		if radiusValue != "" {
+			return nil, 0, models.NewValidationError("radius_km", "requires near")
+		}
+
+		return nil, 0, nil
+	}
+
+	validationErr := &models.ValidationError{}
+
+	var near *models.GeoPoint
+	lat, lng, ok := strings.Cut(nearValue, ",")
+	latitude, latErr := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	longitude, lngErr := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if !ok || latErr != nil || lngErr != nil || math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+		validationErr.Add("near", "must be latitude,longitude in degrees such as -6.2,106.8")
+	} else {
+		near = &models.GeoPoint{Latitude: latitude, Longitude: longitude}
+	}
+
+	radiusKm := float64(defaultRadiusKm)
+	if radiusValue != "" {
+		var err error
+		radiusKm, err = strconv.ParseFloat(radiusValue, 64)
+		if err != nil || !(radiusKm > 0 && radiusKm <= maxRadiusKm) {
+			validationErr.Add("radius_km", "must be a number of kilometres greater than 0 and at most "+strconv.Itoa(maxRadiusKm))
+		}
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return nil, 0, validationErr
+	}
+
+	return near, radiusKm, nil
+}
+
+// parseWindowBound parses an RFC 3339 date-time, or a date starting at midnight in zone
+func parseWindowBound(value string, zone *time.Location) (time.Time, bool, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, false, nil
+	}
+
+	t, err = time.ParseInLocation(time.DateOnly, value, zone)
+
+	return t, true, err
+}
+
+// parseEventId reads the :eventId path parameter
+func parseEventId(context *gin.Context) (int64, error) {
+	eventId, err := strconv.ParseInt(context.Param("eventId"), 10, 64)
+	if err != nil || eventId <= 0 {
+		return 0, models.NewValidationError("eventId", "must be a positive integer")
+	}
+
+	return eventId, nil
+}
+
+// parseTagFilter reads ?category=workshop and ?tag=go, tags repeated or comma-separated must all match
+func parseTagFilter(context *gin.Context) (string, []string, error) {
+	validationErr := &models.ValidationError{}
+
+	category := context.Query("category")
+	if category != "" && !models.ValidCategorySlug(category) {
+		validationErr.Add("category", "must be a category slug such as workshop")
+	}
+
+	var tags []string
+	for _, value := range context.QueryArray("tag") {
+		for _, tag := range strings.Split(value, ",") {
+			if !models.ValidTag(tag) {
+				validationErr.Add("tag", "must be tags of letters, digits and hyphens such as go or machine-learning")
+				break
+			}
+
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return "", nil, validationErr
+	}
+
+	return category, models.NormalizeTags(tags), nil
+}
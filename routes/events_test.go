@@ -2,6 +2,7 @@ package routes
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -11,23 +12,28 @@ import (
 	"time"
 
 	"example.com/event/handlers"
+	"example.com/event/middlewares"
 	"example.com/event/models"
 	"example.com/event/utils"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var createEventPayload = map[string]string{
 	"name":        "Go Workshop Jakarta",
 	"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
 	"location":    "Jakarta",
-	"dateTime":    "2025-12-16T09:00:00+07:00",
+	"dateTime":    "2030-12-16T09:00:00+07:00",
 }
 
 var updateEventPayload = map[string]string{
 	"name":        "Go Workshop Bandung",
 	"description": "A beginner-friendly workshop covering Go fundamentals.",
 	"location":    "Bandung",
-	"dateTime":    "2025-12-26T09:00:00+07:00",
+	"dateTime":    "2030-12-26T09:00:00+07:00",
 }
 
 func TestGetEvents_Success(t *testing.T) {
@@ -42,7 +48,7 @@ func TestGetEvents_Success(t *testing.T) {
 	}()
 
 	// mock handlers.GetAllEvents
-	handlers.GetAllEvents = func() ([]models.Event, error) {
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
 		return []models.Event{
 				{
 					ID:          1,
@@ -81,6 +87,41 @@ func TestGetEvents_Success(t *testing.T) {
 
 }
 
+func TestGetEvents_PropagatesTraceContext(t *testing.T) {
+	router := setupRouter()
+
+	// keep original propagator and handler to restore global state after test
+	originalPropagator := otel.GetTextMapPropagator()
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() {
+		otel.SetTextMapPropagator(originalPropagator)
+		handlers.GetAllEvents = originalGetAllEvents
+	}()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	// capture trace id seen by the handler
+	var handlerTraceId string
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+		handlerTraceId = trace.SpanContextFromContext(ctx).TraceID().String()
+		return []models.Event{}, nil
+	}
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		GET_EVENTS_PATH,
+		http.NoBody,
+	)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// incoming W3C trace context reaches the handlers layer
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", handlerTraceId)
+}
+
 func TestGetEvents_ErrorGetAllEvents(t *testing.T) {
 	router := setupRouter()
 
@@ -88,7 +129,7 @@ func TestGetEvents_ErrorGetAllEvents(t *testing.T) {
 	defer func() {
 		handlers.GetAllEvents = originalGetAllEvents
 	}()
-	handlers.GetAllEvents = func() ([]models.Event, error) {
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
 		return []models.Event{{}}, errors.New("simulate error get all events")
 	}
 
@@ -107,13 +148,54 @@ func TestGetEvents_ErrorGetAllEvents(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not retrieve events", response["message"])
-	assert.NotEmpty(t, response["error"])
+	assert.Equal(t, "internal_error", response["code"])
+	assert.Equal(t, "An unexpected error occurred", response["detail"])
 
 }
 
+func TestGetEvents_ErrorRequestTimeout(t *testing.T) {
+	// router with a very short request deadline
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares.RequestID, middlewares.Timeout(10*time.Millisecond))
+	router.GET(GET_EVENTS_PATH, getEvents)
+
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() {
+		handlers.GetAllEvents = originalGetAllEvents
+	}()
+
+	// simulate slow query that respects cancellation
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return []models.Event{}, nil
+		}
+	}
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		GET_EVENTS_PATH,
+		http.NoBody,
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "request_timeout", response["code"])
+}
+
 func TestGetEventById_Success(t *testing.T) {
 	router := setupRouter()
+	mockRSVPCounts(t)
 
 	// keep original handler to restore global state after test
 	originalGetEventByID := handlers.GetEventByID
@@ -124,7 +206,7 @@ func TestGetEventById_Success(t *testing.T) {
 	}()
 
 	// mock handlers.GetEventByID
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{
 				ID:          1,
 				Name:        "Go Workshop Jakarta",
@@ -179,8 +261,8 @@ func TestGetEventById_ErrorParseEventId(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not parse event id", response["message"])
-	assert.NotEmpty(t, response["error"])
+	assert.Equal(t, "validation_failed", response["code"])
+	assert.NotEmpty(t, response["errors"])
 
 }
 
@@ -191,9 +273,9 @@ func TestGetEventById_ErrorGetEventById(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{},
-			errors.New("simulate error get event by id")
+			models.ErrEventNotFound
 	}
 
 	req, _ := http.NewRequest(
@@ -211,8 +293,8 @@ func TestGetEventById_ErrorGetEventById(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Event not found", response["message"])
-	assert.NotEmpty(t, response["error"])
+	assert.Equal(t, "event_not_found", response["code"])
+	assert.Equal(t, "Event not found", response["title"])
 }
 
 func TestCreateEvent_Success(t *testing.T) {
@@ -229,7 +311,7 @@ func TestCreateEvent_Success(t *testing.T) {
 	}()
 
 	// mock handlers.CreateEvent
-	handlers.CreateEvent = func(event *models.Event) error {
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
 		event.ID = 1 // simulate DB insert
 		return nil
 	}
@@ -258,11 +340,12 @@ func TestCreateEvent_Success(t *testing.T) {
 
 	// expected response data
 	assert.Equal(t, "Event created successfully", response["message"])
-	assert.Equal(t, createEventPayload["name"], data["Name"])
-	assert.Equal(t, createEventPayload["description"], data["Description"])
-	assert.Equal(t, createEventPayload["location"], data["Location"])
-	assert.Equal(t, createEventPayload["dateTime"], data["DateTime"])
-	assert.Equal(t, float64(1), data["ID"]) // JSON numbers → float64
+	assert.Equal(t, createEventPayload["name"], data["name"])
+	assert.Equal(t, createEventPayload["description"], data["description"])
+	assert.Equal(t, createEventPayload["location"], data["location"])
+	// the same instant, rendered in UTC
+	assert.Equal(t, "2030-12-16T02:00:00Z", data["dateTime"])
+	assert.Equal(t, float64(1), data["id"]) // JSON numbers → float64
 }
 
 func TestCreateEvent_ErrorShouldBindJSON(t *testing.T) {
@@ -283,11 +366,113 @@ func TestCreateEvent_ErrorShouldBindJSON(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not parse request", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "invalid_request_body", response["code"])
+	assert.NotEmpty(t, response["detail"].(string))
 
 }
 
+func TestCreateEvent_ErrorValidation(t *testing.T) {
+	router := setupRouter()
+
+	// blank name, missing location and a date in the past
+	body := toJSON(t, map[string]string{
+		"name":        "   ",
+		"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+		"dateTime":    "2020-12-16T09:00:00+07:00",
+	})
+
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		CREATE_EVENT_PATH,
+		body,
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "validation_failed", response["code"])
+
+	// every failed field is listed using its JSON name
+	fields := []string{}
+	for _, e := range response["errors"].([]any) {
+		fields = append(fields, e.(map[string]any)["field"].(string))
+	}
+	assert.ElementsMatch(t, []string{"name", "location", "dateTime"}, fields)
+}
+
+func TestCreateEvent_ErrorValidationTrimmedName(t *testing.T) {
+	router := setupRouter()
+
+	// long enough only with the surrounding spaces, which are trimmed when saved
+	payload := map[string]any{}
+	for k, v := range createEventPayload {
+		payload[k] = v
+	}
+	payload["name"] = "  a  "
+
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		CREATE_EVENT_PATH,
+		toJSON(t, payload),
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "validation_failed", response["code"])
+	assert.Equal(t, []any{map[string]any{"field": "name", "message": "must be at least 3 characters"}}, response["errors"])
+}
+
+func TestCreateEvent_IgnoresClientIds(t *testing.T) {
+	router := setupRouter()
+
+	payload := map[string]any{
+		"id":     99,
+		"userId": 99,
+	}
+	for k, v := range createEventPayload {
+		payload[k] = v
+	}
+
+	originalCreateEvent := handlers.CreateEvent
+	defer func() {
+		handlers.CreateEvent = originalCreateEvent
+	}()
+
+	// capture event passed to handler
+	var savedEvent models.Event
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
+		savedEvent = *event
+		event.ID = 1
+		return nil
+	}
+
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		CREATE_EVENT_PATH,
+		toJSON(t, payload),
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, int64(0), savedEvent.UserID)
+	assert.Equal(t, int64(0), savedEvent.ID)
+}
+
 func TestCreateEvent_ErrorCreateEventHandler(t *testing.T) {
 	router := setupRouter()
 
@@ -297,7 +482,7 @@ func TestCreateEvent_ErrorCreateEventHandler(t *testing.T) {
 	defer func() {
 		handlers.CreateEvent = originalCreateEvent
 	}()
-	handlers.CreateEvent = func(event *models.Event) error {
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
 		return errors.New("simulate error create event handler")
 	}
 
@@ -316,8 +501,8 @@ func TestCreateEvent_ErrorCreateEventHandler(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not save event", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "internal_error", response["code"])
+	assert.Equal(t, "An unexpected error occurred", response["detail"])
 }
 
 func TestUpdateEvent_Success(t *testing.T) {
@@ -339,7 +524,7 @@ func TestUpdateEvent_Success(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{
 				ID:          1,
 				Name:        "Go Workshop Jakarta",
@@ -356,7 +541,7 @@ func TestUpdateEvent_Success(t *testing.T) {
 	defer func() {
 		handlers.UpdateEvent = originalUpdateEvent
 	}()
-	handlers.UpdateEvent = func(event *models.Event) error {
+	handlers.UpdateEvent = func(ctx context.Context, event *models.Event) error {
 		event.ID = 1
 		return nil
 	}
@@ -387,12 +572,12 @@ func TestUpdateEvent_Success(t *testing.T) {
 
 	// expected response data
 	assert.Equal(t, "Event updated successfully", response["message"])
-	assert.Equal(t, float64(1), data["ID"])
-	assert.Equal(t, updateEventPayload["name"], data["Name"])
-	assert.Equal(t, updateEventPayload["description"], data["Description"])
-	assert.Equal(t, updateEventPayload["location"], data["Location"])
-	assert.Equal(t, updateEventPayload["dateTime"], data["DateTime"])
-	assert.Equal(t, float64(1), data["UserID"])
+	assert.Equal(t, float64(1), data["id"])
+	assert.Equal(t, updateEventPayload["name"], data["name"])
+	assert.Equal(t, updateEventPayload["description"], data["description"])
+	assert.Equal(t, updateEventPayload["location"], data["location"])
+	assert.Equal(t, "2030-12-26T02:00:00Z", data["dateTime"])
+	assert.Equal(t, float64(1), data["userId"])
 }
 
 func TestUpdateEvent_ErrorParseEventId(t *testing.T) {
@@ -425,8 +610,8 @@ func TestUpdateEvent_ErrorParseEventId(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Invalid event ID", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "validation_failed", response["code"])
+	assert.NotEmpty(t, response["errors"])
 }
 
 func TestUpdateEvent_ErrorGetEventById(t *testing.T) {
@@ -446,9 +631,9 @@ func TestUpdateEvent_ErrorGetEventById(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{},
-			errors.New("simulate error get event by id")
+			models.ErrEventNotFound
 	}
 
 	req, _ := http.NewRequest(
@@ -468,13 +653,16 @@ func TestUpdateEvent_ErrorGetEventById(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Event not found", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "event_not_found", response["code"])
+	assert.Equal(t, "Event not found", response["title"])
 }
 
 func TestUpdateEvent_ErrorUnauthorize(t *testing.T) {
 	router := setupRouter()
 
+	// User 67 is not a member of the event either
+	mockEventRoles(t, nil)
+
 	body := toJSON(t, updateEventPayload)
 
 	originalVerifyToken := utils.VerifyToken
@@ -489,7 +677,7 @@ func TestUpdateEvent_ErrorUnauthorize(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{
 				ID:          1,
 				Name:        "Go Workshop Jakarta",
@@ -512,14 +700,63 @@ func TestUpdateEvent_ErrorUnauthorize(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "not_owner", response["code"])
+}
+
+// Permission is checked before the body, a non-owner learns nothing about what a valid one looks like
+func TestUpdateEvent_ErrorUnauthorizeInvalidBody(t *testing.T) {
+	router := setupRouter()
+
+	mockEventRoles(t, nil)
+
+	originalVerifyToken := utils.VerifyToken
+	defer func() {
+		utils.VerifyToken = originalVerifyToken
+	}()
+	utils.VerifyToken = func(token string) (int64, error) {
+		return 67, nil
+	}
+
+	originalGetEventByID := handlers.GetEventByID
+	defer func() {
+		handlers.GetEventByID = originalGetEventByID
+	}()
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &models.Event{
+				ID:          1,
+				Name:        "Go Workshop Jakarta",
+				Description: "A beginner-friendly workshop covering Go fundamentals and best practices.",
+				Location:    "Jakarta",
+				DateTime:    time.Date(2025, 12, 16, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+				UserID:      1,
+			},
+			nil
+	}
+
+	req, _ := http.NewRequest(
+		http.MethodPut,
+		strings.Replace(UPDATE_EVENT_PATH, ":eventId", "1", 1),
+		bytes.NewBufferString(`{"name": ""}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "sample-token-userid-67")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
 
 	var response map[string]any
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Not authorized to update event", response["message"])
-	assert.Equal(t, "not authorized to update event", response["error"])
+	assert.Equal(t, "not_owner", response["code"])
 }
 
 func TestUpdateEvent_ErrorShouldBindJSON(t *testing.T) {
@@ -533,6 +770,22 @@ func TestUpdateEvent_ErrorShouldBindJSON(t *testing.T) {
 		return 1, nil
 	}
 
+	originalGetEventByID := handlers.GetEventByID
+	defer func() {
+		handlers.GetEventByID = originalGetEventByID
+	}()
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &models.Event{
+				ID:          1,
+				Name:        "Go Workshop Jakarta",
+				Description: "A beginner-friendly workshop covering Go fundamentals and best practices.",
+				Location:    "Jakarta",
+				DateTime:    time.Date(2025, 12, 16, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+				UserID:      1,
+			},
+			nil
+	}
+
 	req, _ := http.NewRequest(
 		http.MethodPut,
 		strings.Replace(UPDATE_EVENT_PATH, ":eventId", "1", 1),
@@ -550,8 +803,8 @@ func TestUpdateEvent_ErrorShouldBindJSON(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not parse request", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "invalid_request_body", response["code"])
+	assert.NotEmpty(t, response["detail"].(string))
 }
 
 func TestUpdateEvent_ErrorUpdateEventHandler(t *testing.T) {
@@ -571,7 +824,7 @@ func TestUpdateEvent_ErrorUpdateEventHandler(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{
 				ID:          1,
 				Name:        "Go Workshop Jakarta",
@@ -587,7 +840,7 @@ func TestUpdateEvent_ErrorUpdateEventHandler(t *testing.T) {
 	defer func() {
 		handlers.UpdateEvent = originalUpdateEvent
 	}()
-	handlers.UpdateEvent = func(event *models.Event) error {
+	handlers.UpdateEvent = func(ctx context.Context, event *models.Event) error {
 		event.ID = 1
 		return errors.New("simulate error update event handler")
 	}
@@ -609,8 +862,8 @@ func TestUpdateEvent_ErrorUpdateEventHandler(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not update event", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "internal_error", response["code"])
+	assert.Equal(t, "An unexpected error occurred", response["detail"])
 }
 
 func TestDeleteEvent_Success(t *testing.T) {
@@ -630,7 +883,7 @@ func TestDeleteEvent_Success(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{
 				ID:          1,
 				Name:        "Go Workshop Jakarta",
@@ -647,7 +900,7 @@ func TestDeleteEvent_Success(t *testing.T) {
 	defer func() {
 		handlers.DeleteEvent = originalDeleteEvent
 	}()
-	handlers.DeleteEvent = func(event *models.Event) error {
+	handlers.DeleteEvent = func(ctx context.Context, event *models.Event) error {
 		return nil
 	}
 
@@ -706,8 +959,8 @@ func TestDeleteEvent_ErrorParseEventId(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Invalid event ID", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "validation_failed", response["code"])
+	assert.NotEmpty(t, response["errors"])
 }
 
 func TestDeleteEvent_ErrorGetEventById(t *testing.T) {
@@ -727,9 +980,9 @@ func TestDeleteEvent_ErrorGetEventById(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{},
-			errors.New("simulate error get event by id")
+			models.ErrEventNotFound
 	}
 
 	// simulate hit API
@@ -750,13 +1003,16 @@ func TestDeleteEvent_ErrorGetEventById(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Event not found", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "event_not_found", response["code"])
+	assert.Equal(t, "Event not found", response["title"])
 }
 
 func TestDeleteEvent_ErrorUnauthorize(t *testing.T) {
 	router := setupRouter()
 
+	// User 67 is not a member of the event either
+	mockEventRoles(t, nil)
+
 	// mock utils.VerifyToken
 	originalVerifyToken := utils.VerifyToken
 	defer func() {
@@ -771,7 +1027,7 @@ func TestDeleteEvent_ErrorUnauthorize(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{
 				ID:          1,
 				Name:        "Go Workshop Jakarta",
@@ -795,14 +1051,13 @@ func TestDeleteEvent_ErrorUnauthorize(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, http.StatusForbidden, w.Code)
 
 	var response map[string]any
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Not authorized to delete event", response["message"])
-	assert.Equal(t, "not authorized to delete event", response["error"])
+	assert.Equal(t, "not_owner", response["code"])
 }
 
 func TestDeleteEvent_ErrorDeleteEventHandler(t *testing.T) {
@@ -822,7 +1077,7 @@ func TestDeleteEvent_ErrorDeleteEventHandler(t *testing.T) {
 	defer func() {
 		handlers.GetEventByID = originalGetEventByID
 	}()
-	handlers.GetEventByID = func(eventId int64) (*models.Event, error) {
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
 		return &models.Event{
 				ID:          1,
 				Name:        "Go Workshop Jakarta",
@@ -839,7 +1094,7 @@ func TestDeleteEvent_ErrorDeleteEventHandler(t *testing.T) {
 	defer func() {
 		handlers.DeleteEvent = originalDeleteEvent
 	}()
-	handlers.DeleteEvent = func(event *models.Event) error {
+	handlers.DeleteEvent = func(ctx context.Context, event *models.Event) error {
 		return errors.New("simulate error delete event handler")
 	}
 
@@ -861,6 +1116,6 @@ func TestDeleteEvent_ErrorDeleteEventHandler(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not delete event", response["message"])
-	assert.NotEmpty(t, response["error"])
+	assert.Equal(t, "internal_error", response["code"])
+	assert.Equal(t, "An unexpected error occurred", response["detail"])
 }
@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEvents_Near(t *testing.T) {
+	router := setupRouter()
+
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() {
+		handlers.GetAllEvents = originalGetAllEvents
+	}()
+
+	latitude, longitude, distance := -6.2, 106.8, 3.14159
+
+	var got models.EventFilter
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+		got = filter
+		return []models.Event{
+			{
+				ID:         1,
+				Name:       "Go Workshop Jakarta",
+				DateTime:   time.Date(2030, 12, 16, 2, 0, 0, 0, time.UTC),
+				Latitude:   &latitude,
+				Longitude:  &longitude,
+				Address:    models.Address{City: "Jakarta", Country: "ID"},
+				DistanceKm: &distance,
+			},
+		}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, GET_EVENTS_PATH+"?near=-6.21,106.85&radius_km=10", http.NoBody)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &models.GeoPoint{Latitude: -6.21, Longitude: 106.85}, got.Near)
+	assert.Equal(t, 10.0, got.RadiusKm)
+
+	var response struct {
+		Data []map[string]any `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, 3.14, response.Data[0]["distanceKm"])
+		assert.Equal(t, -6.2, response.Data[0]["latitude"])
+		assert.Equal(t, map[string]any{"city": "Jakarta", "country": "ID"}, response.Data[0]["address"])
+	}
+
+	// the radius defaults when only near is given
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, GET_EVENTS_PATH+"?near=-6.21,106.85", http.NoBody))
+	assert.Equal(t, float64(defaultRadiusKm), got.RadiusKm)
+}
+
+func TestGetEvents_ErrorNear(t *testing.T) {
+	router := setupRouter()
+
+	tests := map[string]string{
+		"?near=jakarta":                  "near",
+		"?near=95,106.8":                 "near",
+		"?near=-6.2":                     "near",
+		"?radius_km=5":                   "radius_km",
+		"?near=-6.2,106.8&radius_km=0":   "radius_km",
+		"?near=-6.2,106.8&radius_km=501": "radius_km",
+	}
+
+	for query, field := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, GET_EVENTS_PATH+query, http.NoBody))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), `"field":"`+field+`"`, query)
+	}
+}
+
+func TestCreateEvent_ErrorCoordinates(t *testing.T) {
+	router := setupRouter()
+
+	// an out of range latitude without its longitude, and a country name instead of a code
+	body := toJSON(t, map[string]any{
+		"name":        "Go Workshop Jakarta",
+		"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+		"location":    "Jakarta",
+		"dateTime":    "2030-12-16T09:00:00+07:00",
+		"latitude":    95,
+		"address":     map[string]string{"country": "Indonesia"},
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, CREATE_EVENT_PATH, body)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	fields := []string{}
+	for _, e := range response["errors"].([]any) {
+		fields = append(fields, e.(map[string]any)["field"].(string))
+	}
+	assert.ElementsMatch(t, []string{"latitude", "longitude", "country"}, fields)
+}
@@ -0,0 +1,553 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/ics"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxImportBytes caps the size of an uploaded file
+const maxImportBytes = 5 << 20
+
+// maxImportUIDLength caps the UIDs kept to recognise re-imported events
+const maxImportUIDLength = 255
+
+// Media types of the files events are imported from
+const (
+	csvMediaType      = "text/csv"
+	calendarMediaType = "text/calendar"
+)
+
+// importRow is one event of an uploaded file, Row is its CSV line or the position of its VEVENT
+type importRow struct {
+	Row     int
+	UID     string
+	Request EventRequest
+
+	// Skip says why the row is left out, it is neither imported nor invalid
+	Skip string
+
+	// Errors found while reading the row, before the request is validated
+	Errors models.ValidationError
+}
+
+// ImportRowResponse reports what happened to one row, Action is create, update, unchanged, skip or invalid
+type ImportRowResponse struct {
+	Row     int                 `json:"row"`
+	UID     string              `json:"uid,omitempty"`
+	Action  string              `json:"action"`
+	EventID int64               `json:"eventId,omitempty"`
+	Reason  string              `json:"reason,omitempty"`
+	Errors  []models.FieldError `json:"errors,omitempty"`
+}
+
+type ImportResponse struct {
+	DryRun    bool                `json:"dryRun"`
+	Created   int                 `json:"created"`
+	Updated   int                 `json:"updated"`
+	Unchanged int                 `json:"unchanged"`
+	Skipped   int                 `json:"skipped"`
+	Invalid   int                 `json:"invalid"`
+	Rows      []ImportRowResponse `json:"rows"`
+}
+
+const (
+	importActionSkip    = "skip"
+	importActionInvalid = "invalid"
+)
+
+// importEvents creates the current user's events from an iCalendar or CSV file, all or nothing.
+// Rows with a UID already imported by the user update that event instead. With ?dry_run=true
+// every row is validated and reported, nothing is written.
+func importEvents(context *gin.Context) {
+	err := requireOrganizationMember(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	dryRun, err := parseDryRun(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = allowVenueConflicts(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	mediaType, data, err := readImportFile(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	var rows []importRow
+	switch mediaType {
+	case calendarMediaType:
+		rows, err = calendarImportRows(data)
+	default:
+		rows, err = csvImportRows(data)
+	}
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if len(rows) == 0 {
+		problems.Abort(context, models.NewValidationError("file", "contains no events"))
+		return
+	}
+
+	if len(rows) > models.MaxImportEvents {
+		problems.Abort(context, models.NewValidationError("file", fmt.Sprintf("must contain at most %d events", models.MaxImportEvents)))
+		return
+	}
+
+	response := ImportResponse{DryRun: dryRun, Rows: make([]ImportRowResponse, len(rows))}
+	invalid := &models.ValidationError{}
+
+	var events []models.Event
+	var imported []int
+	for i, row := range validateImportRows(rows) {
+		response.Rows[i] = ImportRowResponse{Row: row.Row, UID: row.UID}
+
+		switch {
+		case row.Skip != "":
+			response.Rows[i].Action = importActionSkip
+			response.Rows[i].Reason = row.Skip
+			response.Skipped++
+		case len(row.Errors.Fields) > 0:
+			response.Rows[i].Action = importActionInvalid
+			response.Rows[i].Errors = row.Errors.Fields
+			response.Invalid++
+
+			for _, f := range row.Errors.Fields {
+				invalid.Add(fmt.Sprintf("rows[%d].%s", row.Row, f.Field), f.Message)
+			}
+		default:
+			event := row.Request.toEvent()
+			event.ExternalUID = row.UID
+
+			events = append(events, event)
+			imported = append(imported, i)
+		}
+	}
+
+	// Nothing is imported unless every row is valid, a dry run still reports what the valid rows would do
+	if len(invalid.Fields) > 0 && !dryRun {
+		problems.Abort(context, invalid)
+		return
+	}
+
+	if len(events) > 0 {
+		results, err := handlers.ImportEvents(context.Request.Context(), context.GetInt64("userId"), events, dryRun)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+
+		for j, result := range results {
+			row := &response.Rows[imported[j]]
+			row.Action = result.Action
+			row.EventID = result.EventID
+
+			// The update would move the event onto other bookings of its venue, nothing was imported
+			if result.Conflict != nil {
+				row.Action = importActionInvalid
+				row.Errors = venueConflictFieldErrors(result.Conflict)
+				response.Invalid++
+
+				for _, f := range row.Errors {
+					invalid.Add(fmt.Sprintf("rows[%d].%s", row.Row, f.Field), f.Message)
+				}
+
+				continue
+			}
+
+			switch result.Action {
+			case models.ImportCreate:
+				response.Created++
+			case models.ImportUpdate:
+				response.Updated++
+			case models.ImportUnchanged:
+				response.Unchanged++
+			}
+		}
+	}
+
+	if len(invalid.Fields) > 0 && !dryRun {
+		problems.Abort(context, invalid)
+		return
+	}
+
+	message := "Events imported successfully"
+	if dryRun {
+		message = "Dry run, nothing was imported"
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    response,
+	})
+}
+
+// venueConflictFieldErrors reports the bookings an imported event overlaps on its start time
+func venueConflictFieldErrors(conflict *models.VenueConflictError) []models.FieldError {
+	fields := make([]models.FieldError, 0, len(conflict.Conflicts))
+	for _, c := range conflict.Conflicts {
+		fields = append(fields, models.FieldError{
+			Field:   "dateTime",
+			Message: fmt.Sprintf("overlaps event %d at its venue, from %s to %s", c.EventID, c.Start.Format(time.RFC3339), c.End.Format(time.RFC3339)),
+		})
+	}
+
+	return fields
+}
+
+func parseDryRun(context *gin.Context) (bool, error) {
+	value := context.Query("dry_run")
+	if value == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, models.NewValidationError("dry_run", "must be true or false")
+	}
+
+	return dryRun, nil
+}
+
+// readImportFile reads the upload, either the "file" field of a multipart form or the raw body,
+// and tells its media type from the Content-Type or, for form files, the .ics/.csv extension
+func readImportFile(context *gin.Context) (string, []byte, error) {
+	context.Request.Body = http.MaxBytesReader(context.Writer, context.Request.Body, maxImportBytes)
+
+	mediaType, _, _ := mime.ParseMediaType(context.GetHeader("Content-Type"))
+
+	var body io.Reader
+	switch mediaType {
+	case calendarMediaType, csvMediaType:
+		body = context.Request.Body
+	case "multipart/form-data":
+		header, err := context.FormFile("file")
+		if err != nil {
+			return "", nil, importReadError(err)
+		}
+
+		mediaType, _, _ = mime.ParseMediaType(header.Header.Get("Content-Type"))
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".ics":
+			mediaType = calendarMediaType
+		case ".csv":
+			mediaType = csvMediaType
+		}
+
+		if mediaType != calendarMediaType && mediaType != csvMediaType {
+			return "", nil, models.NewValidationError("file", "must be an iCalendar (.ics) or CSV (.csv) file")
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		defer file.Close()
+
+		body = file
+	default:
+		return "", nil, fmt.Errorf("%w: use %s, %s or multipart/form-data", problems.ErrUnsupportedMediaType, calendarMediaType, csvMediaType)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", nil, importReadError(err)
+	}
+
+	return mediaType, data, nil
+}
+
+func importReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return models.NewValidationError("file", fmt.Sprintf("must be at most %d MB", maxImportBytes>>20))
+	}
+
+	if errors.Is(err, http.ErrMissingFile) {
+		return models.NewValidationError("file", "is required")
+	}
+
+	return fmt.Errorf("%w: %v", problems.ErrInvalidRequestBody, err)
+}
+
+// validateImportRows checks each request like createEvent does, past start times aside so
+// series that already began can be migrated, and rejects a UID used by an earlier row
+func validateImportRows(rows []importRow) []importRow {
+	seen := map[string]int{}
+
+	for i := range rows {
+		row := &rows[i]
+		if row.Skip != "" {
+			continue
+		}
+
+		if len(row.UID) > maxImportUIDLength {
+			row.Errors.Add("uid", fmt.Sprintf("must be at most %d characters", maxImportUIDLength))
+		}
+
+		if row.UID != "" {
+			if first, ok := seen[row.UID]; ok {
+				row.Errors.Add("uid", fmt.Sprintf("is already used by row %d", first))
+			} else {
+				seen[row.UID] = row.Row
+			}
+		}
+
+		err := checkEventRequest(row.Request, binding.Validator.ValidateStruct(&row.Request), false)
+
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			for _, f := range validationErr.Fields {
+				// a field that could not be read is also missing, only report it once
+				if !row.hasError(f.Field) {
+					row.Errors.Add(f.Field, f.Message)
+				}
+			}
+		}
+	}
+
+	return rows
+}
+
+func (r importRow) hasError(field string) bool {
+	for _, f := range r.Errors.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Request fields read from each iCalendar property
+var calendarImportFields = map[string]string{
+	"UID":         "uid",
+	"SUMMARY":     "name",
+	"DESCRIPTION": "description",
+	"LOCATION":    "location",
+	"DTSTART":     "dateTime",
+	"DTEND":       "endDateTime",
+	"DURATION":    "endDateTime",
+	"RRULE":       "recurrence.rule",
+	"EXDATE":      "recurrence.exDates",
+}
+
+// calendarImportRows turns each VEVENT into a row, changes to single occurrences are skipped
+func calendarImportRows(data []byte) ([]importRow, error) {
+	events, err := ics.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, models.NewValidationError("file", "is not a valid iCalendar document: "+err.Error())
+	}
+
+	rows := make([]importRow, 0, len(events))
+	for i, e := range events {
+		row := importRow{
+			Row: i + 1,
+			UID: strings.TrimSpace(e.UID),
+			Request: EventRequest{
+				Name:        e.Summary,
+				Description: e.Description,
+				Location:    e.Location,
+				DateTime:    e.Start,
+				TimeZone:    e.TimeZone,
+			},
+		}
+
+		if e.RecurrenceID {
+			row.Skip = "changes to a single occurrence (RECURRENCE-ID) are not imported"
+		}
+
+		if !e.End.IsZero() {
+			row.Request.EndDateTime = &e.End
+		}
+
+		if e.RecurrenceRule != "" {
+			row.Request.Recurrence = &RecurrenceRequest{Rule: e.RecurrenceRule, ExDates: e.ExDates}
+		}
+
+		for property, message := range e.Errors {
+			field, ok := calendarImportFields[property]
+			if ok && !row.hasError(field) {
+				row.Errors.Add(field, message)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Columns of an imported CSV file, named like EventRequest fields and matched case-insensitively
+var csvImportColumns = []string{"uid", "name", "description", "location", "dateTime", "endDateTime", "durationMinutes", "timeZone", "recurrenceRule"}
+
+// Spreadsheet apps often start UTF-8 CSV exports with a byte order mark
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// csvImportRows reads a CSV file with a header line, name and dateTime columns are required
+func csvImportRows(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, models.NewValidationError("file", "is not a valid CSV file: "+err.Error())
+	}
+
+	columns, err := csvColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, models.NewValidationError("file", "is not a valid CSV file: "+err.Error())
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		values := map[string]string{}
+		for i, value := range record {
+			values[columns[i]] = strings.TrimSpace(value)
+		}
+
+		rows = append(rows, csvImportRow(line, values))
+	}
+
+	return rows, nil
+}
+
+// csvColumns maps each header cell to its column name
+func csvColumns(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	for i, cell := range header {
+		cell = strings.TrimSpace(cell)
+
+		for _, column := range csvImportColumns {
+			if strings.EqualFold(cell, column) {
+				columns[i] = column
+			}
+		}
+
+		if columns[i] == "" {
+			return nil, models.NewValidationError("file", fmt.Sprintf("has an unknown column %q, expected %s", cell, strings.Join(csvImportColumns, ", ")))
+		}
+	}
+
+	for _, required := range []string{"name", "dateTime"} {
+		if !slices.Contains(columns, required) {
+			return nil, models.NewValidationError("file", "must have a "+required+" column")
+		}
+	}
+
+	return columns, nil
+}
+
+func csvImportRow(line int, values map[string]string) importRow {
+	row := importRow{
+		Row: line,
+		UID: values["uid"],
+		Request: EventRequest{
+			Name:        values["name"],
+			Description: values["description"],
+			Location:    values["location"],
+			TimeZone:    values["timeZone"],
+		},
+	}
+
+	// Local times are taken in the row's time zone, an invalid zone is reported by the validation
+	loc := time.UTC
+	if row.Request.TimeZone != "" {
+		zone, err := models.LoadTimeZone(row.Request.TimeZone)
+		if err == nil {
+			loc = zone
+		}
+	}
+
+	if value := values["dateTime"]; value != "" {
+		start, err := parseImportTime(value, loc)
+		if err != nil {
+			row.Errors.Add("dateTime", err.Error())
+		}
+		row.Request.DateTime = start
+	}
+
+	if value := values["endDateTime"]; value != "" {
+		end, err := parseImportTime(value, loc)
+		if err != nil {
+			row.Errors.Add("endDateTime", err.Error())
+		} else {
+			row.Request.EndDateTime = &end
+		}
+	}
+
+	if value := values["durationMinutes"]; value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil {
+			row.Errors.Add("durationMinutes", "must be a whole number of minutes")
+		}
+		row.Request.DurationMinutes = minutes
+	}
+
+	if value := values["recurrenceRule"]; value != "" {
+		row.Request.Recurrence = &RecurrenceRequest{Rule: value}
+	}
+
+	return row
+}
+
+// Local date-time layouts accepted in CSV files besides RFC 3339, as spreadsheets write them
+var importTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// parseImportTime reads an RFC 3339 date-time, or a local date-time in loc
+func parseImportTime(value string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	for _, layout := range importTimeLayouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.New("must be an RFC 3339 date-time or a local date-time such as 2030-12-16 09:00")
+}
@@ -0,0 +1,268 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const IMPORT_EVENTS_PATH = "/api/v1/events/import"
+
+// mockImportEvents records what would be imported, every event is created with ids from 100
+func mockImportEvents(t *testing.T) (*[]models.Event, *bool) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	original := handlers.ImportEvents
+	t.Cleanup(func() { handlers.ImportEvents = original })
+
+	var imported []models.Event
+	var dryRun bool
+	handlers.ImportEvents = func(ctx context.Context, userId int64, events []models.Event, dry bool) ([]models.ImportResult, error) {
+		imported, dryRun = events, dry
+
+		results := make([]models.ImportResult, len(events))
+		for i := range events {
+			results[i] = models.ImportResult{EventID: int64(100 + i), Action: models.ImportCreate}
+		}
+		return results, nil
+	}
+
+	return &imported, &dryRun
+}
+
+func postImport(router http.Handler, contentType string, body []byte, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, IMPORT_EVENTS_PATH+query, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func decodeImportResponse(t *testing.T, w *httptest.ResponseRecorder) ImportResponse {
+	t.Helper()
+
+	var response struct {
+		Data ImportResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response.Data
+}
+
+const importCSV = "\xef\xbb\xbfUID,Name,Description,Location,DateTime,DurationMinutes,TimeZone,RecurrenceRule\n" +
+	"ws-1,Go Workshop Jakarta,Go fundamentals,Jakarta,2031-03-01 09:00,90,Asia/Jakarta,\n" +
+	"meetup-1,Weekly meetup,\"Talks, then drinks\",Bandung,2031-03-04T19:00:00+07:00,,,FREQ=WEEKLY;COUNT=4\n"
+
+func TestImportEvents_CSV(t *testing.T) {
+	imported, dryRun := mockImportEvents(t)
+	router := setupRegisteredRouter()
+
+	w := postImport(router, "text/csv", []byte(importCSV), "")
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, *dryRun)
+
+	if assert.Len(t, *imported, 2) {
+		workshop := (*imported)[0]
+		assert.Equal(t, "ws-1", workshop.ExternalUID)
+		// local times are taken in the row's time zone
+		assert.Equal(t, time.Date(2031, 3, 1, 2, 0, 0, 0, time.UTC), workshop.DateTime)
+		assert.Equal(t, time.Date(2031, 3, 1, 3, 30, 0, 0, time.UTC), workshop.EndDateTime)
+		assert.Equal(t, "Asia/Jakarta", workshop.TimeZone)
+
+		meetup := (*imported)[1]
+		assert.Equal(t, "Talks, then drinks", meetup.Description)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=4", meetup.Recurrence.Rule)
+	}
+
+	response := decodeImportResponse(t, w)
+	assert.Equal(t, 2, response.Created)
+	assert.Equal(t, ImportRowResponse{Row: 3, UID: "meetup-1", Action: models.ImportCreate, EventID: 101}, response.Rows[1])
+}
+
+const invalidImportCSV = "uid,name,description,location,dateTime\n" +
+	"ok,Go Workshop Jakarta,Go fundamentals,Jakarta,2031-03-01T09:00:00Z\n" +
+	"bad,Go,Go fundamentals,Jakarta,next tuesday\n" +
+	"ok,Go Workshop Bandung,Go fundamentals,Bandung,2031-03-08T09:00:00Z\n"
+
+func TestImportEvents_InvalidRowsImportNothing(t *testing.T) {
+	imported, _ := mockImportEvents(t)
+	router := setupRegisteredRouter()
+
+	w := postImport(router, "text/csv", []byte(invalidImportCSV), "")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, *imported)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `"field":"rows[3].name"`)
+	assert.Contains(t, body, `"field":"rows[3].dateTime"`)
+	assert.Contains(t, body, `"field":"rows[4].uid"`)
+}
+
+func TestImportEvents_DryRunReportsEveryRow(t *testing.T) {
+	imported, dryRun := mockImportEvents(t)
+	router := setupRegisteredRouter()
+
+	w := postImport(router, "text/csv", []byte(invalidImportCSV), "?dry_run=true")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, *dryRun)
+	assert.Len(t, *imported, 1)
+
+	response := decodeImportResponse(t, w)
+	assert.True(t, response.DryRun)
+	assert.Equal(t, 1, response.Created)
+	assert.Equal(t, 2, response.Invalid)
+	if assert.Len(t, response.Rows, 3) {
+		assert.Equal(t, models.ImportCreate, response.Rows[0].Action)
+		assert.Equal(t, "invalid", response.Rows[1].Action)
+		assert.Len(t, response.Rows[1].Errors, 2)
+		assert.Equal(t, "invalid", response.Rows[2].Action)
+	}
+}
+
+func TestImportEvents_VenueConflictRows(t *testing.T) {
+	mockImportEvents(t)
+	router := setupRegisteredRouter()
+
+	start := time.Date(2031, 3, 1, 2, 0, 0, 0, time.UTC)
+	handlers.ImportEvents = func(ctx context.Context, userId int64, events []models.Event, dry bool) ([]models.ImportResult, error) {
+		if models.VenueConflictsAllowed(ctx) {
+			return []models.ImportResult{{EventID: 100, Action: models.ImportCreate}, {EventID: 42, Action: models.ImportUpdate}}, nil
+		}
+
+		return []models.ImportResult{
+			{Action: models.ImportCreate},
+			{EventID: 42, Action: models.ImportUpdate, Conflict: &models.VenueConflictError{Conflicts: []models.VenueConflict{
+				{EventID: 8, Start: start, End: start.Add(time.Hour)},
+			}}},
+		}, nil
+	}
+
+	w := postImport(router, "text/csv", []byte(importCSV), "")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"rows[3].dateTime"`)
+	assert.Contains(t, w.Body.String(), "overlaps event 8 at its venue")
+
+	w = postImport(router, "text/csv", []byte(importCSV), "?dry_run=true")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := decodeImportResponse(t, w)
+	assert.Equal(t, 1, response.Invalid)
+	if assert.Len(t, response.Rows, 2) {
+		assert.Equal(t, models.ImportCreate, response.Rows[0].Action)
+		assert.Equal(t, "invalid", response.Rows[1].Action)
+	}
+
+	// the organizer imports anyway
+	w = postImport(router, "text/csv", []byte(importCSV), "?allow_conflicts=true")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, decodeImportResponse(t, w).Updated)
+}
+
+const importICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Other//Tool//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly@other\r\n" +
+	"DTSTAMP:20301101T080000Z\r\n" +
+	"DTSTART;TZID=Asia/Jakarta:20310107T090000\r\n" +
+	"DTEND;TZID=Asia/Jakarta:20310107T110000\r\n" +
+	"RRULE:FREQ=WEEKLY;COUNT=10\r\n" +
+	"SUMMARY:Weekly meetup\r\n" +
+	"DESCRIPTION:Talks\\, then drinks\r\n" +
+	"LOCATION:Jakarta\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly@other\r\n" +
+	"DTSTAMP:20301101T080000Z\r\n" +
+	"RECURRENCE-ID;TZID=Asia/Jakarta:20310114T090000\r\n" +
+	"DTSTART;TZID=Asia/Jakarta:20310114T100000\r\n" +
+	"SUMMARY:Weekly meetup\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestImportEvents_CalendarUpload(t *testing.T) {
+	imported, _ := mockImportEvents(t)
+	router := setupRegisteredRouter()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "export.ics")
+	_, _ = part.Write([]byte(importICS))
+	_ = form.Close()
+
+	w := postImport(router, form.FormDataContentType(), body.Bytes(), "")
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	if assert.Len(t, *imported, 1) {
+		e := (*imported)[0]
+		assert.Equal(t, "weekly@other", e.ExternalUID)
+		assert.Equal(t, "Talks, then drinks", e.Description)
+		assert.Equal(t, time.Date(2031, 1, 7, 2, 0, 0, 0, time.UTC), e.DateTime)
+		assert.Equal(t, 2*time.Hour, e.Duration())
+		assert.Equal(t, "Asia/Jakarta", e.TimeZone)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=10", e.Recurrence.Rule)
+	}
+
+	// the moved occurrence is reported, not imported and not a duplicate UID
+	response := decodeImportResponse(t, w)
+	assert.Equal(t, 1, response.Skipped)
+	assert.Equal(t, "skip", response.Rows[1].Action)
+	assert.NotEmpty(t, response.Rows[1].Reason)
+}
+
+func TestImportEvents_RejectedFiles(t *testing.T) {
+	mockImportEvents(t)
+	router := setupRegisteredRouter()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		query       string
+		expected    int
+		field       string
+	}{
+		{"json", "application/json", `{}`, "", http.StatusUnsupportedMediaType, ""},
+		{"unknown column", "text/csv", "name,dateTime,colour\n", "", http.StatusBadRequest, "file"},
+		{"missing column", "text/csv", "name,location\nGo Workshop,Jakarta\n", "", http.StatusBadRequest, "file"},
+		{"empty", "text/csv", "name,dateTime\n", "", http.StatusBadRequest, "file"},
+		{"not a calendar", "text/calendar", "BEGIN:VCARD\r\n", "", http.StatusBadRequest, "file"},
+		{"too large", "text/csv", "name,dateTime\n" + strings.Repeat("x", maxImportBytes), "", http.StatusBadRequest, "file"},
+		{"dry run", "text/csv", importCSV, "?dry_run=maybe", http.StatusBadRequest, "dry_run"},
+	}
+
+	for _, tt := range tests {
+		w := postImport(router, tt.contentType, []byte(tt.body), tt.query)
+
+		assert.Equal(t, tt.expected, w.Code, tt.name)
+		if tt.field != "" {
+			assert.Contains(t, w.Body.String(), `"field":"`+tt.field+`"`, tt.name)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, IMPORT_EVENTS_PATH, strings.NewReader(importCSV))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
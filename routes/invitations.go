@@ -0,0 +1,265 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"example.com/event/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultInvitationLinkHours = 7 * 24
+	maxInvitationLinkHours     = 30 * 24
+)
+
+// InvitationRequest invites a user, by the email they signed up with, to a private event
+type InvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// InvitationLinkRequest sets how long a new invitation link works, a week when omitted
+type InvitationLinkRequest struct {
+	ExpiresInHours int `json:"expiresInHours,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+// AcceptInvitationRequest carries the token of an invitation link
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type InvitationResponse struct {
+	UserID    int64     `json:"userId"`
+	Email     string    `json:"email"`
+	InvitedBy int64     `json:"invitedBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type InvitationLinkResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func newInvitationResponse(i models.EventInvitation) InvitationResponse {
+	return InvitationResponse{
+		UserID:    i.UserID,
+		Email:     i.Email,
+		InvitedBy: i.InvitedBy,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+// getEventInvitations lists who is invited, for the owner and co-organizers
+func getEventInvitations(context *gin.Context) {
+	event, err := findEventWithPermission(context, models.PermissionEdit)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	invitations, err := handlers.GetEventInvitations(context.Request.Context(), event.ID)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]InvitationResponse, 0, len(invitations))
+	for _, i := range invitations {
+		responses = append(responses, newInvitationResponse(i))
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of invitations",
+		"data":    responses,
+	})
+}
+
+// inviteToEvent invites a signed up user, the owner and co-organizers invite
+func inviteToEvent(context *gin.Context) {
+	var request InvitationRequest
+
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		problems.Abort(context, problems.BindError(err))
+		return
+	}
+
+	event, err := findEventWithPermission(context, models.PermissionEdit)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	invitation, err := handlers.InviteToEvent(context.Request.Context(), event, request.Email, context.GetInt64("userId"))
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "User invited successfully",
+		"data":    newInvitationResponse(*invitation),
+	})
+}
+
+// revokeEventInvitation takes an invitation back, registrations already made stay
+func revokeEventInvitation(context *gin.Context) {
+	userId, err := strconv.ParseInt(context.Param("userId"), 10, 64)
+	if err != nil || userId <= 0 {
+		problems.Abort(context, models.NewValidationError("userId", "must be a positive integer"))
+		return
+	}
+
+	event, err := findEventWithPermission(context, models.PermissionEdit)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	invitation, err := handlers.GetEventInvitation(context.Request.Context(), event.ID, userId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = handlers.RevokeEventInvitation(context.Request.Context(), invitation)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Invitation revoked successfully",
+	})
+}
+
+// createInvitationLink signs a link inviting whoever accepts it until it expires.
+// Links cannot be revoked one by one, they stop working when their issuer can no longer edit the event.
+func createInvitationLink(context *gin.Context) {
+	var request InvitationLinkRequest
+
+	// The body is optional
+	if context.Request.ContentLength != 0 {
+		err := context.ShouldBindJSON(&request)
+		if err != nil {
+			problems.Abort(context, problems.BindError(err))
+			return
+		}
+	}
+
+	event, err := findEventWithPermission(context, models.PermissionEdit)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	hours := request.ExpiresInHours
+	if hours == 0 {
+		hours = defaultInvitationLinkHours
+	}
+
+	expiresAt := time.Now().UTC().Add(time.Duration(hours) * time.Hour).Truncate(time.Second)
+
+	token, err := utils.GenerateInvitationToken(event.ID, context.GetInt64("userId"), expiresAt)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Invitation link created successfully",
+		"data": InvitationLinkResponse{
+			Token:     token,
+			URL:       fmt.Sprintf("/api/v1/events/%d/invitations/accept?token=%s", event.ID, token),
+			ExpiresAt: expiresAt,
+		},
+	})
+}
+
+// acceptInvitation invites the current user with the token of an invitation link, sent as ?token= or in the body
+func acceptInvitation(context *gin.Context) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	request := AcceptInvitationRequest{Token: context.Query("token")}
+	if request.Token == "" {
+		err = context.ShouldBindJSON(&request)
+		if err != nil {
+			problems.Abort(context, problems.BindError(err))
+			return
+		}
+	}
+
+	tokenEventId, invitedBy, err := utils.VerifyInvitationToken(request.Token)
+	if errors.Is(err, utils.ErrInvalidToken) || err == nil && tokenEventId != eventId {
+		problems.Abort(context, models.ErrInvalidInvitation)
+		return
+	}
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// The event is looked up as is, the invitation is what lets the user see it
+	event, err := handlers.GetEventByID(context.Request.Context(), eventId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Links die with the issuer's right to invite
+	role := models.EventRoleOwner
+	if invitedBy != event.UserID {
+		role, err = handlers.GetEventRole(context.Request.Context(), event.ID, invitedBy)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+	}
+
+	if !role.Can(models.PermissionEdit) {
+		problems.Abort(context, models.ErrInvalidInvitation)
+		return
+	}
+
+	invitation, err := handlers.AcceptEventInvitation(context.Request.Context(), event, context.GetInt64("userId"), invitedBy)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Invitation accepted successfully",
+		"data":    newInvitationResponse(*invitation),
+	})
+}
+
+// requireInvitation lets the users invited to a private event through. Signed-in users who are not invited
+// get ErrNotInvited, which findEvent hides as ErrEventNotFound, anyone else ErrEventNotFound.
+func requireInvitation(context *gin.Context, event *models.Event) error {
+	userId := context.GetInt64("userId")
+	if userId == 0 {
+		return models.ErrEventNotFound
+	}
+
+	invited, err := handlers.IsEventInvited(context.Request.Context(), event.ID, userId)
+	if err != nil {
+		return err
+	}
+
+	if !invited {
+		return models.ErrNotInvited
+	}
+
+	return nil
+}
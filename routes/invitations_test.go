@@ -0,0 +1,168 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks a private published event owned by user 1, user 5 is invited to it
+func mockPrivateEvent(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalGetEventByID := handlers.GetEventByID
+	originalIsEventInvited := handlers.IsEventInvited
+	t.Cleanup(func() {
+		handlers.GetEventByID = originalGetEventByID
+		handlers.IsEventInvited = originalIsEventInvited
+	})
+
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		event, err := originalGetEventByID(ctx, eventId)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Visibility = models.VisibilityPrivate
+		return event, nil
+	}
+
+	handlers.IsEventInvited = func(ctx context.Context, eventId, userId int64) (bool, error) {
+		return userId == 5, nil
+	}
+}
+
+func TestRegisterEvent_PrivateRequiresInvitation(t *testing.T) {
+	mockPrivateEvent(t)
+	router := setupRegisteredRouter()
+
+	originalRegister := handlers.RegisterEvent
+	defer func() { handlers.RegisterEvent = originalRegister }()
+
+	handlers.RegisterEvent = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time) error {
+		return nil
+	}
+
+	// user-2 is signed in but not invited, they reached the event through a link and are told why
+	for token, expected := range map[string]int{
+		"user-5": http.StatusCreated,
+		"user-8": http.StatusCreated,
+		"user-2": http.StatusForbidden,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/registrations", http.NoBody)
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+		if expected == http.StatusForbidden {
+			assert.Contains(t, w.Body.String(), `"code":"not_invited"`)
+		}
+	}
+}
+
+func TestGetEventById_PrivateNotInvited(t *testing.T) {
+	mockPrivateEvent(t)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/1", http.NoBody)
+	req.Header.Set("Authorization", "user-2")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// only registering tells an uninvited user the event exists
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"event_not_found"`)
+}
+
+func TestGetEventById_PrivateAnonymous(t *testing.T) {
+	mockPrivateEvent(t)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/1", http.NoBody)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// the event does not leak, as for drafts
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"event_not_found"`)
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	mockPrivateEvent(t)
+	router := setupRegisteredRouter()
+
+	originalAccept := handlers.AcceptEventInvitation
+	defer func() { handlers.AcceptEventInvitation = originalAccept }()
+
+	handlers.AcceptEventInvitation = func(ctx context.Context, event *models.Event, userId, invitedBy int64) (*models.EventInvitation, error) {
+		return &models.EventInvitation{ID: 1, EventID: event.ID, UserID: userId, Email: "guest@example.com", InvitedBy: invitedBy, CreatedAt: time.Now()}, nil
+	}
+
+	token := func(eventId, invitedBy int64, expiresAt time.Time) string {
+		token, err := utils.GenerateInvitationToken(eventId, invitedBy, expiresAt)
+		assert.NoError(t, err)
+		return token
+	}
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"issued by the owner", token(1, 1, tomorrow), http.StatusOK},
+		{"issued by a co-organizer", token(1, 8, tomorrow), http.StatusOK},
+		{"issuer cannot edit", token(1, 9, tomorrow), http.StatusForbidden},
+		{"another event", token(2, 1, tomorrow), http.StatusForbidden},
+		{"expired", token(1, 1, time.Now().Add(-time.Minute)), http.StatusForbidden},
+		{"not a token", "nope", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/invitations/accept", strings.NewReader(`{"token":"`+tt.token+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "user-2")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, tt.name)
+		if tt.status == http.StatusForbidden {
+			assert.Contains(t, w.Body.String(), `"code":"invalid_invitation"`, tt.name)
+		}
+	}
+}
+
+func TestCreateInvitationLink_ByRole(t *testing.T) {
+	mockPrivateEvent(t)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"user-1": http.StatusCreated,
+		"user-8": http.StatusCreated,
+		"user-9": http.StatusForbidden,
+		"user-5": http.StatusForbidden,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/invitation-links", strings.NewReader(`{"expiresInHours":48}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+}
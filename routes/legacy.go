@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"time"
+
+	"example.com/event/middlewares"
+	"example.com/event/models"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// Date the unversioned routes were deprecated in favour of /api/v1
+	legacyDeprecatedAt = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	// Date the unversioned routes will be removed
+	legacySunsetAt = time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC)
+)
+
+// deprecated returns the middleware marking a legacy route replaced by successor
+func deprecated(successor string) gin.HandlerFunc {
+	return middlewares.Deprecated(legacyDeprecatedAt, legacySunsetAt, successor)
+}
+
+// registerLegacyRoutes keeps the original unversioned paths working as aliases of /api/v1
+func registerLegacyRoutes(server *gin.Engine) {
+	// Scoped to the organization of X-Organization like /api/v1, legacy paths have no :orgSlug
+	legacy := server.Group("", middlewares.Tenant)
+
+	// GET All Events
+	legacy.GET("/events", deprecated("/api/v1/events"), middlewares.OptionalAuthenticate, getEvents)
+
+	// GET Event by ID
+	legacy.GET("/event/:eventId", deprecated("/api/v1/events/:eventId"), middlewares.OptionalAuthenticate, getEventById)
+
+	// POST Create Event, legacy clients have no publish step so their events are published right away
+	legacy.POST("/event", deprecated("/api/v1/events"), middlewares.Authenticate, createEventAs(models.EventStatusPublished))
+
+	// PUT Update Event
+	legacy.PUT("/event/:eventId", deprecated("/api/v1/events/:eventId"), middlewares.Authenticate, middlewares.RequireIfMatch, updateEvent)
+
+	// PATCH Event
+	legacy.PATCH("/event/:eventId", deprecated("/api/v1/events/:eventId"), middlewares.Authenticate, middlewares.RequireIfMatch, patchEvent)
+
+	// DELETE Event
+	legacy.DELETE("/event/:eventId", deprecated("/api/v1/events/:eventId"), middlewares.Authenticate, middlewares.RequireIfMatch, deleteEvent)
+
+	// Register Event
+	legacy.POST("/event/:eventId/register", deprecated("/api/v1/events/:eventId/registrations"), middlewares.Authenticate, registerEvent)
+
+	// Unregister Event
+	legacy.DELETE("/event/:eventId/unregister", deprecated("/api/v1/events/:eventId/registrations/me"), middlewares.Authenticate, unregisterEvent)
+
+	// RSVP to Event
+	legacy.PUT("/event/:eventId/rsvp", deprecated("/api/v1/events/:eventId/rsvp"), middlewares.Authenticate, updateRSVP)
+
+	// Sign Up User
+	legacy.POST("/user/signup", deprecated("/api/v1/users"), signUp)
+
+	// Login User
+	legacy.POST("/user/login", deprecated("/api/v1/users/login"), login)
+}
@@ -0,0 +1,294 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// MemberRequest invites a user, by the email they signed up with, to help run an event
+type MemberRequest struct {
+	Email string           `json:"email" binding:"required,email"`
+	Role  models.EventRole `json:"role" binding:"required,oneof=co_organizer check_in_staff"`
+}
+
+// TransferRequest names the member becoming the owner of an event
+type TransferRequest struct {
+	UserID int64 `json:"userId" binding:"required,min=1"`
+}
+
+type MemberResponse struct {
+	UserID    int64            `json:"userId"`
+	Email     string           `json:"email"`
+	Role      models.EventRole `json:"role"`
+	InvitedBy *int64           `json:"invitedBy,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+type RegistrationResponse struct {
+	UserID          int64             `json:"userId"`
+	Email           string            `json:"email"`
+	OccurrenceStart *time.Time        `json:"occurrenceStart,omitempty"`
+	Status          models.RSVPStatus `json:"status"`
+}
+
+func newRegistrationResponse(r models.Registration) RegistrationResponse {
+	return RegistrationResponse{
+		UserID:          r.UserID,
+		Email:           r.Email,
+		OccurrenceStart: r.OccurrenceStart,
+		Status:          r.Status,
+	}
+}
+
+func newMemberResponse(m models.EventMember) MemberResponse {
+	return MemberResponse{
+		UserID:    m.UserID,
+		Email:     m.Email,
+		Role:      m.Role,
+		InvitedBy: m.InvitedBy,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func getEventMembers(context *gin.Context) {
+	event, err := findEventWithPermission(context, models.PermissionView)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	members, err := handlers.GetEventMembers(context.Request.Context(), event.ID)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]MemberResponse, 0, len(members))
+	for _, m := range members {
+		responses = append(responses, newMemberResponse(m))
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of event members",
+		"data":    responses,
+	})
+}
+
+// addEventMember invites a co-organizer (owner only) or check-in staff (owner and co-organizers)
+func addEventMember(context *gin.Context) {
+	var request MemberRequest
+
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		problems.Abort(context, problems.BindError(err))
+		return
+	}
+
+	event, role, err := findEventWithRole(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if !role.CanManage(request.Role) {
+		problems.Abort(context, models.ErrRoleNotPermitted)
+		return
+	}
+
+	member, err := handlers.AddEventMember(context.Request.Context(), event, request.Email, request.Role, context.GetInt64("userId"))
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Member added successfully",
+		"data":    newMemberResponse(*member),
+	})
+}
+
+// removeEventMember removes a member the caller manages, any member can leave on their own
+func removeEventMember(context *gin.Context) {
+	userId, err := strconv.ParseInt(context.Param("userId"), 10, 64)
+	if err != nil || userId <= 0 {
+		problems.Abort(context, models.NewValidationError("userId", "must be a positive integer"))
+		return
+	}
+
+	event, role, err := findEventWithRole(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	member, err := handlers.GetEventMember(context.Request.Context(), event.ID, userId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if userId != context.GetInt64("userId") && !role.CanManage(member.Role) {
+		problems.Abort(context, models.ErrRoleNotPermitted)
+		return
+	}
+
+	err = handlers.RemoveEventMember(context.Request.Context(), member)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Member removed successfully",
+	})
+}
+
+// transferEventOwnership hands the event over to one of its members, the previous owner stays on as a co-organizer
+func transferEventOwnership(context *gin.Context) {
+	var request TransferRequest
+
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		problems.Abort(context, problems.BindError(err))
+		return
+	}
+
+	event, err := findEventWithPermission(context, models.PermissionDelete)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Reject if the client saw an older version
+	err = checkIfMatch(context, *event)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = handlers.TransferEventOwnership(context.Request.Context(), event, request.UserID)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.Header("ETag", eventETag(*event))
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Event ownership transferred successfully",
+		"data":    newEventResponse(*event),
+	})
+}
+
+// getEventRegistrations lists the attendees for check-in, ?occurrence_start= narrows it to one occurrence
+func getEventRegistrations(context *gin.Context) {
+	var occurrence *time.Time
+	if value := context.Query("occurrence_start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			problems.Abort(context, models.NewValidationError("occurrence_start", "must be an RFC 3339 date-time"))
+			return
+		}
+
+		occurrence = &start
+	}
+
+	event, err := findEventWithPermission(context, models.PermissionCheckIn)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	registrations, err := handlers.GetEventRegistrations(context.Request.Context(), event, occurrence)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]RegistrationResponse, 0, len(registrations))
+	for _, r := range registrations {
+		responses = append(responses, newRegistrationResponse(r))
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of event registrations",
+		"data":    responses,
+	})
+}
+
+// findEventWithPermission loads the event of the :eventId path parameter, requiring permission on it
+func findEventWithPermission(context *gin.Context, permission models.EventPermission) (*models.Event, error) {
+	event, role, err := findEventWithRole(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if !role.Can(permission) {
+		return nil, models.ErrRoleNotPermitted
+	}
+
+	return event, nil
+}
+
+// findEventWithRole loads the event of the :eventId path parameter and the current user's role on it,
+// ErrNotOwner when the user has none
+func findEventWithRole(context *gin.Context) (*models.Event, models.EventRole, error) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		return nil, "", err
+	}
+
+	event, err := findEvent(context, eventId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	role, err := eventRole(context, event)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if role == "" {
+		return nil, "", models.ErrNotOwner
+	}
+
+	return event, role, nil
+}
+
+// requireEventPermission checks the current user's role on the event grants permission.
+// Users without a role get ErrNotOwner, members whose role falls short ErrRoleNotPermitted.
+func requireEventPermission(context *gin.Context, event *models.Event, permission models.EventPermission) error {
+	role, err := eventRole(context, event)
+	if err != nil {
+		return err
+	}
+
+	if role == "" {
+		return models.ErrNotOwner
+	}
+
+	if !role.Can(permission) {
+		return models.ErrRoleNotPermitted
+	}
+
+	return nil
+}
+
+// eventRole is the current user's role on the event, the owner and anonymous users are answered without a lookup
+func eventRole(context *gin.Context, event *models.Event) (models.EventRole, error) {
+	userId := context.GetInt64("userId")
+
+	switch userId {
+	case event.UserID:
+		return models.EventRoleOwner, nil
+	case 0:
+		return "", nil
+	default:
+		return handlers.GetEventRole(context.Request.Context(), event.ID, userId)
+	}
+}
@@ -0,0 +1,261 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks role lookup, users missing from roles are not members of any event
+func mockEventRoles(t *testing.T, roles map[int64]models.EventRole) {
+	originalGetEventRole := handlers.GetEventRole
+	t.Cleanup(func() {
+		handlers.GetEventRole = originalGetEventRole
+	})
+
+	handlers.GetEventRole = func(ctx context.Context, eventId, userId int64) (models.EventRole, error) {
+		return roles[userId], nil
+	}
+}
+
+func TestGetEventById_DraftVisibleToMembers(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusDraft)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"user-2": http.StatusNotFound,
+		"user-8": http.StatusOK,
+		"user-9": http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/1", http.NoBody)
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+}
+
+func TestChangeEventStatus_ByRole(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalChangeEventStatus := handlers.ChangeEventStatus
+	defer func() {
+		handlers.ChangeEventStatus = originalChangeEventStatus
+	}()
+
+	handlers.ChangeEventStatus = func(ctx context.Context, event *models.Event, status models.EventStatus, reason string) error {
+		event.Status = status
+		event.Version++
+		return nil
+	}
+
+	for token, expected := range map[string]string{
+		"user-8": "",
+		"user-9": "role_not_permitted",
+		"user-2": "not_owner",
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/complete", http.NoBody)
+		req.Header.Set("Authorization", token)
+		req.Header.Set("If-Match", `"2"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if expected == "" {
+			assert.Equal(t, http.StatusOK, w.Code, token)
+			continue
+		}
+
+		assert.Equal(t, http.StatusForbidden, w.Code, token)
+		assert.Contains(t, w.Body.String(), `"code":"`+expected+`"`, token)
+	}
+}
+
+func TestDeleteEvent_ErrorCoOrganizer(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/events/1", http.NoBody)
+	req.Header.Set("Authorization", "user-8")
+	req.Header.Set("If-Match", `"2"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"role_not_permitted"`)
+}
+
+func TestAddEventMember_ByRole(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalAddEventMember := handlers.AddEventMember
+	defer func() {
+		handlers.AddEventMember = originalAddEventMember
+	}()
+
+	handlers.AddEventMember = func(ctx context.Context, event *models.Event, email string, role models.EventRole, invitedBy int64) (*models.EventMember, error) {
+		return &models.EventMember{ID: 1, EventID: event.ID, UserID: 10, Email: email, Role: role, InvitedBy: &invitedBy, CreatedAt: time.Now()}, nil
+	}
+
+	tests := []struct {
+		token  string
+		role   string
+		status int
+	}{
+		{"user-1", "co_organizer", http.StatusCreated},
+		{"user-8", "check_in_staff", http.StatusCreated},
+		{"user-8", "co_organizer", http.StatusForbidden},
+		{"user-9", "check_in_staff", http.StatusForbidden},
+		{"user-2", "check_in_staff", http.StatusForbidden},
+		{"user-1", "owner", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		body := `{"email":"helper@example.com","role":"` + tt.role + `"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/members", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", tt.token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, tt.token+" "+tt.role)
+	}
+}
+
+func TestRemoveEventMember_LeaveOrManage(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalGetEventMember := handlers.GetEventMember
+	originalRemoveEventMember := handlers.RemoveEventMember
+	defer func() {
+		handlers.GetEventMember = originalGetEventMember
+		handlers.RemoveEventMember = originalRemoveEventMember
+	}()
+
+	handlers.GetEventMember = func(ctx context.Context, eventId, userId int64) (*models.EventMember, error) {
+		switch userId {
+		case 8:
+			return &models.EventMember{EventID: eventId, UserID: 8, Role: models.EventRoleCoOrganizer}, nil
+		case 9:
+			return &models.EventMember{EventID: eventId, UserID: 9, Role: models.EventRoleCheckInStaff}, nil
+		default:
+			return nil, models.ErrMemberNotFound
+		}
+	}
+
+	var removed []int64
+	handlers.RemoveEventMember = func(ctx context.Context, member *models.EventMember) error {
+		removed = append(removed, member.UserID)
+		return nil
+	}
+
+	tests := []struct {
+		token  string
+		userId string
+		status int
+	}{
+		{"user-9", "8", http.StatusForbidden},
+		{"user-8", "9", http.StatusOK},
+		{"user-9", "9", http.StatusOK},
+		{"user-1", "8", http.StatusOK},
+		{"user-1", "2", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodDelete, "/api/v1/events/1/members/"+tt.userId, http.NoBody)
+		req.Header.Set("Authorization", tt.token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, tt.token+" removes "+tt.userId)
+	}
+
+	assert.Equal(t, []int64{9, 9, 8}, removed)
+}
+
+func TestTransferEventOwnership_OwnerOnly(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalTransferEventOwnership := handlers.TransferEventOwnership
+	defer func() {
+		handlers.TransferEventOwnership = originalTransferEventOwnership
+	}()
+
+	handlers.TransferEventOwnership = func(ctx context.Context, event *models.Event, userId int64) error {
+		event.UserID = userId
+		event.Version++
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/transfer", strings.NewReader(`{"userId":8}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-8")
+	req.Header.Set("If-Match", `"2"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/events/1/transfer", strings.NewReader(`{"userId":8}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-1")
+	req.Header.Set("If-Match", `"2"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	data := response["data"].(map[string]any)
+	assert.Equal(t, float64(8), data["userId"])
+}
+
+func TestGetEventRegistrations_CheckInStaff(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalGetEventRegistrations := handlers.GetEventRegistrations
+	defer func() {
+		handlers.GetEventRegistrations = originalGetEventRegistrations
+	}()
+
+	handlers.GetEventRegistrations = func(ctx context.Context, event *models.Event, occurrence *time.Time) ([]models.Registration, error) {
+		return []models.Registration{{ID: 1, UserID: 4, Email: "attendee@example.com"}}, nil
+	}
+
+	for token, expected := range map[string]int{
+		"user-9": http.StatusOK,
+		"user-2": http.StatusForbidden,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/1/registrations", http.NoBody)
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+}
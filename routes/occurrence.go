@@ -0,0 +1,172 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// OccurrenceRequest overrides a single occurrence of a recurring event, omitted fields keep the series value
+type OccurrenceRequest struct {
+	Name        *string    `json:"name" binding:"omitempty,notblank,min=3,max=100"`
+	Description *string    `json:"description" binding:"omitempty,notblank,max=2000"`
+	Location    *string    `json:"location" binding:"omitempty,notblank,max=200"`
+	DateTime    *time.Time `json:"dateTime"`
+	Cancelled   bool       `json:"cancelled"`
+}
+
+type OccurrenceResponse struct {
+	EventID         int64      `json:"eventId"`
+	OccurrenceStart time.Time  `json:"occurrenceStart"`
+	Name            *string    `json:"name,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	Location        *string    `json:"location,omitempty"`
+	DateTime        *time.Time `json:"dateTime,omitempty"`
+	Cancelled       bool       `json:"cancelled"`
+}
+
+// toOverride copies the request into a model with surrounding whitespace trimmed
+func (r OccurrenceRequest) toOverride(start time.Time) models.OccurrenceOverride {
+	return models.OccurrenceOverride{
+		OccurrenceStart: start,
+		Name:            trimmed(r.Name),
+		Description:     trimmed(r.Description),
+		Location:        trimmed(r.Location),
+		DateTime:        r.DateTime,
+		Cancelled:       r.Cancelled,
+	}
+}
+
+func trimmed(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	t := strings.TrimSpace(*s)
+	return &t
+}
+
+func newOccurrenceResponse(o models.OccurrenceOverride) OccurrenceResponse {
+	return OccurrenceResponse{
+		EventID:         o.EventID,
+		OccurrenceStart: o.OccurrenceStart,
+		Name:            o.Name,
+		Description:     o.Description,
+		Location:        o.Location,
+		DateTime:        o.DateTime,
+		Cancelled:       o.Cancelled,
+	}
+}
+
+// getOccurrences lists the occurrences of one event within ?from=&to=, overrides applied
+func getOccurrences(context *gin.Context) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	from, to, err := parseOccurrenceWindow(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if from.IsZero() {
+		problems.Abort(context, models.NewValidationError("from", "is required"))
+		return
+	}
+
+	event, err := findEvent(context, eventId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	occurrences, err := handlers.GetOccurrences(context.Request.Context(), event, from, to)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of occurrences",
+		"data":    newEventResponses(occurrences),
+	})
+}
+
+func saveOccurrence(context *gin.Context) {
+	event, start, err := findEditableOccurrence(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	var request OccurrenceRequest
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		problems.Abort(context, problems.BindError(err))
+		return
+	}
+
+	override := request.toOverride(start)
+
+	err = handlers.SaveOccurrenceOverride(context.Request.Context(), event, &override)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Occurrence updated successfully",
+		"data":    newOccurrenceResponse(override),
+	})
+}
+
+func deleteOccurrence(context *gin.Context) {
+	event, start, err := findEditableOccurrence(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = handlers.DeleteOccurrenceOverride(context.Request.Context(), event, start)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Occurrence reset successfully",
+	})
+}
+
+// findEditableOccurrence loads the event and the :occurrenceStart parameter, only its owner and co-organizers may change occurrences
+func findEditableOccurrence(context *gin.Context) (*models.Event, time.Time, error) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	start, err := time.Parse(time.RFC3339, context.Param("occurrenceStart"))
+	if err != nil {
+		return nil, time.Time{}, models.NewValidationError("occurrenceStart", "must be an RFC 3339 date-time")
+	}
+
+	event, err := findEvent(context, eventId)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	err = requireEventPermission(context, event, models.PermissionEdit)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return event, start, nil
+}
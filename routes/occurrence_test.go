@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEvents_ValidatesWindow(t *testing.T) {
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() { handlers.GetAllEvents = originalGetAllEvents }()
+
+	var filter models.EventFilter
+	handlers.GetAllEvents = func(ctx context.Context, f models.EventFilter) ([]models.Event, error) {
+		filter = f
+		return []models.Event{}, nil
+	}
+
+	router := setupRegisteredRouter()
+
+	for query, expected := range map[string]int{
+		"": http.StatusOK,
+		"?from=2031-01-01T00:00:00Z&to=2031-02-01T00:00:00Z": http.StatusOK,
+		"?from=2031-01-01T00:00:00Z":                         http.StatusBadRequest,
+		"?from=2031-02-01T00:00:00Z&to=2031-01-01T00:00:00Z": http.StatusBadRequest,
+		"?from=2031-01-01T00:00:00Z&to=2033-01-01T00:00:00Z": http.StatusBadRequest,
+		"?from=tomorrow&to=2031-01-01T00:00:00Z":             http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, query)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events?from=2031-01-01T00:00:00Z&to=2031-02-01T00:00:00Z", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC), filter.From.UTC())
+	assert.Equal(t, time.Date(2031, 2, 1, 0, 0, 0, 0, time.UTC), filter.To.UTC())
+}
+
+func TestSaveOccurrence_OwnerOnly(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalSave := handlers.SaveOccurrenceOverride
+	defer func() { handlers.SaveOccurrenceOverride = originalSave }()
+
+	var saved models.OccurrenceOverride
+	handlers.SaveOccurrenceOverride = func(ctx context.Context, event *models.Event, o *models.OccurrenceOverride) error {
+		o.EventID = event.ID
+		saved = *o
+		return nil
+	}
+
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"user-2": http.StatusForbidden,
+		"user-1": http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1/occurrences/2030-12-23T09:00:00Z", strings.NewReader(`{"location":" Bandung ","cancelled":true}`))
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+
+	assert.Equal(t, time.Date(2030, 12, 23, 9, 0, 0, 0, time.UTC), saved.OccurrenceStart.UTC())
+	assert.Equal(t, "Bandung", *saved.Location)
+	assert.Nil(t, saved.Name)
+	assert.True(t, saved.Cancelled)
+}
+
+func TestSaveOccurrence_ErrorInvalidStart(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1/occurrences/next-week", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteOccurrence_ErrorNoOverride(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalDelete := handlers.DeleteOccurrenceOverride
+	defer func() { handlers.DeleteOccurrenceOverride = originalDelete }()
+
+	handlers.DeleteOccurrenceOverride = func(ctx context.Context, event *models.Event, start time.Time) error {
+		return models.ErrOccurrenceNotFound
+	}
+
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/events/1/occurrences/2030-12-23T09:00:00Z", nil)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "occurrence_not_found", response["code"])
+}
+
+func TestRegisterEvent_Occurrence(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalRegister := handlers.RegisterEvent
+	defer func() { handlers.RegisterEvent = originalRegister }()
+
+	var occurrences []*time.Time
+	handlers.RegisterEvent = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time) error {
+		occurrences = append(occurrences, occurrence)
+		return nil
+	}
+
+	router := setupRegisteredRouter()
+
+	for _, body := range []string{"", `{"occurrenceStart":"2030-12-23T09:00:00Z"}`} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/registrations", strings.NewReader(body))
+		req.Header.Set("Authorization", "user-2")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code, body)
+	}
+
+	if assert.Len(t, occurrences, 2) {
+		// no body registers to the whole series
+		assert.Nil(t, occurrences[0])
+		assert.Equal(t, time.Date(2030, 12, 23, 9, 0, 0, 0, time.UTC), occurrences[1].UTC())
+	}
+}
@@ -0,0 +1,336 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationRequest is the body accepted when creating or updating an organization
+type OrganizationRequest struct {
+	Slug     string           `json:"slug" binding:"required,slug,max=50"`
+	Name     string           `json:"name" binding:"required,notblank,max=100"`
+	Branding *BrandingRequest `json:"branding,omitempty"`
+
+	// DefaultTimeZone is an IANA name given to new events that do not name a time zone
+	DefaultTimeZone string `json:"defaultTimeZone,omitempty" binding:"omitempty,max=64"`
+}
+
+type BrandingRequest struct {
+	LogoURL      string `json:"logoUrl,omitempty" binding:"omitempty,url,max=500"`
+	PrimaryColor string `json:"primaryColor,omitempty" binding:"omitempty,hexcolor"`
+}
+
+// OrganizationMemberRequest adds a user, by the email they signed up with, to an organization
+type OrganizationMemberRequest struct {
+	Email string                  `json:"email" binding:"required,email"`
+	Role  models.OrganizationRole `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+type OrganizationResponse struct {
+	ID              int64            `json:"id"`
+	Slug            string           `json:"slug"`
+	Name            string           `json:"name"`
+	Branding        BrandingResponse `json:"branding"`
+	DefaultTimeZone string           `json:"defaultTimeZone,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+}
+
+type BrandingResponse struct {
+	LogoURL      string `json:"logoUrl,omitempty"`
+	PrimaryColor string `json:"primaryColor,omitempty"`
+}
+
+type OrganizationMemberResponse struct {
+	UserID    int64                   `json:"userId"`
+	Email     string                  `json:"email"`
+	Role      models.OrganizationRole `json:"role"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+func (r OrganizationRequest) toOrganization() models.Organization {
+	organization := models.Organization{
+		Slug:            r.Slug,
+		Name:            strings.TrimSpace(r.Name),
+		DefaultTimeZone: r.DefaultTimeZone,
+	}
+
+	if r.Branding != nil {
+		organization.Branding = models.Branding{
+			LogoURL:      r.Branding.LogoURL,
+			PrimaryColor: strings.ToUpper(r.Branding.PrimaryColor),
+		}
+	}
+
+	return organization
+}
+
+func newOrganizationResponse(o models.Organization) OrganizationResponse {
+	return OrganizationResponse{
+		ID:   o.ID,
+		Slug: o.Slug,
+		Name: o.Name,
+		Branding: BrandingResponse{
+			LogoURL:      o.Branding.LogoURL,
+			PrimaryColor: o.Branding.PrimaryColor,
+		},
+		DefaultTimeZone: o.DefaultTimeZone,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+	}
+}
+
+func newOrganizationMemberResponse(m models.OrganizationMember) OrganizationMemberResponse {
+	return OrganizationMemberResponse{
+		UserID:    m.UserID,
+		Email:     m.Email,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func bindOrganizationRequest(context *gin.Context) (OrganizationRequest, error) {
+	var request OrganizationRequest
+
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		return request, problems.BindError(err)
+	}
+
+	if request.DefaultTimeZone != "" {
+		_, err = models.LoadTimeZone(request.DefaultTimeZone)
+		if err != nil {
+			return request, models.NewValidationError("defaultTimeZone", "must be an IANA time zone name such as Asia/Jakarta")
+		}
+	}
+
+	return request, nil
+}
+
+func getOrganizations(context *gin.Context) {
+	organizations, err := handlers.GetAllOrganizations(context.Request.Context())
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]OrganizationResponse, 0, len(organizations))
+	for _, o := range organizations {
+		responses = append(responses, newOrganizationResponse(o))
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of organizations",
+		"data":    responses,
+	})
+}
+
+func getOrganization(context *gin.Context) {
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Successfully get organization details",
+		"data":    newOrganizationResponse(*currentOrganization(context)),
+	})
+}
+
+// createOrganization makes the current user the first owner of the new organization
+func createOrganization(context *gin.Context) {
+	request, err := bindOrganizationRequest(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	organization := request.toOrganization()
+
+	err = handlers.CreateOrganization(context.Request.Context(), &organization, context.GetInt64("userId"))
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Organization created successfully",
+		"data":    newOrganizationResponse(organization),
+	})
+}
+
+// updateOrganization changes the name and settings, owners and admins only
+func updateOrganization(context *gin.Context) {
+	request, err := bindOrganizationRequest(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	organization, role, err := organizationWithRole(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if !role.CanAdminister() {
+		problems.Abort(context, models.ErrRoleNotPermitted)
+		return
+	}
+
+	updatedOrganization := request.toOrganization()
+	updatedOrganization.ID = organization.ID
+
+	err = handlers.UpdateOrganization(context.Request.Context(), &updatedOrganization)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Organization updated successfully",
+		"data":    newOrganizationResponse(updatedOrganization),
+	})
+}
+
+func getOrganizationMembers(context *gin.Context) {
+	organization, _, err := organizationWithRole(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	members, err := handlers.GetOrganizationMembers(context.Request.Context(), organization.ID)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]OrganizationMemberResponse, 0, len(members))
+	for _, m := range members {
+		responses = append(responses, newOrganizationMemberResponse(m))
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of organization members",
+		"data":    responses,
+	})
+}
+
+// addOrganizationMember adds an owner (owners only), an admin or a member (owners and admins)
+func addOrganizationMember(context *gin.Context) {
+	var request OrganizationMemberRequest
+
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		problems.Abort(context, problems.BindError(err))
+		return
+	}
+
+	organization, role, err := organizationWithRole(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if !role.CanManage(request.Role) {
+		problems.Abort(context, models.ErrRoleNotPermitted)
+		return
+	}
+
+	member, err := handlers.AddOrganizationMember(context.Request.Context(), organization, request.Email, request.Role)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Member added successfully",
+		"data":    newOrganizationMemberResponse(*member),
+	})
+}
+
+// removeOrganizationMember removes a member the caller manages, any member can leave on their own
+// unless they are the last owner
+func removeOrganizationMember(context *gin.Context) {
+	userId, err := strconv.ParseInt(context.Param("userId"), 10, 64)
+	if err != nil || userId <= 0 {
+		problems.Abort(context, models.NewValidationError("userId", "must be a positive integer"))
+		return
+	}
+
+	organization, role, err := organizationWithRole(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	member, err := handlers.GetOrganizationMember(context.Request.Context(), organization.ID, userId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if userId != context.GetInt64("userId") && !role.CanManage(member.Role) {
+		problems.Abort(context, models.ErrRoleNotPermitted)
+		return
+	}
+
+	err = handlers.RemoveOrganizationMember(context.Request.Context(), member)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Member removed successfully",
+	})
+}
+
+// currentOrganization is the organization the request is scoped to (see middlewares.Tenant), nil for none
+func currentOrganization(context *gin.Context) *models.Organization {
+	organization, _ := context.Get("organization")
+	o, _ := organization.(*models.Organization)
+	return o
+}
+
+// organizationWithRole returns the current organization and the current user's role in it,
+// ErrNotOrganizationMember when the user has none
+func organizationWithRole(context *gin.Context) (*models.Organization, models.OrganizationRole, error) {
+	organization := currentOrganization(context)
+
+	role, err := handlers.GetOrganizationRole(context.Request.Context(), organization.ID, context.GetInt64("userId"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if role == "" {
+		return nil, "", models.ErrNotOrganizationMember
+	}
+
+	return organization, role, nil
+}
+
+// requireOrganizationMember lets anyone create events outside any organization,
+// and only its members create events in an organization
+func requireOrganizationMember(context *gin.Context) error {
+	if currentOrganization(context) == nil {
+		return nil
+	}
+
+	_, _, err := organizationWithRole(context)
+
+	return err
+}
+
+// organizationTimeZone is the default time zone of the current organization, empty for none
+func organizationTimeZone(context *gin.Context) string {
+	organization := currentOrganization(context)
+	if organization == nil {
+		return ""
+	}
+
+	return organization.DefaultTimeZone
+}
@@ -0,0 +1,179 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/middlewares"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks the "acme" organization, users missing from roles are not its members
+func mockOrganization(t *testing.T, roles map[int64]models.OrganizationRole) {
+	originalGetOrganizationBySlug := handlers.GetOrganizationBySlug
+	originalGetOrganizationRole := handlers.GetOrganizationRole
+	t.Cleanup(func() {
+		handlers.GetOrganizationBySlug = originalGetOrganizationBySlug
+		handlers.GetOrganizationRole = originalGetOrganizationRole
+	})
+
+	handlers.GetOrganizationBySlug = func(ctx context.Context, slug string) (*models.Organization, error) {
+		if slug != "acme" {
+			return nil, models.ErrOrganizationNotFound
+		}
+		return &models.Organization{ID: 7, Slug: slug, Name: "Acme", DefaultTimeZone: "Asia/Jakarta", CreatedAt: time.Now(), UpdatedAt: time.Now()}, nil
+	}
+
+	handlers.GetOrganizationRole = func(ctx context.Context, organizationId, userId int64) (models.OrganizationRole, error) {
+		return roles[userId], nil
+	}
+}
+
+func TestGetOrganization_NotFound(t *testing.T) {
+	mockOrganization(t, nil)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/organizations/nobody", http.NoBody)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"organization_not_found"`)
+}
+
+func TestUpdateOrganization_ByRole(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	mockOrganization(t, map[int64]models.OrganizationRole{1: models.OrganizationRoleOwner, 2: models.OrganizationRoleMember})
+	router := setupRegisteredRouter()
+
+	originalUpdateOrganization := handlers.UpdateOrganization
+	defer func() {
+		handlers.UpdateOrganization = originalUpdateOrganization
+	}()
+
+	handlers.UpdateOrganization = func(ctx context.Context, organization *models.Organization) error {
+		return nil
+	}
+
+	for token, expected := range map[string]string{
+		"user-1": "",
+		"user-2": "role_not_permitted",
+		"user-3": "not_organization_member",
+	} {
+		body := `{"slug":"acme","name":"Acme Events","branding":{"primaryColor":"#00add8"}}`
+		req, _ := http.NewRequest(http.MethodPut, "/api/v1/organizations/acme", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if expected == "" {
+			assert.Equal(t, http.StatusOK, w.Code, token)
+			assert.Contains(t, w.Body.String(), `"primaryColor":"#00ADD8"`)
+			continue
+		}
+
+		assert.Equal(t, http.StatusForbidden, w.Code, token)
+		assert.Contains(t, w.Body.String(), `"code":"`+expected+`"`, token)
+	}
+}
+
+func TestCreateOrganization_ErrorValidation(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	body := `{"slug":"Not A Slug","name":"Acme","branding":{"logoUrl":"logo.png","primaryColor":"blue"},"defaultTimeZone":"Mars/Olympus"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/organizations", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"slug"`)
+	assert.Contains(t, w.Body.String(), `"field":"logoUrl"`)
+	assert.Contains(t, w.Body.String(), `"field":"primaryColor"`)
+}
+
+func TestCreateEvent_InOrganization(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	mockOrganization(t, map[int64]models.OrganizationRole{1: models.OrganizationRoleMember})
+	router := setupRegisteredRouter()
+
+	originalCreateEvent := handlers.CreateEvent
+	defer func() {
+		handlers.CreateEvent = originalCreateEvent
+	}()
+
+	var tenant int64
+	var timeZone string
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
+		tenant = models.TenantFrom(ctx)
+		timeZone = event.TimeZone
+		return nil
+	}
+
+	for token, expected := range map[string]int{
+		"user-1": http.StatusCreated,
+		"user-2": http.StatusForbidden,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events", toJSON(t, createEventPayload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", token)
+		req.Header.Set(middlewares.OrganizationHeader, "acme")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+
+	// Created in the organization, in its default time zone
+	assert.Equal(t, int64(7), tenant)
+	assert.Equal(t, "Asia/Jakarta", timeZone)
+}
+
+func TestRegisterRoutes_LegacyTenant(t *testing.T) {
+	mockOrganization(t, nil)
+	router := setupRegisteredRouter()
+
+	originalGetEventByID := handlers.GetEventByID
+	t.Cleanup(func() {
+		handlers.GetEventByID = originalGetEventByID
+	})
+
+	var tenant int64
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		tenant = models.TenantFrom(ctx)
+		return nil, models.ErrEventNotFound
+	}
+
+	for organization, expected := range map[string]int64{"": 0, "acme": 7} {
+		req, _ := http.NewRequest(http.MethodGet, "/event/1", http.NoBody)
+		req.Header.Set("X-Organization", organization)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, organization)
+		assert.Equal(t, expected, tenant, organization)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/event/1", http.NoBody)
+	req.Header.Set("X-Organization", "nobody")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"organization_not_found"`)
+}
@@ -0,0 +1,267 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// patchEvent partially updates an event with a JSON Merge Patch (RFC 7396)
+// or a JSON Patch (RFC 6902), depending on the Content-Type
+func patchEvent(context *gin.Context) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	body, err := context.GetRawData()
+	if err != nil {
+		problems.Abort(context, fmt.Errorf("%w: %v", problems.ErrInvalidRequestBody, err))
+		return
+	}
+
+	// Check if the event exists
+	event, err := findEvent(context, eventId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Event can be updated by its owner and co-organizers
+	err = requireEventPermission(context, event, models.PermissionEdit)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Reject if the patch was made against an older version
+	err = checkIfMatch(context, *event)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	request, err := applyEventPatch(context.ContentType(), *event, body)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Moving the start keeps the duration, unless the patch sets the end too
+	dateTimeChanged := !request.DateTime.Equal(event.DateTime)
+	if dateTimeChanged && request.EndDateTime != nil && request.EndDateTime.Equal(event.End()) {
+		end := request.DateTime.Add(event.Duration())
+		request.EndDateTime = &end
+	}
+
+	// Same rules as create, the date only has to be in the future when it changes
+	err = checkEventRequest(request, binding.Validator.ValidateStruct(&request), dateTimeChanged)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	patchedEvent := request.toEvent()
+
+	err = applyVenue(context, &patchedEvent)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = applyCategory(context, &patchedEvent)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	patchedEvent.ID = event.ID
+	patchedEvent.UserID = event.UserID
+	patchedEvent.Version = event.Version
+	patchedEvent.UpdatedAt = event.UpdatedAt
+	keepStoredEventFields(&patchedEvent, *event)
+
+	// Only write columns whose value actually changed
+	columns := changedEventColumns(*event, patchedEvent)
+
+	// A new time or venue is checked against other bookings when the event is written
+	err = allowVenueConflicts(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if len(columns) > 0 {
+		err = handlers.PatchEvent(context.Request.Context(), &patchedEvent, columns)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+	}
+
+	context.Header("ETag", eventETag(patchedEvent))
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Event updated successfully",
+		"data":    newEventResponse(patchedEvent),
+	})
+}
+
+// applyEventPatch applies the patch document to the editable fields of event
+func applyEventPatch(contentType string, event models.Event, patch []byte) (EventRequest, error) {
+	var request EventRequest
+
+	original, err := json.Marshal(newEventRequest(event))
+	if err != nil {
+		return request, err
+	}
+
+	var patched []byte
+	switch contentType {
+	case mergePatchContentType:
+		patched, err = jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return request, fmt.Errorf("%w: %v", problems.ErrInvalidRequestBody, err)
+		}
+	case jsonPatchContentType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return request, fmt.Errorf("%w: %v", problems.ErrInvalidRequestBody, err)
+		}
+
+		patched, err = operations.Apply(original)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return request, fmt.Errorf("%w: %v", problems.ErrPatchTestFailed, err)
+		}
+		if err != nil {
+			return request, fmt.Errorf("%w: %v", problems.ErrInvalidPatch, err)
+		}
+	default:
+		return request, fmt.Errorf("%w: use %s or %s", problems.ErrUnsupportedMediaType, mergePatchContentType, jsonPatchContentType)
+	}
+
+	patched, err = dropStoredEnd(original, patched)
+	if err != nil {
+		return request, problems.BindError(err)
+	}
+
+	// Reject fields that are not editable (id, userId, typos...)
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(&request)
+	if err != nil {
+		return request, problems.BindError(err)
+	}
+
+	return request, nil
+}
+
+// dropStoredEnd removes the stored endDateTime from a patched document that sets durationMinutes instead.
+// The document the patch applies to always carries endDateTime and never durationMinutes, and a request
+// cannot send both. A patch setting a new endDateTime as well is still rejected.
+func dropStoredEnd(original, patched []byte) ([]byte, error) {
+	var before, after map[string]json.RawMessage
+	err := json.Unmarshal(original, &before)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(patched, &after)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, hasDuration := after["durationMinutes"]
+	if !hasDuration || string(duration) == "null" || !bytes.Equal(before["endDateTime"], after["endDateTime"]) {
+		return patched, nil
+	}
+
+	delete(after, "endDateTime")
+
+	return json.Marshal(after)
+}
+
+// changedEventColumns lists the database columns that differ between two versions of an event
+func changedEventColumns(before, after models.Event) []string {
+	columns := []string{}
+
+	if before.Name != after.Name {
+		columns = append(columns, "name")
+	}
+	if before.Description != after.Description {
+		columns = append(columns, "description")
+	}
+	if before.Location != after.Location {
+		columns = append(columns, "location")
+	}
+	if !before.DateTime.Equal(after.DateTime) {
+		columns = append(columns, "datetime")
+	}
+	if !before.End().Equal(after.End()) {
+		columns = append(columns, "end_datetime")
+	}
+	if before.TimeZoneName() != after.TimeZoneName() {
+		columns = append(columns, "time_zone")
+	}
+	if !before.Recurrence.Equal(after.Recurrence) {
+		columns = append(columns, "recurrence_rule", "recurrence_exdates")
+	}
+	if !equalPointer(before.Latitude, after.Latitude) || !equalPointer(before.Longitude, after.Longitude) {
+		columns = append(columns, "latitude", "longitude")
+	}
+	if before.Address != after.Address {
+		columns = append(columns, "address_street", "address_city", "address_region", "address_postal_code", "address_country")
+	}
+	if !equalPointer(before.VenueID, after.VenueID) {
+		columns = append(columns, "venue_id")
+	}
+	if !equalPointer(before.Capacity, after.Capacity) {
+		columns = append(columns, "capacity")
+	}
+	if !equalPointer(before.CategoryID, after.CategoryID) {
+		columns = append(columns, "category_id")
+	}
+	if !slices.Equal(before.Tags, after.Tags) {
+		columns = append(columns, "tags")
+	}
+	if before.VisibilityName() != after.VisibilityName() {
+		columns = append(columns, "visibility")
+	}
+
+	return columns
+}
+
+// keepStoredEventFields copies the fields a write does not edit from the stored event,
+// so the response shows them like a GET would
+func keepStoredEventFields(event *models.Event, stored models.Event) {
+	event.Status = stored.Status
+	event.CancellationReason = stored.CancellationReason
+	event.OrganizationID = stored.OrganizationID
+	event.ExternalUID = stored.ExternalUID
+	event.DeletedAt = stored.DeletedAt
+}
+
+// equalPointer compares optional values, both missing being equal
+func equalPointer[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,170 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks token verification, event lookup and the patch handler,
+// returns a pointer to the columns written by handlers.PatchEvent
+func mockPatchEvent(t *testing.T, ownerId int64) *[]string {
+	originalVerifyToken := utils.VerifyToken
+	originalGetEventByID := handlers.GetEventByID
+	originalPatchEvent := handlers.PatchEvent
+	t.Cleanup(func() {
+		utils.VerifyToken = originalVerifyToken
+		handlers.GetEventByID = originalGetEventByID
+		handlers.PatchEvent = originalPatchEvent
+	})
+
+	// Users other than ownerId are not members of the event
+	mockEventRoles(t, nil)
+
+	utils.VerifyToken = func(token string) (int64, error) {
+		return 1, nil
+	}
+
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &models.Event{
+				ID:          1,
+				Name:        "Go Workshop Jakarta",
+				Description: "A beginner-friendly workshop covering Go fundamentals and best practices.",
+				Location:    "Jakarta",
+				DateTime:    time.Date(2025, 12, 16, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+				UserID:      ownerId,
+			},
+			nil
+	}
+
+	columns := []string{}
+	handlers.PatchEvent = func(ctx context.Context, event *models.Event, changed []string) error {
+		columns = changed
+		return nil
+	}
+
+	return &columns
+}
+
+func sendPatch(contentType, body string) *httptest.ResponseRecorder {
+	router := setupRouter()
+
+	req, _ := http.NewRequest(
+		http.MethodPatch,
+		strings.Replace(PATCH_EVENT_PATH, ":eventId", "1", 1),
+		bytes.NewBufferString(body),
+	)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "test-token")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestPatchEvent_MergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		status  int
+		code    string
+		columns []string
+	}{
+		{"change one field", `{"location": "Bandung"}`, http.StatusOK, "", []string{"location"}},
+		{"change several fields", `{"name": "Go Workshop Bandung", "dateTime": "2030-01-01T09:00:00+07:00"}`, http.StatusOK, "", []string{"name", "datetime", "end_datetime"}},
+		{"same value writes nothing", `{"location": "Jakarta"}`, http.StatusOK, "", []string{}},
+		{"change time zone", `{"timeZone": "Asia/Jakarta"}`, http.StatusOK, "", []string{"time_zone"}},
+		{"duration replaces the stored end", `{"durationMinutes": 90}`, http.StatusOK, "", []string{"end_datetime"}},
+		{"duration and end", `{"durationMinutes": 90, "endDateTime": "2025-12-16T12:00:00+07:00"}`, http.StatusBadRequest, "validation_failed", []string{}},
+		{"end before start", `{"endDateTime": "2025-12-16T08:00:00+07:00"}`, http.StatusBadRequest, "validation_failed", []string{}},
+		{"null removes required field", `{"description": null}`, http.StatusBadRequest, "validation_failed", []string{}},
+		{"new date in the past", `{"dateTime": "2020-01-01T09:00:00+07:00"}`, http.StatusBadRequest, "validation_failed", []string{}},
+		{"id is not editable", `{"id": 5}`, http.StatusBadRequest, "invalid_request_body", []string{}},
+		{"malformed patch", `not json`, http.StatusBadRequest, "invalid_request_body", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := mockPatchEvent(t, 1)
+
+			w := sendPatch(mergePatchContentType, tt.patch)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.columns, *columns)
+
+			if tt.code != "" {
+				var response map[string]any
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.code, response["code"])
+			}
+		})
+	}
+}
+
+func TestPatchEvent_JSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		status  int
+		code    string
+		columns []string
+	}{
+		{"replace", `[{"op": "replace", "path": "/name", "value": "Go Workshop Bandung"}]`, http.StatusOK, "", []string{"name"}},
+		{"add", `[{"op": "add", "path": "/location", "value": "Bandung"}]`, http.StatusOK, "", []string{"location"}},
+		{"remove", `[{"op": "remove", "path": "/description"}]`, http.StatusBadRequest, "validation_failed", []string{}},
+		{"copy", `[{"op": "copy", "from": "/name", "path": "/description"}]`, http.StatusOK, "", []string{"description"}},
+		{"move", `[{"op": "move", "from": "/location", "path": "/description"}]`, http.StatusBadRequest, "validation_failed", []string{}},
+		{"add duration", `[{"op": "add", "path": "/durationMinutes", "value": 90}]`, http.StatusOK, "", []string{"end_datetime"}},
+		{"test passes", `[{"op": "test", "path": "/location", "value": "Jakarta"}, {"op": "replace", "path": "/location", "value": "Bandung"}]`, http.StatusOK, "", []string{"location"}},
+		{"test fails", `[{"op": "test", "path": "/location", "value": "Surabaya"}, {"op": "replace", "path": "/location", "value": "Bandung"}]`, http.StatusConflict, "patch_test_failed", []string{}},
+		{"path does not exist", `[{"op": "replace", "path": "/capacity", "value": 10}]`, http.StatusUnprocessableEntity, "invalid_patch", []string{}},
+		{"user id is not editable", `[{"op": "add", "path": "/userId", "value": 2}]`, http.StatusBadRequest, "invalid_request_body", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := mockPatchEvent(t, 1)
+
+			w := sendPatch(jsonPatchContentType, tt.patch)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.columns, *columns)
+
+			if tt.code != "" {
+				var response map[string]any
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.code, response["code"])
+			}
+		})
+	}
+}
+
+func TestPatchEvent_ErrorUnsupportedMediaType(t *testing.T) {
+	mockPatchEvent(t, 1)
+
+	w := sendPatch("application/json", `{"location": "Bandung"}`)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestPatchEvent_ErrorNotOwner(t *testing.T) {
+	columns := mockPatchEvent(t, 2)
+
+	w := sendPatch(mergePatchContentType, `{"location": "Bandung"}`)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, *columns)
+}
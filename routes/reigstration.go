@@ -2,39 +2,50 @@ package routes
 
 import (
 	"net/http"
-	"strconv"
+	"time"
 
+	"example.com/event/handlers"
 	"example.com/event/models"
+	"example.com/event/problems"
 	"github.com/gin-gonic/gin"
 )
 
+// RegistrationRequest is the optional body of a registration.
+// OccurrenceStart picks one occurrence of a recurring event, without it the user registers to the whole series.
+type RegistrationRequest struct {
+	OccurrenceStart *time.Time `json:"occurrenceStart"`
+}
+
 func registerEvent(context *gin.Context) {
 	userId := context.GetInt64("userId")
 
-	eventId, err := strconv.ParseInt(context.Param("eventId"), 10, 64)
+	eventId, err := parseEventId(context)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Could not parse event id",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	event, err := models.GetEventByID(eventId)
+	var request RegistrationRequest
+
+	// The body is optional
+	if context.Request.ContentLength != 0 {
+		err = context.ShouldBindJSON(&request)
+		if err != nil {
+			problems.Abort(context, problems.BindError(err))
+			return
+		}
+	}
+
+	// Users who are not invited to a private event are told so
+	event, err := findEventToJoin(context, eventId)
 	if err != nil {
-		context.JSON(http.StatusNotFound, gin.H{
-			"message": "Event not found",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	err = event.RegisterEvent(userId)
+	err = handlers.RegisterEvent(context.Request.Context(), event, userId, request.OccurrenceStart)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Could not register to event",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
@@ -46,30 +57,33 @@ func registerEvent(context *gin.Context) {
 func unregisterEvent(context *gin.Context) {
 	userId := context.GetInt64("userId")
 
-	eventId, err := strconv.ParseInt(context.Param("eventId"), 10, 64)
+	eventId, err := parseEventId(context)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Could not parse event id",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	event, err := models.GetEventByID(eventId)
+	// ?occurrence_start= removes a single occurrence registration, without it every registration to the event goes
+	var occurrence *time.Time
+	if value := context.Query("occurrence_start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			problems.Abort(context, models.NewValidationError("occurrence_start", "must be an RFC 3339 date-time"))
+			return
+		}
+
+		occurrence = &start
+	}
+
+	event, err := findEvent(context, eventId)
 	if err != nil {
-		context.JSON(http.StatusNotFound, gin.H{
-			"message": "Event not found",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	err = event.UnregisterEvent(userId)
+	err = handlers.UnregisterEvent(context.Request.Context(), event, userId, occurrence)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Failed to unregister an event",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
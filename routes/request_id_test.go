@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/event/handlers"
+	"example.com/event/middlewares"
+	"example.com/event/models"
+	"example.com/event/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestId_UsesClientRequestId(t *testing.T) {
+	router := setupRouter()
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		strings.Replace(GET_EVENTS_BY_ID_PATH, ":eventId", "invalidEventId", 1),
+		http.NoBody,
+	)
+	req.Header.Set(middlewares.RequestIDHeader, "test-request-id")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// request id is echoed in header and in the error body
+	assert.Equal(t, "test-request-id", w.Header().Get(middlewares.RequestIDHeader))
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test-request-id", response["requestId"])
+}
+
+func TestRequestId_GeneratesRequestId(t *testing.T) {
+	router := setupRouter()
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		strings.Replace(GET_EVENTS_BY_ID_PATH, ":eventId", "invalidEventId", 1),
+		http.NoBody,
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	requestId := w.Header().Get(middlewares.RequestIDHeader)
+	assert.NotEmpty(t, requestId)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, requestId, response["requestId"])
+}
+
+func TestRequestId_PassedToHandlers(t *testing.T) {
+	router := setupRouter()
+
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() {
+		handlers.GetAllEvents = originalGetAllEvents
+	}()
+
+	// handlers and models log with the request context, so it must carry the id
+	var receivedRequestId string
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+		receivedRequestId = utils.RequestIDFromContext(ctx)
+		return []models.Event{}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, GET_EVENTS_PATH, http.NoBody)
+	req.Header.Set(middlewares.RequestIDHeader, "test-request-id")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "test-request-id", receivedRequestId)
+}
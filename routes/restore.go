@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+func restoreEvent(context *gin.Context) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	event, err := handlers.GetDeletedEventByID(context.Request.Context(), eventId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Restoring takes the same role as deleting, site admins can restore any event
+	err = requireEventPermission(context, event, models.PermissionDelete)
+	if errors.Is(err, models.ErrNotOwner) || errors.Is(err, models.ErrRoleNotPermitted) {
+		admin, adminErr := handlers.IsAdmin(context.Request.Context(), context.GetInt64("userId"))
+		if adminErr != nil {
+			problems.Abort(context, adminErr)
+			return
+		}
+
+		if admin {
+			err = nil
+		}
+	}
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Reject if the client saw an older version
+	err = checkIfMatch(context, *event)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Its venue may have been booked since the event was deleted
+	err = allowVenueConflicts(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = handlers.RestoreEvent(context.Request.Context(), event)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.Header("ETag", eventETag(*event))
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Event restored successfully",
+		"data":    newEventResponse(*event),
+	})
+}
@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks a soft deleted event owned by user 1, user 3 acts as its owner (an admin of its organization),
+// user 8 co-organizes it and user 4 is a site admin
+func mockDeletedEvent(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	mockEventRoles(t, map[int64]models.EventRole{3: models.EventRoleOwner, 8: models.EventRoleCoOrganizer})
+
+	originalGetDeletedEventByID := handlers.GetDeletedEventByID
+	originalRestoreEvent := handlers.RestoreEvent
+	originalIsAdmin := handlers.IsAdmin
+	t.Cleanup(func() {
+		handlers.GetDeletedEventByID = originalGetDeletedEventByID
+		handlers.RestoreEvent = originalRestoreEvent
+		handlers.IsAdmin = originalIsAdmin
+	})
+
+	handlers.GetDeletedEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		deletedAt := time.Now().Add(-time.Hour)
+		return &models.Event{ID: eventId, UserID: 1, Version: 4, Status: models.EventStatusPublished, DeletedAt: &deletedAt}, nil
+	}
+
+	handlers.RestoreEvent = func(ctx context.Context, event *models.Event) error {
+		event.DeletedAt = nil
+		event.Version++
+		return nil
+	}
+
+	handlers.IsAdmin = func(ctx context.Context, userId int64) (bool, error) {
+		return userId == 4, nil
+	}
+}
+
+func TestRestoreEvent(t *testing.T) {
+	mockDeletedEvent(t)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]string{
+		"user-1": "",
+		"user-3": "",
+		"user-4": "",
+		"user-8": "role_not_permitted",
+		"user-2": "not_owner",
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/restore", http.NoBody)
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if expected == "" {
+			assert.Equal(t, http.StatusOK, w.Code, token)
+			assert.Equal(t, `"5"`, w.Header().Get("ETag"), token)
+			continue
+		}
+
+		assert.Equal(t, http.StatusForbidden, w.Code, token)
+		assert.Contains(t, w.Body.String(), expected, token)
+	}
+}
+
+func TestRestoreEvent_ErrorExpired(t *testing.T) {
+	mockDeletedEvent(t)
+	router := setupRegisteredRouter()
+
+	handlers.RestoreEvent = func(ctx context.Context, event *models.Event) error {
+		return models.ErrRestoreExpired
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/restore", http.NoBody)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"restore_expired"`)
+}
@@ -1,46 +1,23 @@
 package routes
 
 import (
-	"example.com/event/middlewares"
+	"net/http"
+
+	"example.com/event/docs"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(server *gin.Engine) {
-	// GET All Events
-	server.GET("/events", getEvents)
-
-	// GET Event by ID
-	server.GET("/event/:eventId", getEventById)
-
-	// POST Create Event
-	server.POST("/event", middlewares.Authenticate, createEvent)
-
-	// PUT Update Event
-	server.PUT("/event/:eventId", middlewares.Authenticate, updateEvent)
-
-	// DELETE Event
-	server.DELETE("/event/:eventId", middlewares.Authenticate, deleteEvent)
-
-	// Register Event
-	server.POST("/event/:eventId/register", registerEvent)
-
-	// Unregister Event
-	server.DELETE("/event/:eventId/unregister", unregisterEvent)
-
-	// Sign Up User
-	server.POST("/user/signup", signUp)
-
-	// Login User
-	server.POST("/user/login", login)
-
-	// TODO: GET Registration List
-
-	// Other way to register protected routes
-	// authenticated := server.Group("/")
-	// authenticated.Use(middlewares.Authenticate)
-	// authenticated.POST("/event", createEvent)
-	// authenticated.PUT("/events/:id", updateEvent)
-	// authenticated.DELETE("/events/:id", deleteEvent)
-	// authenticated.POST("/event/:id/register", registerEvent)
-	// authenticated.DELETE("/event/:id/unregister", unregisterEvent)
+	// Versioned API, a future /api/v2 gets its own register function
+	// reusing the same route handlers where behaviour did not change
+	registerV1Routes(server.Group("/api/v1"))
+
+	// Old unversioned paths, kept as deprecated aliases until their sunset date
+	registerLegacyRoutes(server)
+
+	// OpenAPI document and Swagger UI
+	server.GET("/openapi.json", getOpenAPI)
+	server.GET("/docs", getSwaggerUI)
+	server.StaticFileFS("/docs/swagger-ui.css", "swagger-ui/swagger-ui.css", http.FS(docs.SwaggerUIAssets))
+	server.StaticFileFS("/docs/swagger-ui-bundle.js", "swagger-ui/swagger-ui-bundle.js", http.FS(docs.SwaggerUIAssets))
 }
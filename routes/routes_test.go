@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// creates a router with the real route registration
+func setupRegisteredRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	RegisterRoutes(r)
+
+	return r
+}
+
+func TestRegisterRoutes_V1NotDeprecated(t *testing.T) {
+	router := setupRegisteredRouter()
+	mockRSVPCounts(t)
+
+	originalGetEventByID := handlers.GetEventByID
+	defer func() {
+		handlers.GetEventByID = originalGetEventByID
+	}()
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &models.Event{ID: eventId}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/7", http.NoBody)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+}
+
+func TestRegisterRoutes_LegacyDeprecationHeaders(t *testing.T) {
+	router := setupRegisteredRouter()
+	mockRSVPCounts(t)
+
+	originalGetEventByID := handlers.GetEventByID
+	defer func() {
+		handlers.GetEventByID = originalGetEventByID
+	}()
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &models.Event{ID: eventId}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/event/7", http.NoBody)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// legacy path still works
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, "@1792281600", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Sun, 18 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/events/7>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func TestRegisterRoutes_RegistrationRequiresToken(t *testing.T) {
+	router := setupRegisteredRouter()
+
+	for _, path := range []string{"/api/v1/events/1/registrations", "/event/1/register"} {
+		req, _ := http.NewRequest(http.MethodPost, path, http.NoBody)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+	}
+}
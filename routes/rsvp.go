@@ -0,0 +1,145 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// RSVPRequest is the current user's answer. OccurrenceStart picks one occurrence of a recurring event,
+// without it the answer is for the whole series.
+type RSVPRequest struct {
+	Status          models.RSVPStatus `json:"status" binding:"required,oneof=going maybe declined"`
+	OccurrenceStart *time.Time        `json:"occurrenceStart"`
+}
+
+// CheckInRequest records whether a registered user showed up, going undoes a check-in.
+// It never registers anyone.
+type CheckInRequest struct {
+	Status          models.RSVPStatus `json:"status" binding:"required,oneof=attended no_show going"`
+	OccurrenceStart *time.Time        `json:"occurrenceStart"`
+}
+
+type RSVPChangeResponse struct {
+	UserID          int64             `json:"userId"`
+	Email           string            `json:"email"`
+	OccurrenceStart *time.Time        `json:"occurrenceStart,omitempty"`
+	FromStatus      models.RSVPStatus `json:"fromStatus,omitempty"`
+	ToStatus        models.RSVPStatus `json:"toStatus,omitempty"`
+	ChangedBy       *int64            `json:"changedBy,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+// updateRSVP sets the current user's answer, registering them when they have no registration yet
+func updateRSVP(context *gin.Context) {
+	eventId, err := parseEventId(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	var request RSVPRequest
+
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		problems.Abort(context, problems.BindError(err))
+		return
+	}
+
+	// Users who are not invited to a private event are told so
+	event, err := findEventToJoin(context, eventId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	registration, err := handlers.SetEventRSVP(context.Request.Context(), event, context.GetInt64("userId"), request.OccurrenceStart, request.Status)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "RSVP updated successfully",
+		"data":    newRegistrationResponse(*registration),
+	})
+}
+
+// checkInAttendee records whether a registered user attended, for the owner, co-organizers and check-in staff
+func checkInAttendee(context *gin.Context) {
+	userId, err := strconv.ParseInt(context.Param("userId"), 10, 64)
+	if err != nil || userId <= 0 {
+		problems.Abort(context, models.NewValidationError("userId", "must be a positive integer"))
+		return
+	}
+
+	var request CheckInRequest
+
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		problems.Abort(context, problems.BindError(err))
+		return
+	}
+
+	event, err := findEventWithPermission(context, models.PermissionCheckIn)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	registration, err := handlers.CheckInAttendee(context.Request.Context(), event, userId, request.OccurrenceStart, request.Status)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "RSVP updated successfully",
+		"data":    newRegistrationResponse(*registration),
+	})
+}
+
+// getRSVPHistory lists every RSVP change of the event for the owner and co-organizers, ?user_id= keeps one user's
+func getRSVPHistory(context *gin.Context) {
+	validationErr := &models.ValidationError{}
+	userId := parsePositiveQuery(context, "user_id", validationErr)
+	if len(validationErr.Fields) > 0 {
+		problems.Abort(context, validationErr)
+		return
+	}
+
+	event, err := findEventWithPermission(context, models.PermissionEdit)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	changes, err := handlers.GetRSVPHistory(context.Request.Context(), event, userId)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]RSVPChangeResponse, 0, len(changes))
+	for _, c := range changes {
+		responses = append(responses, RSVPChangeResponse{
+			UserID:          c.UserID,
+			Email:           c.Email,
+			OccurrenceStart: c.OccurrenceStart,
+			FromStatus:      c.FromStatus,
+			ToStatus:        c.ToStatus,
+			ChangedBy:       c.ChangedBy,
+			CreatedAt:       c.CreatedAt,
+		})
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "RSVP history",
+		"data":    responses,
+	})
+}
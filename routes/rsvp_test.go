@@ -0,0 +1,248 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks RSVP counts with one going and one maybe
+func mockRSVPCounts(t *testing.T) {
+	originalGetRSVPCounts := handlers.GetRSVPCounts
+	t.Cleanup(func() {
+		handlers.GetRSVPCounts = originalGetRSVPCounts
+	})
+
+	handlers.GetRSVPCounts = func(ctx context.Context, event *models.Event) (map[models.RSVPStatus]int, error) {
+		counts := map[models.RSVPStatus]int{}
+		for _, status := range models.RSVPStatuses {
+			counts[status] = 0
+		}
+		counts[models.RSVPGoing] = 1
+		counts[models.RSVPMaybe] = 1
+
+		return counts, nil
+	}
+}
+
+// mocks RSVP updates, the registration gets the requested status. It returns the user ids updated.
+func mockSetEventRSVP(t *testing.T) *[]int64 {
+	originalSetEventRSVP := handlers.SetEventRSVP
+	t.Cleanup(func() {
+		handlers.SetEventRSVP = originalSetEventRSVP
+	})
+
+	updated := []int64{}
+	handlers.SetEventRSVP = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time, status models.RSVPStatus) (*models.Registration, error) {
+		updated = append(updated, userId)
+		return &models.Registration{UserID: userId, Email: "user@example.com", OccurrenceStart: occurrence, Status: status}, nil
+	}
+
+	return &updated
+}
+
+// mocks check-in, users in registered get the requested status and the others are not registered.
+// It returns the user ids updated.
+func mockCheckInAttendee(t *testing.T, registered ...int64) *[]int64 {
+	originalCheckInAttendee := handlers.CheckInAttendee
+	t.Cleanup(func() {
+		handlers.CheckInAttendee = originalCheckInAttendee
+	})
+
+	updated := []int64{}
+	handlers.CheckInAttendee = func(ctx context.Context, event *models.Event, userId int64, occurrence *time.Time, status models.RSVPStatus) (*models.Registration, error) {
+		if !slices.Contains(registered, userId) {
+			return nil, models.ErrNotRegistered
+		}
+
+		updated = append(updated, userId)
+		return &models.Registration{UserID: userId, Email: "user@example.com", OccurrenceStart: occurrence, Status: status}, nil
+	}
+
+	return &updated
+}
+
+func TestGetEventById_RSVPCounts(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/1", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data EventResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Data.RSVPCounts[models.RSVPGoing])
+	assert.Equal(t, 1, response.Data.RSVPCounts[models.RSVPMaybe])
+	assert.Contains(t, response.Data.RSVPCounts, models.RSVPNoShow)
+}
+
+func TestUpdateRSVP(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	updated := mockSetEventRSVP(t)
+	router := setupRegisteredRouter()
+
+	for status, expected := range map[string]int{
+		"maybe":    http.StatusOK,
+		"declined": http.StatusOK,
+		"attended": http.StatusBadRequest,
+		"":         http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1/rsvp", strings.NewReader(`{"status":"`+status+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "user-2")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, status)
+		if expected == http.StatusOK {
+			assert.Contains(t, w.Body.String(), `"status":"`+status+`"`)
+		}
+	}
+
+	assert.Equal(t, []int64{2, 2}, *updated)
+}
+
+func TestUpdateRSVP_Legacy(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	mockSetEventRSVP(t)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodPut, "/event/1/rsvp", strings.NewReader(`{"status":"going"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-2")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+}
+
+func TestCheckInAttendee_ByRole(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	updated := mockCheckInAttendee(t, 3)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]string{
+		"user-9": "",
+		"user-8": "",
+		"user-2": "not_owner",
+	} {
+		req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1/registrations/3/rsvp", strings.NewReader(`{"status":"attended"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if expected == "" {
+			assert.Equal(t, http.StatusOK, w.Code, token)
+			continue
+		}
+
+		assert.Equal(t, http.StatusForbidden, w.Code, token)
+		assert.Contains(t, w.Body.String(), expected, token)
+	}
+
+	assert.Equal(t, []int64{3, 3}, *updated)
+}
+
+func TestCheckInAttendee_RejectsAttendeeStatus(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	mockCheckInAttendee(t, 3)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1/registrations/3/rsvp", strings.NewReader(`{"status":"maybe"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-9")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCheckInAttendee_ErrorNotRegistered(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	updated := mockCheckInAttendee(t, 3)
+	router := setupRegisteredRouter()
+
+	// going for a user with no registration must not register them
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1/registrations/777777/rsvp", strings.NewReader(`{"status":"going"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-9")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "not_registered")
+	assert.Empty(t, *updated)
+}
+
+func TestCheckInAttendee_UndoOnceCompleted(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusCompleted)
+	updated := mockCheckInAttendee(t, 3)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1/registrations/3/rsvp", strings.NewReader(`{"status":"going"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-9")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"going"`)
+	assert.Equal(t, []int64{3}, *updated)
+}
+
+func TestGetRSVPHistory_ByRole(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalGetRSVPHistory := handlers.GetRSVPHistory
+	t.Cleanup(func() {
+		handlers.GetRSVPHistory = originalGetRSVPHistory
+	})
+
+	var filtered int64
+	handlers.GetRSVPHistory = func(ctx context.Context, event *models.Event, userId int64) ([]models.RSVPChange, error) {
+		filtered = userId
+		return []models.RSVPChange{
+			{UserID: 3, Email: "user@example.com", ToStatus: models.RSVPGoing, CreatedAt: time.Now()},
+			{UserID: 3, Email: "user@example.com", FromStatus: models.RSVPGoing, ToStatus: models.RSVPDeclined, CreatedAt: time.Now()},
+		}, nil
+	}
+
+	for token, expected := range map[string]int{
+		"user-8": http.StatusOK,
+		"user-9": http.StatusForbidden,
+		"user-2": http.StatusForbidden,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/1/rsvp/history?user_id=3", http.NoBody)
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+
+	assert.Equal(t, int64(3), filtered)
+}
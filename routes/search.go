@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchQuery     = 200
+)
+
+// SearchResultResponse is an event plus the text that matched, as HTML with matches in <mark>
+type SearchResultResponse struct {
+	EventResponse
+	Snippet string `json:"snippet"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+// searchEvents finds events by words of their name, description or location, best matches first
+func searchEvents(context *gin.Context) {
+	filter, err := parseSearchFilter(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	results, total, err := handlers.SearchEvents(context.Request.Context(), filter)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]SearchResultResponse, 0, len(results))
+	for _, r := range results {
+		responses = append(responses, SearchResultResponse{
+			EventResponse: newEventResponse(r.Event),
+			Snippet:       r.Snippet,
+		})
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Search results",
+		"data":    responses,
+		"pagination": PaginationResponse{
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+			Total:  total,
+		},
+	})
+}
+
+// parseSearchFilter reads ?q=&status=&limit=&offset=, reporting every invalid parameter at once
+func parseSearchFilter(context *gin.Context) (models.SearchFilter, error) {
+	validationErr := &models.ValidationError{}
+
+	filter := models.SearchFilter{
+		Query:    context.Query("q"),
+		ViewerID: context.GetInt64("userId"),
+		Limit:    int(parsePositiveQuery(context, "limit", validationErr)),
+	}
+
+	switch {
+	case filter.Query == "":
+		validationErr.Add("q", "is required")
+	case utf8.RuneCountInString(filter.Query) > maxSearchQuery:
+		validationErr.Add("q", "must be at most "+strconv.Itoa(maxSearchQuery)+" characters")
+	case len(models.SearchTerms(filter.Query)) == 0:
+		validationErr.Add("q", "must contain a word")
+	}
+
+	statuses, err := parseStatusFilter(context)
+
+	var statusErr *models.ValidationError
+	if errors.As(err, &statusErr) {
+		validationErr.Fields = append(validationErr.Fields, statusErr.Fields...)
+	}
+	filter.Statuses = statuses
+
+	if filter.Limit > maxSearchLimit {
+		validationErr.Add("limit", "must be at most "+strconv.Itoa(maxSearchLimit))
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultSearchLimit
+	}
+
+	if value := context.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			validationErr.Add("offset", "must be a non-negative integer")
+		}
+		filter.Offset = max(offset, 0)
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return filter, validationErr
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchEvents(t *testing.T) {
+	original := handlers.SearchEvents
+	defer func() { handlers.SearchEvents = original }()
+
+	var filter models.SearchFilter
+	handlers.SearchEvents = func(ctx context.Context, f models.SearchFilter) ([]models.SearchResult, int, error) {
+		filter = f
+		return []models.SearchResult{{
+			Event: models.Event{
+				ID:       3,
+				Name:     "Go Workshop Jakarta",
+				DateTime: time.Date(2030, 12, 16, 2, 0, 0, 0, time.UTC),
+				Status:   models.EventStatusPublished,
+			},
+			Snippet: "<mark>Go</mark> Workshop Jakarta",
+		}}, 41, nil
+	}
+
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/search?q=go+work&limit=10&offset=20", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.SearchFilter{Query: "go work", Limit: 10, Offset: 20}, filter)
+
+	var response struct {
+		Data       []map[string]any   `json:"data"`
+		Pagination PaginationResponse `json:"pagination"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, PaginationResponse{Limit: 10, Offset: 20, Total: 41}, response.Pagination)
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, float64(3), response.Data[0]["id"])
+		assert.Equal(t, "Go Workshop Jakarta", response.Data[0]["name"])
+		assert.Equal(t, "<mark>Go</mark> Workshop Jakarta", response.Data[0]["snippet"])
+	}
+
+	// defaults
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/events/search?q=go", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, defaultSearchLimit, filter.Limit)
+	assert.Zero(t, filter.Offset)
+}
+
+func TestSearchEvents_ErrorInvalidQuery(t *testing.T) {
+	original := handlers.SearchEvents
+	defer func() { handlers.SearchEvents = original }()
+
+	handlers.SearchEvents = func(ctx context.Context, f models.SearchFilter) ([]models.SearchResult, int, error) {
+		t.Error("invalid searches must not reach the handler")
+		return nil, 0, nil
+	}
+
+	router := setupRegisteredRouter()
+
+	for query, field := range map[string]string{
+		"":                  "q",
+		"?q=%22*%22":        "q",
+		"?q=go&limit=101":   "limit",
+		"?q=go&limit=0":     "limit",
+		"?q=go&offset=-1":   "offset",
+		"?q=go&status=gone": "status",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/search"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), `"field":"`+field+`"`, query)
+	}
+}
@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"net/http"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// CancelEventRequest is the optional body of a cancellation, the reason is shown to registrants
+type CancelEventRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// changeEventStatus returns the handler moving an event to status, the owner and co-organizers may do it
+func changeEventStatus(status models.EventStatus) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		eventId, err := parseEventId(context)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+
+		var request CancelEventRequest
+
+		// The body is optional, and only cancellation reads it
+		if status == models.EventStatusCancelled && context.Request.ContentLength != 0 {
+			err = context.ShouldBindJSON(&request)
+			if err != nil {
+				problems.Abort(context, problems.BindError(err))
+				return
+			}
+		}
+
+		event, err := findEvent(context, eventId)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+
+		// Status can be changed by the owner and co-organizers
+		err = requireEventPermission(context, event, models.PermissionEdit)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+
+		// Reject if the client saw an older version
+		err = checkIfMatch(context, *event)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+
+		err = handlers.ChangeEventStatus(context.Request.Context(), event, status, request.Reason)
+		if err != nil {
+			problems.Abort(context, err)
+			return
+		}
+
+		context.Header("ETag", eventETag(*event))
+		context.JSON(http.StatusOK, gin.H{
+			"message": "Event " + string(status),
+			"data":    newEventResponse(*event),
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// mocks event lookup with an event of the given status owned by user 1,
+// tokens "user-1" and "user-2" authenticate as those users
+func mockEventWithStatus(t *testing.T, status models.EventStatus) {
+	originalVerifyToken := utils.VerifyToken
+	originalGetEventByID := handlers.GetEventByID
+	t.Cleanup(func() {
+		utils.VerifyToken = originalVerifyToken
+		handlers.GetEventByID = originalGetEventByID
+	})
+
+	utils.VerifyToken = func(token string) (int64, error) {
+		var userId int64
+		_, err := fmt.Sscanf(token, "user-%d", &userId)
+		if err != nil {
+			return 0, utils.ErrInvalidToken
+		}
+		return userId, nil
+	}
+
+	handlers.GetEventByID = func(ctx context.Context, eventId int64) (*models.Event, error) {
+		return &models.Event{
+				ID:          eventId,
+				Name:        "Go Workshop Jakarta",
+				Description: "A beginner-friendly workshop covering Go fundamentals and best practices.",
+				Location:    "Jakarta",
+				DateTime:    time.Date(2030, 12, 16, 9, 0, 0, 0, time.UTC),
+				UserID:      1,
+				Version:     2,
+				Status:      status,
+			},
+			nil
+	}
+
+	// User 8 co-organizes the event and user 9 checks attendees in
+	mockEventRoles(t, map[int64]models.EventRole{8: models.EventRoleCoOrganizer, 9: models.EventRoleCheckInStaff})
+	mockRSVPCounts(t)
+}
+
+func TestGetEventById_DraftOnlyVisibleToOwner(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusDraft)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"":       http.StatusNotFound,
+		"user-2": http.StatusNotFound,
+		"user-1": http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events/1", http.NoBody)
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+}
+
+func TestGetEvents_StatusFilter(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() {
+		handlers.GetAllEvents = originalGetAllEvents
+	}()
+
+	var received models.EventFilter
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+		received = filter
+		return []models.Event{}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events?status=draft,cancelled", http.NoBody)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []models.EventStatus{models.EventStatusDraft, models.EventStatusCancelled}, received.Statuses)
+	assert.Equal(t, int64(1), received.ViewerID)
+
+	// default is left to the model
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/events", http.NoBody)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, received.Statuses)
+	assert.Zero(t, received.ViewerID)
+}
+
+func TestGetEvents_ErrorInvalidStatusFilter(t *testing.T) {
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/events?status=published,archived", http.NoBody)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"status"`)
+}
+
+func TestCancelEvent_Success(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalChangeEventStatus := handlers.ChangeEventStatus
+	defer func() {
+		handlers.ChangeEventStatus = originalChangeEventStatus
+	}()
+
+	var receivedReason string
+	handlers.ChangeEventStatus = func(ctx context.Context, event *models.Event, status models.EventStatus, reason string) error {
+		receivedReason = reason
+		event.Status = status
+		event.CancellationReason = reason
+		event.Version++
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/cancel", strings.NewReader(`{"reason":"venue flooded"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-1")
+	req.Header.Set("If-Match", `"2"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "venue flooded", receivedReason)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	data := response["data"].(map[string]any)
+	assert.Equal(t, "cancelled", data["status"])
+	assert.Equal(t, "venue flooded", data["cancellationReason"])
+}
+
+func TestPublishEvent_ErrorNotOwner(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/complete", http.NoBody)
+	req.Header.Set("Authorization", "user-2")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"not_owner"`)
+}
+
+func TestPublishEvent_ErrorInvalidTransition(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusCancelled)
+	router := setupRegisteredRouter()
+
+	originalChangeEventStatus := handlers.ChangeEventStatus
+	defer func() {
+		handlers.ChangeEventStatus = originalChangeEventStatus
+	}()
+
+	handlers.ChangeEventStatus = func(ctx context.Context, event *models.Event, status models.EventStatus, reason string) error {
+		return event.ChangeStatus(ctx, status, reason)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/events/1/publish", http.NoBody)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_status_transition"`)
+}
+
+func TestCreateEvent_InitialStatus(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusDraft)
+	router := setupRegisteredRouter()
+
+	originalCreateEvent := handlers.CreateEvent
+	defer func() {
+		handlers.CreateEvent = originalCreateEvent
+	}()
+
+	var saved models.EventStatus
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
+		saved = event.Status
+		return nil
+	}
+
+	// legacy clients have no publish step
+	for path, expected := range map[string]models.EventStatus{
+		"/api/v1/events": models.EventStatusDraft,
+		"/event":         models.EventStatusPublished,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, path, toJSON(t, createEventPayload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "user-1")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code, path)
+		assert.Equal(t, expected, saved, path)
+	}
+}
+
+func TestUpdateEvent_KeepsStatus(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalUpdateEvent := handlers.UpdateEvent
+	defer func() {
+		handlers.UpdateEvent = originalUpdateEvent
+	}()
+	handlers.UpdateEvent = func(ctx context.Context, event *models.Event) error {
+		event.Version++
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/events/1", toJSON(t, updateEventPayload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "user-1")
+	req.Header.Set("If-Match", `"2"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	data := response["data"].(map[string]any)
+	assert.Equal(t, "published", data["status"])
+}
+
+func TestPatchEvent_KeepsStatus(t *testing.T) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+	router := setupRegisteredRouter()
+
+	originalPatchEvent := handlers.PatchEvent
+	defer func() {
+		handlers.PatchEvent = originalPatchEvent
+	}()
+	handlers.PatchEvent = func(ctx context.Context, event *models.Event, columns []string) error {
+		event.Version++
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/v1/events/1", strings.NewReader(`{"location": "Bandung"}`))
+	req.Header.Set("Content-Type", mergePatchContentType)
+	req.Header.Set("Authorization", "user-1")
+	req.Header.Set("If-Match", `"2"`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	data := response["data"].(map[string]any)
+	assert.Equal(t, "published", data["status"])
+	assert.Equal(t, "Bandung", data["location"])
+}
@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// Number of tags listed by default and at most, counts and autocomplete suggestions
+const (
+	defaultTagLimit        = 50
+	maxTagLimit            = 200
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+)
+
+type TagCountResponse struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func newTagCountResponses(counts []models.TagCount) []TagCountResponse {
+	responses := make([]TagCountResponse, 0, len(counts))
+	for _, c := range counts {
+		responses = append(responses, TagCountResponse{Name: c.Name, Count: c.Count})
+	}
+
+	return responses
+}
+
+// getTags counts the listed events per tag, narrowed by ?category=&tag= like GET /events for faceted navigation
+func getTags(context *gin.Context) {
+	validationErr := &models.ValidationError{}
+
+	limit := int(parsePositiveQuery(context, "limit", validationErr))
+	if limit > maxTagLimit {
+		validationErr.Add("limit", "must be at most "+strconv.Itoa(maxTagLimit))
+	}
+	if limit == 0 {
+		limit = defaultTagLimit
+	}
+
+	if len(validationErr.Fields) > 0 {
+		problems.Abort(context, validationErr)
+		return
+	}
+
+	category, tags, err := parseTagFilter(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	counts, err := handlers.GetTagCounts(context.Request.Context(), models.TagFilter{Category: category, Tags: tags, Limit: limit})
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Tags by number of events",
+		"data":    newTagCountResponses(counts),
+	})
+}
+
+// autocompleteTags suggests the most used tags starting with ?q=
+func autocompleteTags(context *gin.Context) {
+	validationErr := &models.ValidationError{}
+
+	query := context.Query("q")
+	switch {
+	case query == "":
+		validationErr.Add("q", "is required")
+	case len(query) > models.MaxTagLength:
+		validationErr.Add("q", "must be at most "+strconv.Itoa(models.MaxTagLength)+" characters")
+	}
+
+	limit := int(parsePositiveQuery(context, "limit", validationErr))
+	if limit > maxSuggestionLimit {
+		validationErr.Add("limit", "must be at most "+strconv.Itoa(maxSuggestionLimit))
+	}
+	if limit == 0 {
+		limit = defaultSuggestionLimit
+	}
+
+	if len(validationErr.Fields) > 0 {
+		problems.Abort(context, validationErr)
+		return
+	}
+
+	counts, err := handlers.GetTagCounts(context.Request.Context(), models.TagFilter{Prefix: query, Limit: limit})
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Tag suggestions",
+		"data":    newTagCountResponses(counts),
+	})
+}
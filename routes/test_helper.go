@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"example.com/event/middlewares"
+	"example.com/event/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // USER ROUTES
@@ -20,6 +22,7 @@ const GET_EVENTS_BY_ID_PATH = "/event/:eventId"
 const CREATE_EVENT_PATH = "/event"
 const UPDATE_EVENT_PATH = "/event/:eventId"
 const DELETE_EVENT_PATH = "/event/:eventId"
+const PATCH_EVENT_PATH = "/event/:eventId"
 
 // convert []byte (payload) to JSON
 func toJSON(t *testing.T, v any) *bytes.Buffer {
@@ -34,6 +37,7 @@ func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 
 	r := gin.Default()
+	r.Use(otelgin.Middleware(utils.ServiceName), middlewares.RequestID)
 
 	// define user routes
 	r.POST(SIGNUP_PATH, signUp)
@@ -45,6 +49,7 @@ func setupRouter() *gin.Engine {
 	r.POST(CREATE_EVENT_PATH, createEvent)
 	r.PUT(UPDATE_EVENT_PATH, middlewares.Authenticate, updateEvent)
 	r.DELETE(UPDATE_EVENT_PATH, middlewares.Authenticate, deleteEvent)
+	r.PATCH(PATCH_EVENT_PATH, middlewares.Authenticate, patchEvent)
 
 	return r
 }
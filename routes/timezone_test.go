@@ -0,0 +1,130 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateEvent_TimeZone(t *testing.T) {
+	router := setupRouter()
+
+	originalCreateEvent := handlers.CreateEvent
+	defer func() { handlers.CreateEvent = originalCreateEvent }()
+
+	var created models.Event
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
+		event.ID = 1
+		created = *event
+		return nil
+	}
+
+	body := `{
+		"name": "Go Workshop Jakarta",
+		"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+		"location": "Jakarta",
+		"dateTime": "2030-12-16T09:00:00+07:00",
+		"durationMinutes": 90,
+		"timeZone": "Asia/Jakarta"
+	}`
+
+	req, _ := http.NewRequest(http.MethodPost, CREATE_EVENT_PATH, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// stored as UTC instants plus the zone name
+	assert.Equal(t, time.Date(2030, 12, 16, 2, 0, 0, 0, time.UTC), created.DateTime)
+	assert.Equal(t, time.Date(2030, 12, 16, 3, 30, 0, 0, time.UTC), created.EndDateTime)
+	assert.Equal(t, "Asia/Jakarta", created.TimeZone)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	data := response["data"].(map[string]any)
+	assert.Equal(t, "2030-12-16T02:00:00Z", data["dateTime"])
+	assert.Equal(t, "2030-12-16T03:30:00Z", data["endDateTime"])
+	assert.Equal(t, "2030-12-16T09:00:00+07:00", data["localDateTime"])
+	assert.Equal(t, "2030-12-16T10:30:00+07:00", data["localEndDateTime"])
+	assert.Equal(t, "Asia/Jakarta", data["timeZone"])
+}
+
+func TestCreateEvent_ErrorInvalidSchedule(t *testing.T) {
+	router := setupRouter()
+
+	tests := map[string]string{
+		"endDateTime":     `"endDateTime": "2030-12-16T08:00:00+07:00"`,
+		"durationMinutes": `"endDateTime": "2030-12-16T10:00:00+07:00", "durationMinutes": 30`,
+		"timeZone":        `"timeZone": "Mars/Olympus_Mons"`,
+	}
+
+	for field, extra := range tests {
+		body := `{
+			"name": "Go Workshop Jakarta",
+			"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+			"location": "Jakarta",
+			"dateTime": "2030-12-16T09:00:00+07:00",
+			` + extra + `
+		}`
+
+		req, _ := http.NewRequest(http.MethodPost, CREATE_EVENT_PATH, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, field)
+		assert.Contains(t, w.Body.String(), `"field":"`+field+`"`, field)
+	}
+}
+
+func TestGetEvents_DateInRequestedZone(t *testing.T) {
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() { handlers.GetAllEvents = originalGetAllEvents }()
+
+	var filter models.EventFilter
+	handlers.GetAllEvents = func(ctx context.Context, f models.EventFilter) ([]models.Event, error) {
+		filter = f
+		return []models.Event{}, nil
+	}
+
+	router := setupRegisteredRouter()
+
+	tests := []struct {
+		query    string
+		from, to time.Time
+	}{
+		// a Jakarta day starts at 17:00 UTC the day before
+		{"?date=2031-01-07&tz=Asia/Jakarta", time.Date(2031, 1, 6, 17, 0, 0, 0, time.UTC), time.Date(2031, 1, 7, 17, 0, 0, 0, time.UTC)},
+		// a date as to includes that day
+		{"?from=2031-01-01&to=2031-01-31", time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2031, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// the day DST ends in New York lasts 25 hours
+		{"?date=2031-11-02&tz=America/New_York", time.Date(2031, 11, 2, 4, 0, 0, 0, time.UTC), time.Date(2031, 11, 3, 5, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events"+tt.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, tt.query)
+		assert.True(t, tt.from.Equal(filter.From), tt.query)
+		assert.True(t, tt.to.Equal(filter.To), tt.query)
+	}
+
+	for _, query := range []string{"?date=2031-01-07&tz=Nowhere", "?date=07/01/2031", "?date=2031-01-07&from=2031-01-01"} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/events"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
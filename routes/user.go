@@ -2,33 +2,43 @@ package routes
 
 import (
 	"net/http"
+	"strings"
 
 	"example.com/event/handlers"
 	"example.com/event/models"
+	"example.com/event/problems"
 	"github.com/gin-gonic/gin"
 )
 
+// UserRequest is the body accepted by sign up and login,
+// bcrypt only uses the first 72 bytes of a password so longer ones are rejected
+type UserRequest struct {
+	Email    string `json:"email" binding:"required,email,max=254"`
+	Password string `json:"password" binding:"required,max=72"`
+}
+
 type UserResponse struct {
 	ID    int64  `json:"id"`
 	Email string `json:"email"`
 }
 
 func signUp(context *gin.Context) {
-	var user models.User
+	var request UserRequest
 
-	err := context.ShouldBindJSON(&user)
+	err := context.ShouldBindJSON(&request)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Could not parse request",
-			"error":   err.Error()})
+		problems.Abort(context, problems.BindError(err))
 		return
 	}
 
-	err = handlers.SaveUser(&user)
+	user := models.User{
+		Email:    strings.TrimSpace(request.Email),
+		Password: request.Password,
+	}
+
+	err = handlers.SaveUser(context.Request.Context(), &user)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Could not create user",
-			"error":   err.Error()})
+		problems.Abort(context, err)
 		return
 	}
 
@@ -42,32 +52,29 @@ func signUp(context *gin.Context) {
 }
 
 func login(context *gin.Context) {
-	var user models.User
+	var request UserRequest
 
-	err := context.ShouldBindJSON(&user)
+	err := context.ShouldBindJSON(&request)
 	if err != nil {
-		context.JSON(http.StatusBadRequest, gin.H{
-			"message": "Could not parse request",
-			"error":   err.Error()})
+		problems.Abort(context, problems.BindError(err))
 		return
 	}
 
-	err = handlers.ValidateCredentials(&user)
+	user := models.User{
+		Email:    strings.TrimSpace(request.Email),
+		Password: request.Password,
+	}
+
+	err = handlers.ValidateCredentials(context.Request.Context(), &user)
 	if err != nil {
-		context.JSON(http.StatusUnauthorized, gin.H{
-			"message": "Could not authenticate user",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
-	token, err := handlers.GenerateToken(user.Email, user.ID)
+	token, err := handlers.GenerateToken(context.Request.Context(), user.Email, user.ID)
 
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Could not generate token",
-			"error":   err.Error(),
-		})
+		problems.Abort(context, err)
 		return
 	}
 
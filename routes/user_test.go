@@ -2,6 +2,7 @@ package routes
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -37,7 +38,7 @@ func TestSignUp_Success(t *testing.T) {
 	}()
 
 	// mock handlers.SaveUser
-	handlers.SaveUser = func(user *models.User) error {
+	handlers.SaveUser = func(ctx context.Context, user *models.User) error {
 		user.ID = 1 // simulate DB insert
 		return nil
 	}
@@ -90,8 +91,8 @@ func TestSignUp_ErrorShouldBindJSON(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not parse request", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "invalid_request_body", response["code"])
+	assert.NotEmpty(t, response["detail"].(string))
 }
 
 func TestSignUp_ErrorSaveUserHandler(t *testing.T) {
@@ -104,7 +105,7 @@ func TestSignUp_ErrorSaveUserHandler(t *testing.T) {
 		handlers.SaveUser = originalSaveUser
 	}()
 
-	handlers.SaveUser = func(user *models.User) error {
+	handlers.SaveUser = func(ctx context.Context, user *models.User) error {
 		return errors.New("simulate error handlers.SaveUser")
 	}
 
@@ -124,8 +125,8 @@ func TestSignUp_ErrorSaveUserHandler(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not create user", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "internal_error", response["code"])
+	assert.Equal(t, "An unexpected error occurred", response["detail"])
 }
 
 func TestLogin_Success(t *testing.T) {
@@ -142,7 +143,7 @@ func TestLogin_Success(t *testing.T) {
 	}()
 
 	// mock handlers.SaveUser
-	handlers.ValidateCredentials = func(user *models.User) error {
+	handlers.ValidateCredentials = func(ctx context.Context, user *models.User) error {
 		return nil
 	}
 
@@ -150,7 +151,7 @@ func TestLogin_Success(t *testing.T) {
 	defer func() {
 		handlers.GenerateToken = originalGenerateToken
 	}()
-	handlers.GenerateToken = func(email string, userId int64) (string, error) {
+	handlers.GenerateToken = func(ctx context.Context, email string, userId int64) (string, error) {
 		return "token", nil
 	}
 
@@ -198,8 +199,8 @@ func TestLogin_ErrorShouldBindJSON(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not parse request", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "invalid_request_body", response["code"])
+	assert.NotEmpty(t, response["detail"].(string))
 }
 
 func TestLogin_ErrorValidateCredentials(t *testing.T) {
@@ -213,8 +214,8 @@ func TestLogin_ErrorValidateCredentials(t *testing.T) {
 		handlers.ValidateCredentials = originalValidateCredentials
 	}()
 
-	handlers.ValidateCredentials = func(user *models.User) error {
-		return errors.New("simulate error handlers.ValidateCredentials")
+	handlers.ValidateCredentials = func(ctx context.Context, user *models.User) error {
+		return models.ErrInvalidCredentials
 	}
 
 	req, _ := http.NewRequest(
@@ -233,8 +234,7 @@ func TestLogin_ErrorValidateCredentials(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not authenticate user", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "invalid_credentials", response["code"])
 }
 
 func TestLogin_ErrorGenerateToken(t *testing.T) {
@@ -246,7 +246,7 @@ func TestLogin_ErrorGenerateToken(t *testing.T) {
 	defer func() {
 		handlers.ValidateCredentials = originalValidateCredentials
 	}()
-	handlers.ValidateCredentials = func(user *models.User) error {
+	handlers.ValidateCredentials = func(ctx context.Context, user *models.User) error {
 		return nil
 	}
 
@@ -254,7 +254,7 @@ func TestLogin_ErrorGenerateToken(t *testing.T) {
 	defer func() {
 		handlers.GenerateToken = originalGenerateToken
 	}()
-	handlers.GenerateToken = func(email string, userId int64) (string, error) {
+	handlers.GenerateToken = func(ctx context.Context, email string, userId int64) (string, error) {
 		return "", errors.New("simulate error handlers.GenerateToken")
 	}
 
@@ -274,6 +274,6 @@ func TestLogin_ErrorGenerateToken(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "Could not generate token", response["message"])
-	assert.NotEmpty(t, response["error"].(string))
+	assert.Equal(t, "internal_error", response["code"])
+	assert.Equal(t, "An unexpected error occurred", response["detail"])
 }
@@ -0,0 +1,138 @@
+package routes
+
+import (
+	"example.com/event/middlewares"
+	"example.com/event/models"
+	"github.com/gin-gonic/gin"
+)
+
+// registerV1Routes mounts the v1 API on router (normally the /api/v1 group)
+func registerV1Routes(router *gin.RouterGroup) {
+	// Every route is scoped to the organization of X-Organization, or of the :orgSlug path parameter
+	router.Use(middlewares.Tenant)
+
+	events := router.Group("/events")
+
+	// Public event routes, signed-in users also see their own drafts
+	events.GET("", middlewares.OptionalAuthenticate, getEvents)
+	events.GET("/calendar.ics", getEventsCalendar)
+	events.GET("/search", middlewares.OptionalAuthenticate, searchEvents)
+	events.GET("/:eventId", middlewares.OptionalAuthenticate, getEventById)
+	events.GET("/:eventId/occurrences", middlewares.OptionalAuthenticate, getOccurrences)
+	events.GET("/:eventId/calendar.ics", middlewares.OptionalAuthenticate, getEventCalendar)
+
+	// Protected event routes
+	authenticated := events.Group("", middlewares.Authenticate)
+	authenticated.POST("", createEvent)
+	// Bulk create or update from an iCalendar or CSV file
+	authenticated.POST("/import", importEvents)
+	// Writes to an existing event must send If-Match with its ETag
+	authenticated.PUT("/:eventId", middlewares.RequireIfMatch, updateEvent)
+	authenticated.PATCH("/:eventId", middlewares.RequireIfMatch, patchEvent)
+	authenticated.DELETE("/:eventId", middlewares.RequireIfMatch, deleteEvent)
+
+	// Lifecycle: draft -> published -> cancelled/completed
+	authenticated.POST("/:eventId/publish", changeEventStatus(models.EventStatusPublished))
+	authenticated.POST("/:eventId/cancel", changeEventStatus(models.EventStatusCancelled))
+	authenticated.POST("/:eventId/complete", changeEventStatus(models.EventStatusCompleted))
+
+	// Undo a delete within the retention window
+	authenticated.POST("/:eventId/restore", restoreEvent)
+
+	// Overrides of a single occurrence of a recurring event
+	authenticated.PUT("/:eventId/occurrences/:occurrenceStart", saveOccurrence)
+	authenticated.DELETE("/:eventId/occurrences/:occurrenceStart", deleteOccurrence)
+
+	// Team running the event: co-organizers edit it, check-in staff see who attends
+	authenticated.GET("/:eventId/members", getEventMembers)
+	authenticated.POST("/:eventId/members", addEventMember)
+	authenticated.DELETE("/:eventId/members/:userId", removeEventMember)
+	authenticated.POST("/:eventId/transfer", middlewares.RequireIfMatch, transferEventOwnership)
+	authenticated.GET("/:eventId/registrations", getEventRegistrations)
+
+	// Private events: who is invited, and signed links inviting whoever accepts them before they expire
+	authenticated.GET("/:eventId/invitations", getEventInvitations)
+	authenticated.POST("/:eventId/invitations", inviteToEvent)
+	authenticated.DELETE("/:eventId/invitations/:userId", revokeEventInvitation)
+	authenticated.POST("/:eventId/invitations/accept", acceptInvitation)
+	authenticated.POST("/:eventId/invitation-links", createInvitationLink)
+
+	// Registrations of the current user
+	authenticated.POST("/:eventId/registrations", registerEvent)
+	authenticated.DELETE("/:eventId/registrations/me", unregisterEvent)
+
+	// RSVPs: attendees answer going, maybe or declined, check-in records who attended, organizers follow the changes
+	authenticated.PUT("/:eventId/rsvp", updateRSVP)
+	authenticated.PUT("/:eventId/registrations/:userId/rsvp", checkInAttendee)
+	authenticated.GET("/:eventId/rsvp/history", getRSVPHistory)
+
+	users := router.Group("/users")
+
+	// Sign Up User
+	users.POST("", signUp)
+
+	// Login User
+	users.POST("/login", login)
+
+	// Calendar feed of the current user's registrations, authenticated by its own token
+	users.GET("/me/calendar.ics", getUserCalendar)
+	users.POST("/me/calendar-token", middlewares.Authenticate, rotateCalendarToken)
+	users.DELETE("/me/calendar-token", middlewares.Authenticate, revokeCalendarToken)
+
+	// TODO: GET Registration List
+
+	venues := router.Group("/venues")
+
+	// Public venue routes, signed-in users also see their own drafts among the events
+	venues.GET("", getVenues)
+	venues.GET("/:venueId", getVenueById)
+	venues.GET("/:venueId/events", middlewares.OptionalAuthenticate, getVenueEvents)
+	venues.GET("/:venueId/availability", getVenueAvailability)
+
+	// Venues are changed by whoever added them, or an admin
+	authenticatedVenues := venues.Group("", middlewares.Authenticate)
+	authenticatedVenues.POST("", createVenue)
+	authenticatedVenues.PUT("/:venueId", updateVenue)
+	authenticatedVenues.DELETE("/:venueId", deleteVenue)
+
+	categories := router.Group("/categories")
+
+	// Anyone can browse categories, only admins manage them
+	categories.GET("", getCategories)
+	categories.GET("/:categoryId", getCategoryById)
+
+	adminCategories := categories.Group("", middlewares.Authenticate, middlewares.RequireAdmin)
+	adminCategories.POST("", createCategory)
+	adminCategories.PUT("/:categoryId", updateCategory)
+	adminCategories.DELETE("/:categoryId", deleteCategory)
+
+	organizations := router.Group("/organizations")
+
+	// Anyone can see organizations and their events, members manage them
+	organizations.GET("", getOrganizations)
+	organizations.POST("", middlewares.Authenticate, createOrganization)
+
+	organization := organizations.Group("/:orgSlug")
+	organization.GET("", getOrganization)
+	organization.PUT("", middlewares.Authenticate, updateOrganization)
+	organization.GET("/members", middlewares.Authenticate, getOrganizationMembers)
+	organization.POST("/members", middlewares.Authenticate, addOrganizationMember)
+	organization.DELETE("/members/:userId", middlewares.Authenticate, removeOrganizationMember)
+
+	// The organization's listings, the same as sending X-Organization to /events, /tags and /categories
+	organization.GET("/events", middlewares.OptionalAuthenticate, getEvents)
+	organization.GET("/events/search", middlewares.OptionalAuthenticate, searchEvents)
+	organization.GET("/tags", getTags)
+	organization.GET("/categories", getCategories)
+
+	tags := router.Group("/tags")
+
+	// Tag counts for faceted navigation, and suggestions while typing a tag
+	tags.GET("", getTags)
+	tags.GET("/autocomplete", autocompleteTags)
+
+	admin := router.Group("/admin", middlewares.Authenticate, middlewares.RequireAdmin)
+
+	// Who changed what, filterable by entity and actor
+	admin.GET("/audit", getAuditLog)
+}
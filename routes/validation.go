@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"reflect"
+	"strings"
+
+	"example.com/event/models"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Register custom validation rules on gin's validator once, for the server and tests alike
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Report JSON field names (e.g. "dateTime") instead of Go field names in validation errors
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	// notblank rejects strings that are empty once surrounding whitespace is trimmed
+	v.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+
+	// tag accepts what models.NormalizeTags turns into a valid tag, slug a category slug
+	v.RegisterValidation("tag", func(fl validator.FieldLevel) bool {
+		return models.ValidTag(fl.Field().String())
+	})
+	v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return models.ValidCategorySlug(fl.Field().String())
+	})
+}
@@ -0,0 +1,372 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"example.com/event/problems"
+	"github.com/gin-gonic/gin"
+)
+
+// VenueRequest is the body accepted when creating or updating a venue
+type VenueRequest struct {
+	Name    string          `json:"name" binding:"required,notblank,max=100"`
+	Address *AddressRequest `json:"address,omitempty"`
+
+	// Both or neither must be sent
+	Latitude  *float64 `json:"latitude,omitempty" binding:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude,omitempty" binding:"omitempty,longitude"`
+
+	// Capacity is how many people the venue holds, events at the venue default to it
+	Capacity *int `json:"capacity,omitempty" binding:"omitempty,min=1,max=1000000"`
+
+	AccessibilityNotes string `json:"accessibilityNotes,omitempty" binding:"max=2000"`
+}
+
+type VenueResponse struct {
+	ID                 int64            `json:"id"`
+	Name               string           `json:"name"`
+	Address            *AddressResponse `json:"address,omitempty"`
+	Latitude           *float64         `json:"latitude,omitempty"`
+	Longitude          *float64         `json:"longitude,omitempty"`
+	Capacity           *int             `json:"capacity,omitempty"`
+	AccessibilityNotes string           `json:"accessibilityNotes,omitempty"`
+	UserID             int64            `json:"userId"`
+	CreatedAt          time.Time        `json:"createdAt"`
+	UpdatedAt          time.Time        `json:"updatedAt"`
+}
+
+func (r VenueRequest) toVenue() models.Venue {
+	return models.Venue{
+		Name:               strings.TrimSpace(r.Name),
+		Address:            r.Address.toAddress(),
+		Latitude:           r.Latitude,
+		Longitude:          r.Longitude,
+		Capacity:           r.Capacity,
+		AccessibilityNotes: strings.TrimSpace(r.AccessibilityNotes),
+	}
+}
+
+func newVenueResponse(v models.Venue) VenueResponse {
+	return VenueResponse{
+		ID:                 v.ID,
+		Name:               v.Name,
+		Address:            newAddressResponse(v.Address),
+		Latitude:           v.Latitude,
+		Longitude:          v.Longitude,
+		Capacity:           v.Capacity,
+		AccessibilityNotes: v.AccessibilityNotes,
+		UserID:             v.UserID,
+		CreatedAt:          v.CreatedAt,
+		UpdatedAt:          v.UpdatedAt,
+	}
+}
+
+// bindVenueRequest binds and validates the body, collecting every invalid field into one error
+func bindVenueRequest(context *gin.Context) (VenueRequest, error) {
+	var request VenueRequest
+
+	err := context.ShouldBindJSON(&request)
+
+	validationErr := &models.ValidationError{}
+	if err != nil {
+		err := problems.BindError(err)
+		if !errors.As(err, &validationErr) {
+			return request, err
+		}
+	}
+
+	if request.Latitude != nil && request.Longitude == nil {
+		validationErr.Add("longitude", "is required with latitude")
+	}
+	if request.Latitude == nil && request.Longitude != nil {
+		validationErr.Add("latitude", "is required with longitude")
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return request, validationErr
+	}
+
+	return request, nil
+}
+
+func getVenues(context *gin.Context) {
+	venues, err := handlers.GetAllVenues(context.Request.Context())
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	responses := make([]VenueResponse, 0, len(venues))
+	for _, v := range venues {
+		responses = append(responses, newVenueResponse(v))
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "List of venues",
+		"data":    responses,
+	})
+}
+
+func getVenueById(context *gin.Context) {
+	venue, err := findVenue(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Successfully get venue details",
+		"data":    newVenueResponse(*venue),
+	})
+}
+
+func createVenue(context *gin.Context) {
+	request, err := bindVenueRequest(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	venue := request.toVenue()
+	venue.UserID = context.GetInt64("userId")
+
+	err = handlers.CreateVenue(context.Request.Context(), &venue)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Venue created successfully",
+		"data":    newVenueResponse(venue),
+	})
+}
+
+func updateVenue(context *gin.Context) {
+	request, err := bindVenueRequest(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	venue, err := findVenue(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = requireVenueOwner(context, *venue)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	updatedVenue := request.toVenue()
+	updatedVenue.ID = venue.ID
+
+	err = handlers.UpdateVenue(context.Request.Context(), &updatedVenue)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Venue updated successfully",
+		"data":    newVenueResponse(updatedVenue),
+	})
+}
+
+func deleteVenue(context *gin.Context) {
+	venue, err := findVenue(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = requireVenueOwner(context, *venue)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	err = handlers.DeleteVenue(context.Request.Context(), venue)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Venue deleted successfully",
+	})
+}
+
+// getVenueEvents lists the events at the venue over the next year, recurring events expanded into their occurrences
+func getVenueEvents(context *gin.Context) {
+	venue, err := findVenue(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	statuses, err := parseStatusFilter(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	now := time.Now().UTC()
+
+	filter := models.EventFilter{
+		Statuses: statuses,
+		ViewerID: context.GetInt64("userId"),
+		VenueID:  venue.ID,
+		From:     now,
+		To:       now.Add(models.MaxOccurrenceWindow),
+	}
+
+	events, err := handlers.GetAllEvents(context.Request.Context(), filter)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Upcoming events at the venue",
+		"data":    newEventResponses(events),
+	})
+}
+
+// VenueAvailabilityResponse splits a window into the times the venue is booked and the free slots between them
+type VenueAvailabilityResponse struct {
+	From time.Time         `json:"from"`
+	To   time.Time         `json:"to"`
+	Busy []models.TimeSlot `json:"busy"`
+	Free []models.TimeSlot `json:"free"`
+}
+
+// getVenueAvailability lists the free slots of the venue within ?from=&to=
+func getVenueAvailability(context *gin.Context) {
+	venue, err := findVenue(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	from, to, err := parseOccurrenceWindow(context)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	if from.IsZero() {
+		problems.Abort(context, models.NewValidationError("from", "is required"))
+		return
+	}
+
+	// Rendered in UTC like every other instant, whatever offset the query used
+	from, to = from.UTC(), to.UTC()
+
+	bookings, err := handlers.GetVenueBookings(context.Request.Context(), venue.ID, from, to)
+	if err != nil {
+		problems.Abort(context, err)
+		return
+	}
+
+	// Clipped to the window, bookings may start before it or run past it
+	busy := make([]models.TimeSlot, 0, len(bookings))
+	for _, b := range bookings {
+		slot := models.TimeSlot{Start: b.DateTime, End: b.End()}
+		if slot.Start.Before(from) {
+			slot.Start = from
+		}
+		if slot.End.After(to) {
+			slot.End = to
+		}
+
+		busy = append(busy, slot)
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Availability of the venue",
+		"data": VenueAvailabilityResponse{
+			From: from,
+			To:   to,
+			Busy: busy,
+			Free: models.FreeSlots(bookings, from, to),
+		},
+	})
+}
+
+// findVenue loads the venue of the :venueId path parameter
+func findVenue(context *gin.Context) (*models.Venue, error) {
+	venueId, err := strconv.ParseInt(context.Param("venueId"), 10, 64)
+	if err != nil || venueId <= 0 {
+		return nil, models.NewValidationError("venueId", "must be a positive integer")
+	}
+
+	return handlers.GetVenueByID(context.Request.Context(), venueId)
+}
+
+// requireVenueOwner lets the user who added the venue and admins through
+func requireVenueOwner(context *gin.Context, venue models.Venue) error {
+	userId := context.GetInt64("userId")
+	if venue.UserID == userId {
+		return nil
+	}
+
+	admin, err := handlers.IsAdmin(context.Request.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	if !admin {
+		return models.ErrNotVenueOwner
+	}
+
+	return nil
+}
+
+// applyVenue fills what the event leaves out from its venue, an unknown venue is a validation error
+func applyVenue(context *gin.Context, event *models.Event) error {
+	if event.VenueID == nil {
+		return nil
+	}
+
+	venue, err := handlers.GetVenueByID(context.Request.Context(), *event.VenueID)
+	if errors.Is(err, models.ErrVenueNotFound) {
+		return models.NewValidationError("venueId", "must be the id of an existing venue")
+	}
+	if err != nil {
+		return err
+	}
+
+	venue.ApplyTo(event)
+
+	return nil
+}
+
+// allowVenueConflicts lets the writes of the request book a venue over other bookings when the organizer
+// overrides with ?allow_conflicts=true. Otherwise they fail with a *models.VenueConflictError.
+func allowVenueConflicts(context *gin.Context) error {
+	value := context.Query("allow_conflicts")
+	if value == "" {
+		return nil
+	}
+
+	allow, err := strconv.ParseBool(value)
+	if err != nil {
+		return models.NewValidationError("allow_conflicts", "must be true or false")
+	}
+
+	if allow {
+		context.Request = context.Request.WithContext(models.WithVenueConflictsAllowed(context.Request.Context()))
+	}
+
+	return nil
+}
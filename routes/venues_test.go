@@ -0,0 +1,305 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/event/handlers"
+	"example.com/event/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockVenues serves venue 5 (added by user 1, holding 120 people) and records what gets saved.
+// Tokens "user-N" authenticate as user N, user 3 is an admin.
+func mockVenues(t *testing.T) (*models.Event, *models.Venue) {
+	mockEventWithStatus(t, models.EventStatusPublished)
+
+	originalGetVenueByID := handlers.GetVenueByID
+	originalUpdateVenue := handlers.UpdateVenue
+	originalCreateEvent := handlers.CreateEvent
+	originalIsAdmin := handlers.IsAdmin
+	t.Cleanup(func() {
+		handlers.GetVenueByID = originalGetVenueByID
+		handlers.UpdateVenue = originalUpdateVenue
+		handlers.CreateEvent = originalCreateEvent
+		handlers.IsAdmin = originalIsAdmin
+	})
+
+	capacity := 120
+	handlers.GetVenueByID = func(ctx context.Context, venueId int64) (*models.Venue, error) {
+		if venueId != 5 {
+			return nil, models.ErrVenueNotFound
+		}
+
+		return &models.Venue{
+			ID:       5,
+			Name:     "Sudirman Hall",
+			Address:  models.Address{City: "Jakarta", Country: "ID"},
+			Capacity: &capacity,
+			UserID:   1,
+		}, nil
+	}
+
+	created := &models.Event{}
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
+		*created = *event
+		return nil
+	}
+
+	updated := &models.Venue{}
+	handlers.UpdateVenue = func(ctx context.Context, venue *models.Venue) error {
+		*updated = *venue
+		return nil
+	}
+
+	handlers.IsAdmin = func(ctx context.Context, userId int64) (bool, error) {
+		return userId == 3, nil
+	}
+
+	return created, updated
+}
+
+func TestCreateEvent_DefaultsFromVenue(t *testing.T) {
+	created, _ := mockVenues(t)
+	router := setupRegisteredRouter()
+
+	body := toJSON(t, map[string]any{
+		"name":        "Go Workshop Jakarta",
+		"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+		"dateTime":    "2030-12-16T09:00:00+07:00",
+		"venueId":     5,
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/events", body)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Sudirman Hall", created.Location)
+	assert.Equal(t, models.Address{City: "Jakarta", Country: "ID"}, created.Address)
+	if assert.NotNil(t, created.Capacity) {
+		assert.Equal(t, 120, *created.Capacity)
+	}
+	assert.Contains(t, w.Body.String(), `"venueId":5`)
+
+	// the event's own capacity wins over the venue's
+	body = toJSON(t, map[string]any{
+		"name":        "Go Workshop Jakarta",
+		"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+		"location":    "Sudirman Hall, room 2",
+		"dateTime":    "2030-12-16T09:00:00+07:00",
+		"venueId":     5,
+		"capacity":    30,
+	})
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/events", body)
+	req.Header.Set("Authorization", "user-1")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Sudirman Hall, room 2", created.Location)
+	assert.Equal(t, 30, *created.Capacity)
+}
+
+func TestCreateEvent_ErrorUnknownVenue(t *testing.T) {
+	mockVenues(t)
+	router := setupRegisteredRouter()
+
+	body := toJSON(t, map[string]any{
+		"name":        "Go Workshop Jakarta",
+		"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+		"dateTime":    "2030-12-16T09:00:00+07:00",
+		"venueId":     6,
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/events", body)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"venueId"`)
+}
+
+func TestUpdateVenue_OwnerOrAdmin(t *testing.T) {
+	_, updated := mockVenues(t)
+	router := setupRegisteredRouter()
+
+	for token, expected := range map[string]int{
+		"user-1": http.StatusOK,
+		"user-2": http.StatusForbidden,
+		"user-3": http.StatusOK,
+	} {
+		body := toJSON(t, map[string]any{"name": "Sudirman Hall", "capacity": 150})
+
+		req, _ := http.NewRequest(http.MethodPut, "/api/v1/venues/5", body)
+		req.Header.Set("Authorization", token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code, token)
+	}
+
+	assert.Equal(t, int64(5), updated.ID)
+	assert.Equal(t, 150, *updated.Capacity)
+}
+
+func TestCreateVenue_ErrorValidation(t *testing.T) {
+	mockVenues(t)
+	router := setupRegisteredRouter()
+
+	body := toJSON(t, map[string]any{"name": " ", "longitude": 106.8, "capacity": 0})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/venues", body)
+	req.Header.Set("Authorization", "user-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	fields := []string{}
+	for _, e := range response["errors"].([]any) {
+		fields = append(fields, e.(map[string]any)["field"].(string))
+	}
+	assert.ElementsMatch(t, []string{"name", "latitude", "capacity"}, fields)
+}
+
+func TestGetVenueEvents(t *testing.T) {
+	mockVenues(t)
+	router := setupRegisteredRouter()
+
+	originalGetAllEvents := handlers.GetAllEvents
+	defer func() {
+		handlers.GetAllEvents = originalGetAllEvents
+	}()
+
+	var got models.EventFilter
+	handlers.GetAllEvents = func(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+		got = filter
+		return []models.Event{}, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/venues/5/events", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(5), got.VenueID)
+	assert.Equal(t, models.MaxOccurrenceWindow, got.To.Sub(got.From))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/venues/6/events", http.NoBody))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"venue_not_found"`)
+}
+
+func TestCreateEvent_ErrorVenueConflict(t *testing.T) {
+	created, _ := mockVenues(t)
+	router := setupRegisteredRouter()
+
+	start := time.Date(2030, 12, 16, 2, 0, 0, 0, time.UTC)
+	handlers.CreateEvent = func(ctx context.Context, event *models.Event) error {
+		if !models.VenueConflictsAllowed(ctx) {
+			return &models.VenueConflictError{Conflicts: []models.VenueConflict{
+				{EventID: 8, Name: "Go Meetup", Start: start, End: start.Add(time.Hour)},
+				{EventID: 9, Start: start, End: start.Add(time.Hour)},
+			}}
+		}
+
+		*created = *event
+		return nil
+	}
+
+	newRequest := func(query string) *http.Request {
+		body := toJSON(t, map[string]any{
+			"name":        "Go Workshop Jakarta",
+			"description": "A beginner-friendly workshop covering Go fundamentals and best practices.",
+			"dateTime":    "2030-12-16T09:00:00+07:00",
+			"venueId":     5,
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/events"+query, body)
+		req.Header.Set("Authorization", "user-1")
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(""))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Zero(t, created.Name)
+
+	var response struct {
+		Code      string                 `json:"code"`
+		Conflicts []models.VenueConflict `json:"conflicts"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "venue_conflict", response.Code)
+	if assert.Len(t, response.Conflicts, 2) {
+		assert.Equal(t, "Go Meetup", response.Conflicts[0].Name)
+		assert.Equal(t, int64(9), response.Conflicts[1].EventID)
+	}
+
+	// the organizer books anyway
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest("?allow_conflicts=true"))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Go Workshop Jakarta", created.Name)
+}
+
+func TestGetVenueAvailability(t *testing.T) {
+	mockVenues(t)
+	router := setupRegisteredRouter()
+
+	originalGetVenueBookings := handlers.GetVenueBookings
+	defer func() {
+		handlers.GetVenueBookings = originalGetVenueBookings
+	}()
+
+	day := time.Date(2030, 12, 16, 0, 0, 0, 0, time.UTC)
+	handlers.GetVenueBookings = func(ctx context.Context, venueId int64, from, to time.Time) ([]models.Event, error) {
+		return []models.Event{
+			{DateTime: day.Add(-time.Hour), EndDateTime: day.Add(2 * time.Hour)},
+			{DateTime: day.Add(9 * time.Hour), EndDateTime: day.Add(11 * time.Hour)},
+		}, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/venues/5/availability?date=2030-12-16", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data VenueAvailabilityResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TimeSlot{
+		{Start: day.Add(2 * time.Hour), End: day.Add(9 * time.Hour)},
+		{Start: day.Add(11 * time.Hour), End: day.Add(24 * time.Hour)},
+	}, response.Data.Free)
+	assert.Equal(t, day, response.Data.Busy[0].Start)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/venues/5/availability", http.NoBody))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"from"`)
+}
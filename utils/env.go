@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"log/slog"
+	"os"
+	"time"
+)
+
+// GetEnvDuration reads a duration like "10s" or "500ms" from the environment,
+// falling back to the default when unset or invalid
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		slog.Warn("invalid duration in environment, using default", "key", key, "value", value, "default", fallback.String())
+		return fallback
+	}
+
+	return duration
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -9,6 +10,14 @@ import (
 
 var secretKey = []byte("EVENT-SECRET-KEY")
 
+var (
+	ErrMissingToken = errors.New("missing authorization token")
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrExpiredToken is an ErrInvalidToken that was valid until its expiry
+	ErrExpiredToken = fmt.Errorf("%w: token has expired", ErrInvalidToken)
+)
+
 func GenerateToken(email string, userId int64) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"email":  email,
@@ -20,6 +29,57 @@ func GenerateToken(email string, userId int64) (string, error) {
 }
 
 var VerifyToken = func(token string) (int64, error) {
+	claims, err := parseToken(token)
+	if err != nil {
+		return 0, err
+	}
+
+	// .(string) to check if the variable has string data types
+	// email := claims["email"].(string)
+	rawUserId, ok := claims["userId"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("%w: missing userId claim", ErrInvalidToken)
+	}
+
+	userId := int64(rawUserId)
+
+	return userId, nil
+}
+
+// GenerateInvitationToken signs an invitation link to the event, issued by invitedBy and usable until expiresAt.
+// It has no userId claim, so it cannot be used to authenticate.
+func GenerateInvitationToken(eventId, invitedBy int64, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"eventId":   eventId,
+		"invitedBy": invitedBy,
+		"exp":       expiresAt.Unix(),
+	})
+
+	return token.SignedString(secretKey)
+}
+
+// VerifyInvitationToken returns the event and the issuer of a token made by GenerateInvitationToken
+var VerifyInvitationToken = func(token string) (eventId, invitedBy int64, err error) {
+	claims, err := parseToken(token)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rawEventId, ok := claims["eventId"].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: missing eventId claim", ErrInvalidToken)
+	}
+
+	rawInvitedBy, ok := claims["invitedBy"].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: missing invitedBy claim", ErrInvalidToken)
+	}
+
+	return int64(rawEventId), int64(rawInvitedBy), nil
+}
+
+// parseToken checks the signature and expiry of token, returning its claims
+func parseToken(token string) (jwt.MapClaims, error) {
 	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
 		// Ensure token uses HMAC signing method
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
@@ -31,24 +91,24 @@ var VerifyToken = func(token string) (int64, error) {
 		return secretKey, nil
 	})
 
+	// The parser's reason is kept for the logs, clients only learn the token is invalid or expired
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, fmt.Errorf("%w: %w", ErrExpiredToken, err)
+	}
 	if err != nil {
-		return 0, errors.New("could not parse token")
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
 	}
 
 	// Check if token valid
 	isTokenValid := parsedToken.Valid
 	if !isTokenValid {
-		return 0, errors.New("invalid token")
+		return nil, ErrInvalidToken
 	}
 
 	claims, ok := parsedToken.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, errors.New("invalid token claims")
+		return nil, fmt.Errorf("%w: invalid token claims", ErrInvalidToken)
 	}
 
-	// .(string) to check if the variable has string data types
-	// email := claims["email"].(string)
-	userId := int64(claims["userId"].(float64))
-
-	return userId, nil
+	return claims, nil
 }
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvitationToken(t *testing.T) {
+	token, err := GenerateInvitationToken(3, 7, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	eventId, invitedBy, err := VerifyInvitationToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), eventId)
+	assert.Equal(t, int64(7), invitedBy)
+
+	// An invitation does not sign anybody in
+	_, err = VerifyToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	expired, err := GenerateInvitationToken(3, 7, time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+
+	_, _, err = VerifyInvitationToken(expired)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// Attribute keys whose values must never reach the logs
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"authorization": true,
+	"secret":        true,
+}
+
+// InitLogger sets the default slog logger to write JSON logs to stdout.
+// The level is read from LOG_LEVEL (debug, info, warn, error) and defaults to info.
+func InitLogger() {
+	slog.SetDefault(NewLogger(os.Stdout, os.Getenv("LOG_LEVEL")))
+}
+
+func NewLogger(w io.Writer, level string) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       ParseLogLevel(level),
+		ReplaceAttr: redactAttr,
+	})
+
+	return slog.New(requestIDHandler{handler})
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the id of the request being served
+func WithRequestID(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestId)
+}
+
+// RequestIDFromContext returns the request id stored by WithRequestID, empty outside a request
+func RequestIDFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIDKey{}).(string)
+	return requestId
+}
+
+// requestIDHandler adds the request id of the context to every record logged with one,
+// so application logs can be tied to the request they were written for
+type requestIDHandler struct {
+	slog.Handler
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestId := RequestIDFromContext(ctx); requestId != "" {
+		record.AddAttrs(slog.String("request_id", requestId))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{h.Handler.WithGroup(name)}
+}
+
+// ParseLogLevel converts a level name into slog.Level, falling back to info for unknown values
+func ParseLogLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// redactAttr replaces the value of sensitive attributes (in any group) before they are written
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redactedValue)
+	}
+
+	return a
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogger_RedactsSensitiveFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, "info")
+
+	logger.Info("login", "email", "test@example.com", "password", "secret123", slog.Group("headers", "Authorization", "Bearer abc"))
+
+	var entry map[string]any
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test@example.com", entry["email"])
+	assert.Equal(t, redactedValue, entry["password"])
+	assert.Equal(t, redactedValue, entry["headers"].(map[string]any)["Authorization"])
+}
+
+func TestNewLogger_RespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, "warn")
+
+	logger.Info("should be dropped")
+	assert.Empty(t, buf.String())
+
+	logger.Warn("should be written")
+	assert.NotEmpty(t, buf.String())
+}
+
+func TestNewLogger_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, "info").With("component", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-123"), "with request")
+
+	var entry map[string]any
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, "test", entry["component"])
+
+	// Logs outside a request have no request id
+	buf.Reset()
+	logger.InfoContext(context.Background(), "without request")
+
+	entry = map[string]any{}
+	err = json.Unmarshal(buf.Bytes(), &entry)
+	assert.NoError(t, err)
+	assert.NotContains(t, entry, "request_id")
+}
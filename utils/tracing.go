@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const ServiceName = "golang-event"
+
+// InitTracer configures the global OpenTelemetry tracer provider and W3C trace-context propagation.
+// The exporter is selected with OTEL_TRACES_EXPORTER:
+//   - "otlp"   sends spans over OTLP/HTTP (endpoint from OTEL_EXPORTER_OTLP_ENDPOINT)
+//   - "stdout" pretty prints spans, useful for local debugging
+//   - "none" or empty disables exporting (spans are still created and propagated)
+//
+// The returned function flushes and stops the provider, call it before the program exits.
+func InitTracer(ctx context.Context) (func(context.Context) error, error) {
+	// Accept incoming traceparent/tracestate headers and forward baggage
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+	}
+
+	switch exporterName := os.Getenv("OTEL_TRACES_EXPORTER"); exporterName {
+	case "", "none":
+		// no exporter registered
+	case "otlp":
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", exporterName)
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}